@@ -0,0 +1,73 @@
+package jsoninfo
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// JSONLibrary is the JSON implementation used to marshal and unmarshal values,
+// such as encoding/json or a compatible library like jsoniter.
+// It is set for the package with SetJSONLibrary,
+// and can be overridden per call with EncoderOptions.Library and DecoderOptions.Library.
+//
+// The implementation must honor the json.Marshaler and json.Unmarshaler interfaces
+// and the struct tags of encoding/json, which the strict structs of this module rely on.
+type JSONLibrary interface {
+	Marshal(v interface{}) ([]byte, error)
+	Unmarshal(data []byte, v interface{}) error
+	NewEncoder(w io.Writer) JSONEncoder
+	NewDecoder(r io.Reader) JSONDecoder
+}
+
+// JSONEncoder writes JSON values to a stream, like json.Encoder.
+type JSONEncoder interface {
+	Encode(v interface{}) error
+}
+
+// JSONDecoder reads JSON values from a stream, like json.Decoder.
+type JSONDecoder interface {
+	Decode(v interface{}) error
+}
+
+// StdJSONLibrary is the JSONLibrary backed by encoding/json, used by default.
+var StdJSONLibrary JSONLibrary = stdJSONLibrary{}
+
+type stdJSONLibrary struct{}
+
+func (stdJSONLibrary) Marshal(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (stdJSONLibrary) Unmarshal(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
+
+func (stdJSONLibrary) NewEncoder(w io.Writer) JSONEncoder {
+	return json.NewEncoder(w)
+}
+
+func (stdJSONLibrary) NewDecoder(r io.Reader) JSONDecoder {
+	return json.NewDecoder(r)
+}
+
+var jsonLibrary = StdJSONLibrary
+
+// SetJSONLibrary makes the package marshal and unmarshal values with impl,
+// or with StdJSONLibrary when impl is nil.
+// This covers json.Marshal and json.Unmarshal of the documents of openapi2 and openapi3,
+// whose MarshalJSON and UnmarshalJSON methods go through this package.
+// It isn't safe to call concurrently with marshalling: set it once, during initialization.
+//
+// The key order of objects, recorded with DecoderOptions.PreserveFieldOrder,
+// is always scanned with encoding/json.
+func SetJSONLibrary(impl JSONLibrary) {
+	if impl == nil {
+		impl = StdJSONLibrary
+	}
+	jsonLibrary = impl
+}
+
+// CurrentJSONLibrary returns the JSONLibrary set by SetJSONLibrary.
+func CurrentJSONLibrary() JSONLibrary {
+	return jsonLibrary
+}
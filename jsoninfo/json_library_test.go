@@ -0,0 +1,106 @@
+package jsoninfo_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"testing"
+
+	"github.com/mbilski/kin-openapi/jsoninfo"
+	"github.com/mbilski/kin-openapi/openapi2"
+	"github.com/mbilski/kin-openapi/openapi3"
+	"github.com/stretchr/testify/require"
+)
+
+// countingJSONLibrary is an alternate JSONLibrary counting its calls.
+// It decodes numbers as json.Number to differ from encoding/json.
+type countingJSONLibrary struct {
+	marshals   int
+	unmarshals int
+}
+
+func (library *countingJSONLibrary) Marshal(v interface{}) ([]byte, error) {
+	library.marshals++
+	return json.Marshal(v)
+}
+
+func (library *countingJSONLibrary) Unmarshal(data []byte, v interface{}) error {
+	library.unmarshals++
+	return library.NewDecoder(bytes.NewReader(data)).Decode(v)
+}
+
+func (library *countingJSONLibrary) NewEncoder(w io.Writer) jsoninfo.JSONEncoder {
+	return json.NewEncoder(w)
+}
+
+func (library *countingJSONLibrary) NewDecoder(r io.Reader) jsoninfo.JSONDecoder {
+	decoder := json.NewDecoder(r)
+	decoder.UseNumber()
+	return decoder
+}
+
+func TestSetJSONLibrary(t *testing.T) {
+	library := &countingJSONLibrary{}
+	jsoninfo.SetJSONLibrary(library)
+	defer jsoninfo.SetJSONLibrary(nil)
+	require.True(t, jsoninfo.CurrentJSONLibrary() == library)
+
+	t.Run("Extensions", TestExtensions)
+	t.Run("OmitEmptyExtensions", TestOmitEmptyExtensions)
+	t.Run("PreserveFieldOrder", TestPreserveFieldOrder)
+	t.Run("NewObjectDecoder", TestNewObjectDecoder)
+	t.Run("UnmarshalStrictStruct", TestUnmarshalStrictStruct)
+	t.Run("DecodeStructFieldsAndExtensions", TestDecodeStructFieldsAndExtensions)
+	t.Run("Swagger", func(t *testing.T) {
+		data, err := ioutil.ReadFile("../openapi2/testdata/swagger.json")
+		require.NoError(t, err)
+		calls := library.marshals + library.unmarshals
+		var swagger openapi2.Swagger
+		require.NoError(t, json.Unmarshal(data, &swagger))
+		result, err := json.Marshal(&swagger)
+		require.NoError(t, err)
+		require.JSONEq(t, string(data), string(result))
+		require.NotEqual(t, calls, library.marshals+library.unmarshals)
+	})
+	for _, file := range []string{"test", "testref", "testrefsinglecomponent"} {
+		path := "../openapi3/testdata/" + file + ".openapi.json"
+		t.Run(path, func(t *testing.T) {
+			data, err := ioutil.ReadFile(path)
+			require.NoError(t, err)
+			calls := library.marshals + library.unmarshals
+			var swagger openapi3.Swagger
+			require.NoError(t, json.Unmarshal(data, &swagger))
+			result, err := json.Marshal(&swagger)
+			require.NoError(t, err)
+			require.JSONEq(t, string(data), string(result))
+			require.NotEqual(t, calls, library.marshals+library.unmarshals)
+		})
+	}
+
+	jsoninfo.SetJSONLibrary(nil)
+	require.True(t, jsoninfo.CurrentJSONLibrary() == jsoninfo.StdJSONLibrary)
+}
+
+func TestJSONLibraryOptions(t *testing.T) {
+	data, err := ioutil.ReadFile("../openapi2/testdata/swagger.json")
+	require.NoError(t, err)
+
+	// The options override the library set for the package
+	library := &countingJSONLibrary{}
+	var swagger openapi2.Swagger
+	require.NoError(t, jsoninfo.UnmarshalWithOptions(data, &swagger, jsoninfo.DecoderOptions{Library: library}))
+	require.NotZero(t, library.unmarshals)
+	require.Zero(t, library.marshals)
+	result, err := jsoninfo.MarshalWithOptions(&swagger, jsoninfo.EncoderOptions{Library: library})
+	require.NoError(t, err)
+	require.NotZero(t, library.marshals)
+	require.JSONEq(t, string(data), string(result))
+
+	// Other calls use the library set for the package
+	calls := library.marshals + library.unmarshals
+	require.NoError(t, json.Unmarshal(data, &swagger))
+	_, err = json.Marshal(&swagger)
+	require.NoError(t, err)
+	require.Equal(t, calls, library.marshals+library.unmarshals)
+}
@@ -1,9 +1,12 @@
 package jsoninfo
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"io"
 	"reflect"
+	"sort"
 )
 
 // MarshalStrictStruct function:
@@ -17,27 +20,193 @@ func MarshalStrictStruct(value StrictStruct) ([]byte, error) {
 	return encoder.Bytes()
 }
 
+// MarshalWithOptions marshals a value like json.Marshal, with the options applying to
+// each StrictStruct of the value, including those held by references, pointers, maps and slices.
+// json.Marshal, and the MarshalJSON methods of StrictStructs, use the zero options.
+func MarshalWithOptions(value interface{}, opts EncoderOptions) ([]byte, error) {
+	encoder := &ObjectEncoder{Options: opts}
+	return encoder.marshal(reflect.ValueOf(value))
+}
+
+// EncoderOptions configures how an ObjectEncoder writes extensions.
+type EncoderOptions struct {
+	// OmitEmptyExtensions skips extensions whose value is null.
+	// Fixed fields are not affected.
+	OmitEmptyExtensions bool
+
+	// OmitEmptyExtensionCollections, together with OmitEmptyExtensions,
+	// also skips extensions whose value is an empty object or array.
+	OmitEmptyExtensionCollections bool
+
+	// PreserveFieldOrder writes the keys of an object in the order recorded with EncodeFieldOrder,
+	// which usually comes from decoding with DecoderOptions.PreserveFieldOrder.
+	// Keys missing from the recorded order are written after, sorted.
+	// Only objects decoded into a StrictStruct are affected: Go maps are always written sorted.
+	PreserveFieldOrder bool
+
+	// Library marshals the values, or the library set with SetJSONLibrary when nil.
+	Library JSONLibrary
+}
+
+func (opts EncoderOptions) library() JSONLibrary {
+	if opts.Library == nil {
+		return jsonLibrary
+	}
+	return opts.Library
+}
+
 type ObjectEncoder struct {
-	result map[string]json.RawMessage
+	// Options apply to the encoded object and to the values nested in it.
+	Options    EncoderOptions
+	result     map[string]json.RawMessage
+	streams    map[string]func(w io.Writer) error
+	fieldOrder []string
+	// value, when set by EncodeValue, is written instead of the object
+	value json.RawMessage
 }
 
+// NewObjectEncoder returns an encoder with the zero options.
 func NewObjectEncoder() *ObjectEncoder {
+	return NewObjectEncoderWithOptions(EncoderOptions{})
+}
+
+// NewObjectEncoderWithOptions returns an encoder with the given options.
+func NewObjectEncoderWithOptions(opts EncoderOptions) *ObjectEncoder {
 	return &ObjectEncoder{
-		result: make(map[string]json.RawMessage, 8),
+		Options: opts,
+		result:  make(map[string]json.RawMessage, 8),
 	}
 }
 
 // Bytes returns the result of encoding.
 func (encoder *ObjectEncoder) Bytes() ([]byte, error) {
-	return json.Marshal(encoder.result)
+	if encoder.value != nil {
+		return encoder.value, nil
+	}
+	if len(encoder.streams) == 0 && (!encoder.Options.PreserveFieldOrder || len(encoder.fieldOrder) == 0) {
+		return encoder.Options.library().Marshal(encoder.result)
+	}
+	var buf bytes.Buffer
+	if err := encoder.Write(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Write writes the result of encoding to w, like Bytes.
+// The values added with EncodeStream are written directly to w.
+func (encoder *ObjectEncoder) Write(w io.Writer) error {
+	if encoder.value != nil {
+		_, err := w.Write(encoder.value)
+		return err
+	}
+	if _, err := io.WriteString(w, "{"); err != nil {
+		return err
+	}
+	for i, key := range encoder.keys() {
+		if i > 0 {
+			if _, err := io.WriteString(w, ","); err != nil {
+				return err
+			}
+		}
+		// Marshal escapes and compacts like it does for the values of a map
+		keyData, err := encoder.Options.library().Marshal(key)
+		if err != nil {
+			return err
+		}
+		if _, err := w.Write(keyData); err != nil {
+			return err
+		}
+		if _, err := io.WriteString(w, ":"); err != nil {
+			return err
+		}
+		if stream, ok := encoder.streams[key]; ok {
+			if err := stream(w); err != nil {
+				return err
+			}
+			continue
+		}
+		data, err := encoder.Options.library().Marshal(encoder.result[key])
+		if err != nil {
+			return err
+		}
+		if _, err := w.Write(data); err != nil {
+			return err
+		}
+	}
+	_, err := io.WriteString(w, "}")
+	return err
+}
+
+// keys returns the keys of the result in the order they are written.
+func (encoder *ObjectEncoder) keys() []string {
+	all := make(map[string]struct{}, len(encoder.result)+len(encoder.streams))
+	for key := range encoder.result {
+		all[key] = struct{}{}
+	}
+	for key := range encoder.streams {
+		all[key] = struct{}{}
+	}
+	keys := make([]string, 0, len(all))
+	if encoder.Options.PreserveFieldOrder {
+		for _, key := range encoder.fieldOrder {
+			if _, ok := all[key]; ok {
+				delete(all, key)
+				keys = append(keys, key)
+			}
+		}
+	}
+	rest := make([]string, 0, len(all))
+	for key := range all {
+		rest = append(rest, key)
+	}
+	sort.Strings(rest)
+	return append(keys, rest...)
+}
+
+// EncodeStream adds a key whose value is written by write when the result is written.
+// It lets a large value be written without holding its encoding in memory.
+func (encoder *ObjectEncoder) EncodeStream(key string, write func(w io.Writer) error) {
+	if encoder.streams == nil {
+		encoder.streams = make(map[string]func(w io.Writer) error)
+	}
+	delete(encoder.result, key)
+	encoder.streams[key] = write
+}
+
+// EncodeValue makes the result a value instead of an object,
+// for the types written in another form in some cases, such as a string.
+func (encoder *ObjectEncoder) EncodeValue(value interface{}) error {
+	data, err := encoder.Options.library().Marshal(value)
+	if err != nil {
+		return err
+	}
+	encoder.value = data
+	return nil
+}
+
+// EncodeFieldOrder records the order in which keys are written when Options.PreserveFieldOrder is set.
+func (encoder *ObjectEncoder) EncodeFieldOrder(order []string) {
+	encoder.fieldOrder = order
 }
 
 // EncodeExtension adds a key/value to the current JSON object.
 func (encoder *ObjectEncoder) EncodeExtension(key string, value interface{}) error {
-	data, err := json.Marshal(value)
+	data, err := encoder.Options.library().Marshal(value)
 	if err != nil {
 		return err
 	}
+	if encoder.Options.OmitEmptyExtensions {
+		// Marshalled data is compact, even for a json.RawMessage
+		switch string(data) {
+		case "null":
+			return nil
+		case "{}", "[]":
+			if encoder.Options.OmitEmptyExtensionCollections {
+				return nil
+			}
+		}
+	}
 	encoder.result[key] = data
 	return nil
 }
@@ -85,7 +254,7 @@ iteration:
 
 		// Marshal
 		fieldValue := reflection.FieldByIndex(field.Index)
-		if v, ok := fieldValue.Interface().(json.Marshaler); ok {
+		if _, ok := fieldValue.Interface().(json.Marshaler); ok {
 			if fieldValue.Kind() == reflect.Ptr && fieldValue.IsNil() {
 				if field.JSONOmitEmpty {
 					continue iteration
@@ -93,7 +262,10 @@ iteration:
 				result[field.JSONName] = []byte("null")
 				continue
 			}
-			fieldData, err := v.MarshalJSON()
+			if kind := fieldValue.Kind(); field.JSONOmitEmpty && (kind == reflect.Slice || kind == reflect.Map) && fieldValue.Len() == 0 {
+				continue iteration
+			}
+			fieldData, err := encoder.marshal(fieldValue)
 			if err != nil {
 				return err
 			}
@@ -150,8 +322,7 @@ iteration:
 		}
 
 		// No special treament is needed
-		// Use plain old "encoding/json".Marshal
-		fieldData, err := json.Marshal(fieldValue.Addr().Interface())
+		fieldData, err := encoder.marshal(fieldValue)
 		if err != nil {
 			return err
 		}
@@ -160,3 +331,111 @@ iteration:
 
 	return nil
 }
+
+var marshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+
+// marshal marshals a value nested in the encoded object, honoring the options of the encoder:
+// a StrictStruct is encoded by a new encoder with the same options, and so are the ones
+// held by references, pointers, maps and slices. Other values are marshalled as they are.
+func (encoder *ObjectEncoder) marshal(value reflect.Value) ([]byte, error) {
+	switch value.Kind() {
+	case reflect.Invalid:
+		return []byte("null"), nil
+	case reflect.Ptr, reflect.Interface:
+		if value.IsNil() {
+			return []byte("null"), nil
+		}
+	}
+	// Methods have pointer receivers
+	ptr := value
+	if value.Kind() != reflect.Ptr && value.CanAddr() {
+		ptr = value.Addr()
+	}
+	if ptr.Kind() == reflect.Ptr {
+		switch v := ptr.Interface().(type) {
+		case RefStruct:
+			ref, target := v.RefFields()
+			if *ref != "" {
+				return encoder.Options.library().Marshal(&refProps{Ref: *ref})
+			}
+			return encoder.marshal(reflect.ValueOf(target).Elem())
+		case StrictStruct:
+			child := NewObjectEncoderWithOptions(encoder.Options)
+			if err := v.EncodeWith(child, v); err != nil {
+				return nil, err
+			}
+			return child.Bytes()
+		}
+	}
+	if value.Type().Implements(marshalerType) || reflect.PtrTo(value.Type()).Implements(marshalerType) {
+		return encoder.Options.library().Marshal(ptr.Interface())
+	}
+	switch value.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		return encoder.marshal(value.Elem())
+	case reflect.Map:
+		if value.Type().Key().Kind() == reflect.String {
+			return encoder.marshalMap(value)
+		}
+	case reflect.Slice:
+		if value.Type().Elem().Kind() != reflect.Uint8 {
+			if value.IsNil() {
+				return []byte("null"), nil
+			}
+			return encoder.marshalSlice(value)
+		}
+	case reflect.Array:
+		return encoder.marshalSlice(value)
+	}
+	return encoder.Options.library().Marshal(ptr.Interface())
+}
+
+// marshalMap marshals a map with string keys, sorted like json.Marshal does.
+func (encoder *ObjectEncoder) marshalMap(value reflect.Value) ([]byte, error) {
+	if value.IsNil() {
+		return []byte("null"), nil
+	}
+	keys := make([]string, 0, value.Len())
+	for _, key := range value.MapKeys() {
+		keys = append(keys, key.String())
+	}
+	sort.Strings(keys)
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, key := range keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		keyData, err := encoder.Options.library().Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(keyData)
+		buf.WriteByte(':')
+		data, err := encoder.marshal(value.MapIndex(reflect.ValueOf(key).Convert(value.Type().Key())))
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(data)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// marshalSlice marshals the elements of a slice or an array.
+func (encoder *ObjectEncoder) marshalSlice(value reflect.Value) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('[')
+	for i := 0; i < value.Len(); i++ {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		data, err := encoder.marshal(value.Index(i))
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(data)
+	}
+	buf.WriteByte(']')
+	return buf.Bytes(), nil
+}
@@ -1,28 +1,31 @@
 package jsoninfo
 
-import (
-	"encoding/json"
-)
+// RefStruct is implemented by the references marshalled with MarshalRef and unmarshalled
+// with UnmarshalRef, so that MarshalWithOptions and UnmarshalWithOptions reach their value.
+type RefStruct interface {
+	// RefFields returns pointers to the $ref of the reference and to its value.
+	RefFields() (ref *string, value interface{})
+}
 
 func MarshalRef(value string, otherwise interface{}) ([]byte, error) {
 	if len(value) > 0 {
-		return json.Marshal(&refProps{
+		return jsonLibrary.Marshal(&refProps{
 			Ref: value,
 		})
 	}
-	return json.Marshal(otherwise)
+	return jsonLibrary.Marshal(otherwise)
 }
 
 func UnmarshalRef(data []byte, destRef *string, destOtherwise interface{}) error {
 	refProps := &refProps{}
-	if err := json.Unmarshal(data, refProps); err == nil {
+	if err := jsonLibrary.Unmarshal(data, refProps); err == nil {
 		ref := refProps.Ref
 		if len(ref) > 0 {
 			*destRef = ref
 			return nil
 		}
 	}
-	return json.Unmarshal(data, destOtherwise)
+	return jsonLibrary.Unmarshal(data, destOtherwise)
 }
 
 type refProps struct {
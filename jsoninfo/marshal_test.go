@@ -7,6 +7,7 @@ import (
 
 	"github.com/mbilski/kin-openapi/jsoninfo"
 	"github.com/mbilski/kin-openapi/openapi3"
+	"github.com/stretchr/testify/require"
 )
 
 type Simple struct {
@@ -187,3 +188,78 @@ func TestExtensions(t *testing.T) {
 		}
 	}
 }
+
+type ExtendedWithPtr struct {
+	openapi3.ExtensionProps
+	Ptr *string `json:"ptr"`
+}
+
+func TestOmitEmptyExtensions(t *testing.T) {
+	value := &ExtendedWithPtr{}
+	err := jsoninfo.UnmarshalStrictStruct([]byte(`{"x-null":null,"x-empty":{},"x-list":[],"x-value":1}`), value)
+	require.NoError(t, err)
+	value.Extensions["x-nil"] = nil
+
+	data, err := jsoninfo.MarshalStrictStruct(value)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"ptr":null,"x-null":null,"x-nil":null,"x-empty":{},"x-list":[],"x-value":1}`, string(data))
+
+	data, err = jsoninfo.MarshalWithOptions(value, jsoninfo.EncoderOptions{OmitEmptyExtensions: true})
+	require.NoError(t, err)
+	require.JSONEq(t, `{"ptr":null,"x-empty":{},"x-list":[],"x-value":1}`, string(data))
+
+	data, err = jsoninfo.MarshalWithOptions(value, jsoninfo.EncoderOptions{OmitEmptyExtensions: true, OmitEmptyExtensionCollections: true})
+	require.NoError(t, err)
+	require.JSONEq(t, `{"ptr":null,"x-value":1}`, string(data))
+
+	// The options apply to the nested values, including those held by references
+	nested := map[string]interface{}{
+		"schema": &openapi3.SchemaRef{Value: &openapi3.Schema{
+			ExtensionProps: openapi3.ExtensionProps{Extensions: map[string]interface{}{"x-nil": nil}},
+			Items: &openapi3.SchemaRef{Value: &openapi3.Schema{
+				ExtensionProps: openapi3.ExtensionProps{Extensions: map[string]interface{}{"x-nil": nil}},
+			}},
+		}},
+		"values": []*ExtendedWithPtr{value},
+	}
+	data, err = jsoninfo.MarshalWithOptions(nested, jsoninfo.EncoderOptions{OmitEmptyExtensions: true})
+	require.NoError(t, err)
+	require.JSONEq(t, `{"schema":{"items":{}},"values":[{"ptr":null,"x-empty":{},"x-list":[],"x-value":1}]}`, string(data))
+}
+
+func TestPreserveFieldOrder(t *testing.T) {
+	data := []byte(`{"version":"1.0","title":"MyAPI","x-audience":"public","description":"An API"}`)
+
+	var info openapi3.Info
+	require.NoError(t, json.Unmarshal(data, &info))
+	require.Nil(t, info.FieldOrder)
+	result, err := json.Marshal(&info)
+	require.NoError(t, err)
+	require.JSONEq(t, string(data), string(result))
+	require.NotEqual(t, string(data), string(result))
+
+	decoderOptions := jsoninfo.DecoderOptions{PreserveFieldOrder: true}
+	info = openapi3.Info{}
+	require.NoError(t, jsoninfo.UnmarshalWithOptions(data, &info, decoderOptions))
+	require.Equal(t, []string{"version", "title", "x-audience", "description"}, info.FieldOrder)
+	encoderOptions := jsoninfo.EncoderOptions{PreserveFieldOrder: true}
+	result, err = jsoninfo.MarshalWithOptions(&info, encoderOptions)
+	require.NoError(t, err)
+	require.Equal(t, string(data), string(result))
+
+	// New fields go last
+	info.TermsOfService = "https://example.com/terms"
+	result, err = jsoninfo.MarshalWithOptions(&info, encoderOptions)
+	require.NoError(t, err)
+	require.Equal(t, `{"version":"1.0","title":"MyAPI","x-audience":"public","description":"An API","termsOfService":"https://example.com/terms"}`, string(result))
+
+	// The options apply to nested values
+	data = []byte(`{"openapi":"3.0.0","info":{"version":"1.0","title":"MyAPI"},"paths":{"/pets":{"get":{"responses":{"200":{"description":"OK"}},"summary":"List"}}},"components":{"schemas":{"Pet":{"type":"object","title":"Pet"},"Ref":{"$ref":"#/components/schemas/Pet"}}}}`)
+	var swagger openapi3.Swagger
+	require.NoError(t, jsoninfo.UnmarshalWithOptions(data, &swagger, decoderOptions))
+	require.Equal(t, []string{"version", "title"}, swagger.Info.FieldOrder)
+	require.Equal(t, []string{"responses", "summary"}, swagger.Paths["/pets"].Get.FieldOrder)
+	require.Equal(t, []string{"type", "title"}, swagger.Components.Schemas["Pet"].Value.FieldOrder)
+	require.Equal(t, "#/components/schemas/Pet", swagger.Components.Schemas["Ref"].Ref)
+	require.Nil(t, swagger.Components.Schemas["Ref"].Value)
+}
@@ -1,6 +1,7 @@
 package jsoninfo
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"reflect"
@@ -17,20 +18,97 @@ func UnmarshalStrictStruct(data []byte, value StrictStruct) error {
 	return value.DecodeWith(decoder, value)
 }
 
+// UnmarshalWithOptions unmarshals data into the value pointed at like json.Unmarshal,
+// with the options applying to each StrictStruct of the value, including those held by
+// references, pointers, maps and slices.
+// json.Unmarshal, and the UnmarshalJSON methods of StrictStructs, use the zero options.
+func UnmarshalWithOptions(data []byte, value interface{}, opts DecoderOptions) error {
+	ptr := reflect.ValueOf(value)
+	if ptr.Kind() != reflect.Ptr || ptr.IsNil() {
+		return &json.InvalidUnmarshalError{Type: reflect.TypeOf(value)}
+	}
+	decoder := &ObjectDecoder{Options: opts}
+	return decoder.unmarshal(data, ptr)
+}
+
+// DecoderOptions configures ObjectDecoder.
+type DecoderOptions struct {
+	// PreserveFieldOrder records the order of the keys of each decoded object,
+	// so that EncoderOptions.PreserveFieldOrder can write them back in the same order.
+	// This costs an additional scan of every object.
+	PreserveFieldOrder bool
+
+	// Library unmarshals the values, or the library set with SetJSONLibrary when nil.
+	// The key order is always scanned with encoding/json.
+	Library JSONLibrary
+}
+
+func (opts DecoderOptions) library() JSONLibrary {
+	if opts.Library == nil {
+		return jsonLibrary
+	}
+	return opts.Library
+}
+
 type ObjectDecoder struct {
-	Data            []byte
+	Data []byte
+	// Options apply to the decoded object and to the values nested in it.
+	Options         DecoderOptions
 	remainingFields map[string]json.RawMessage
+	fieldOrder      []string
 }
 
+// NewObjectDecoder returns a decoder of data with the zero options.
 func NewObjectDecoder(data []byte) (*ObjectDecoder, error) {
+	return NewObjectDecoderWithOptions(data, DecoderOptions{})
+}
+
+// NewObjectDecoderWithOptions returns a decoder of data with the given options.
+func NewObjectDecoderWithOptions(data []byte, opts DecoderOptions) (*ObjectDecoder, error) {
 	var remainingFields map[string]json.RawMessage
-	if err := json.Unmarshal(data, &remainingFields); err != nil {
+	if err := opts.library().Unmarshal(data, &remainingFields); err != nil {
 		return nil, fmt.Errorf("Failed to unmarshal extension properties: %v\nInput: %s", err, data)
 	}
-	return &ObjectDecoder{
+	decoder := &ObjectDecoder{
 		Data:            data,
+		Options:         opts,
 		remainingFields: remainingFields,
-	}, nil
+	}
+	if opts.PreserveFieldOrder {
+		fieldOrder, err := objectKeys(data)
+		if err != nil {
+			return nil, err
+		}
+		decoder.fieldOrder = fieldOrder
+	}
+	return decoder, nil
+}
+
+// objectKeys returns the keys of a JSON object in the order they appear.
+func objectKeys(data []byte) ([]string, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	if _, err := decoder.Token(); err != nil {
+		return nil, err
+	}
+	var keys []string
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, token.(string))
+		var value json.RawMessage
+		if err := decoder.Decode(&value); err != nil {
+			return nil, err
+		}
+	}
+	return keys, nil
+}
+
+// DecodeFieldOrder returns the keys of the object in source order,
+// or nil unless Options.PreserveFieldOrder is set.
+func (decoder *ObjectDecoder) DecodeFieldOrder() []string {
+	return decoder.fieldOrder
 }
 
 // DecodeExtensionMap returns all properties that were not decoded previously.
@@ -80,7 +158,7 @@ func (decoder *ObjectDecoder) DecodeStructFieldsAndExtensions(value interface{})
 				isPtr = true
 			}
 			fieldValue := reflect.New(fieldType)
-			if err := fieldValue.Interface().(json.Unmarshaler).UnmarshalJSON(fieldData); err != nil {
+			if err := decoder.unmarshal(fieldData, fieldValue); err != nil {
 				if field.MultipleFields {
 					i := fieldIndex + 1
 					if i < len(fields) && fields[i].JSONName == field.JSONName {
@@ -102,7 +180,7 @@ func (decoder *ObjectDecoder) DecodeStructFieldsAndExtensions(value interface{})
 			if fieldPtr.Kind() != reflect.Ptr || fieldPtr.IsNil() {
 				fieldPtr = fieldPtr.Addr()
 			}
-			if err := json.Unmarshal(fieldData, fieldPtr.Interface()); err != nil {
+			if err := decoder.unmarshal(fieldData, fieldPtr); err != nil {
 				if field.MultipleFields {
 					i := fieldIndex + 1
 					if i < len(fields) && fields[i].JSONName == field.JSONName {
@@ -119,3 +197,76 @@ func (decoder *ObjectDecoder) DecodeStructFieldsAndExtensions(value interface{})
 	}
 	return nil
 }
+
+var unmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+
+// unmarshal unmarshals data nested in the decoded object into the value ptr points at,
+// honoring the options of the decoder: a StrictStruct decoded from an object is decoded
+// by a new decoder with the same options, and so are the ones held by references,
+// pointers, maps and slices. Other values are unmarshalled as they are.
+func (decoder *ObjectDecoder) unmarshal(data []byte, ptr reflect.Value) error {
+	switch v := ptr.Interface().(type) {
+	case RefStruct:
+		ref, target := v.RefFields()
+		refProps := &refProps{}
+		if err := decoder.Options.library().Unmarshal(data, refProps); err == nil && refProps.Ref != "" {
+			*ref = refProps.Ref
+			return nil
+		}
+		return decoder.unmarshal(data, reflect.ValueOf(target))
+	case StrictStruct:
+		// Other JSON values are left to the UnmarshalJSON method, if any
+		if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+			child, err := NewObjectDecoderWithOptions(data, decoder.Options)
+			if err != nil {
+				return err
+			}
+			return v.DecodeWith(child, v)
+		}
+	}
+	target := ptr.Elem()
+	if ptr.Type().Implements(unmarshalerType) || bytes.Equal(bytes.TrimSpace(data), []byte("null")) {
+		return decoder.Options.library().Unmarshal(data, ptr.Interface())
+	}
+	switch target.Kind() {
+	case reflect.Ptr:
+		if target.IsNil() {
+			target.Set(reflect.New(target.Type().Elem()))
+		}
+		return decoder.unmarshal(data, target)
+	case reflect.Map:
+		if target.Type().Key().Kind() == reflect.String {
+			var items map[string]json.RawMessage
+			if err := decoder.Options.library().Unmarshal(data, &items); err != nil {
+				// The error of the JSON library names the type of the map
+				return decoder.Options.library().Unmarshal(data, ptr.Interface())
+			}
+			result := reflect.MakeMapWithSize(target.Type(), len(items))
+			for key, item := range items {
+				value := reflect.New(target.Type().Elem())
+				if err := decoder.unmarshal(item, value); err != nil {
+					return err
+				}
+				result.SetMapIndex(reflect.ValueOf(key).Convert(target.Type().Key()), value.Elem())
+			}
+			target.Set(result)
+			return nil
+		}
+	case reflect.Slice:
+		if target.Type().Elem().Kind() != reflect.Uint8 {
+			var items []json.RawMessage
+			if err := decoder.Options.library().Unmarshal(data, &items); err != nil {
+				return decoder.Options.library().Unmarshal(data, ptr.Interface())
+			}
+			result := reflect.MakeSlice(target.Type(), len(items), len(items))
+			for i, item := range items {
+				if err := decoder.unmarshal(item, result.Index(i).Addr()); err != nil {
+					return err
+				}
+			}
+			target.Set(result)
+			return nil
+		}
+	}
+	return decoder.Options.library().Unmarshal(data, ptr.Interface())
+}
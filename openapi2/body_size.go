@@ -0,0 +1,301 @@
+package openapi2
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"mime"
+	"net/url"
+	"sort"
+
+	"github.com/mbilski/kin-openapi/openapi3"
+)
+
+// Sizes used by MaxBodyBytesHint, in bytes.
+const (
+	// "-9223372036854775808"
+	maxIntegerBytes = 20
+	// "-2.2250738585072014e-308"
+	maxNumberBytes = 24
+	// "false"
+	maxBooleanBytes = 5
+	// "null"
+	maxNullBytes = 4
+	// The longest escape of a character in a JSON string, the surrogate pair of
+	// a character outside the Basic Multilingual Plane such as "\ud83d\ude00"
+	maxEscapedCharBytes = 12
+	// The longest UTF-8 encoding of a character
+	maxUTF8CharBytes = 4
+	// The percent-encoding of a byte in a form value, such as "%3D"
+	maxFormByteBytes = 3
+)
+
+// MaxBodyBytesHint returns an upper bound of the size of the request body of an operation,
+// derived from the "maxLength", "maxItems" and "maxProperties" of its body or formData
+// parameters, including those inherited from the path item of swagger.Paths at path.
+// It returns false when the body is unbounded, and 0 for an operation without body or formData parameters.
+//
+// The bound is a best-effort hint, such as a default limit for a gateway, not a guarantee:
+//   - A JSON body is assumed to be written without whitespace between tokens,
+//     with numbers in their shortest form and non-ASCII characters in UTF-8.
+//     A string may have every character escaped, such as "\u003c" for "<",
+//     or a surrogate pair such as "\ud83d\ude00" for a character outside the Basic Multilingual Plane.
+//   - An integer is assumed to fit 64 bits, and a number to be a float64.
+//   - An object is only bounded with "additionalProperties": false.
+//   - Form data is bounded as "application/x-www-form-urlencoded", with every byte
+//     percent-encoded. A multipart body, or a file parameter, is unbounded.
+//   - A recursive schema, or a schema without a type or an enum, is unbounded.
+func (operation *Operation) MaxBodyBytesHint(swagger *Swagger, path string) (int64, bool) {
+	parameters, err := swagger.effectiveParameters(swagger.Paths[path], operation)
+	if err != nil {
+		return 0, false
+	}
+	var formData Parameters
+	for _, parameter := range parameters {
+		switch parameter.In {
+		case "body":
+			if parameter.Schema == nil {
+				return 0, false
+			}
+			return swagger.maxJSONBytes(parameter.Schema, make(map[*openapi3.Schema]bool))
+		case "formData":
+			formData = append(formData, parameter)
+		}
+	}
+	if len(formData) == 0 {
+		return 0, true
+	}
+	consumes := operation.Consumes
+	if len(consumes) == 0 {
+		consumes = swagger.Consumes
+	}
+	for _, item := range consumes {
+		if mediaType, _, err := mime.ParseMediaType(item); err == nil && mediaType == "multipart/form-data" {
+			return 0, false
+		}
+	}
+	// The "&" separators
+	total := int64(len(formData) - 1)
+	for _, parameter := range formData {
+		size, ok := parameter.maxFormBytes()
+		if !ok {
+			return 0, false
+		}
+		if total, ok = addBounds(total, size); !ok {
+			return 0, false
+		}
+	}
+	return total, true
+}
+
+// maxJSONBytes returns an upper bound of the size of a JSON value of a schema.
+// visited holds the schemas being bounded, which make a recursive schema unbounded.
+func (swagger *Swagger) maxJSONBytes(schemaRef *openapi3.SchemaRef, visited map[*openapi3.Schema]bool) (int64, bool) {
+	resolved, err := swagger.resolveSchemaRef(schemaRef)
+	if err != nil || resolved == nil || resolved.Value == nil {
+		return 0, false
+	}
+	schema := resolved.Value
+	if visited[schema] {
+		return 0, false
+	}
+	visited[schema] = true
+	defer delete(visited, schema)
+
+	size, ok := swagger.maxJSONTypeBytes(schema, visited)
+	for _, member := range schema.AllOf {
+		// The value satisfies every member, so any bound of a member bounds it
+		if memberSize, memberOK := swagger.maxJSONBytes(member, visited); memberOK && (!ok || memberSize < size) {
+			size, ok = memberSize, true
+		}
+	}
+	for _, members := range [][]*openapi3.SchemaRef{schema.AnyOf, schema.OneOf} {
+		if len(members) == 0 {
+			continue
+		}
+		// The value satisfies one of the members, so they must all be bounded
+		var union int64
+		unionOK := true
+		for _, member := range members {
+			memberSize, memberOK := swagger.maxJSONBytes(member, visited)
+			if !memberOK {
+				unionOK = false
+				break
+			}
+			if memberSize > union {
+				union = memberSize
+			}
+		}
+		if unionOK && (!ok || union < size) {
+			size, ok = union, true
+		}
+	}
+	if ok && schema.Nullable && size < maxNullBytes {
+		size = maxNullBytes
+	}
+	return size, ok
+}
+
+// maxJSONTypeBytes returns an upper bound of the size of a JSON value of a schema,
+// from its enum or its type, ignoring its allOf, anyOf and oneOf.
+func (swagger *Swagger) maxJSONTypeBytes(schema *openapi3.Schema, visited map[*openapi3.Schema]bool) (int64, bool) {
+	if len(schema.Enum) > 0 {
+		var size int64
+		for _, value := range schema.Enum {
+			data, err := json.Marshal(value)
+			if err != nil {
+				return 0, false
+			}
+			if int64(len(data)) > size {
+				size = int64(len(data))
+			}
+		}
+		return size, true
+	}
+	switch schema.Type {
+	case "boolean":
+		return maxBooleanBytes, true
+	case "integer":
+		return maxIntegerBytes, true
+	case "number":
+		return maxNumberBytes, true
+	case "string":
+		if schema.MaxLength == nil {
+			return 0, false
+		}
+		// The quotes, and the escaped characters
+		return mulBounds(*schema.MaxLength, maxEscapedCharBytes, 2)
+	case "array":
+		if schema.MaxItems == nil || schema.Items == nil {
+			return 0, false
+		}
+		item, ok := swagger.maxJSONBytes(schema.Items, visited)
+		if !ok {
+			return 0, false
+		}
+		// The brackets, and each item followed by a comma
+		return mulBounds(*schema.MaxItems, item+1, 2)
+	case "object":
+		if schema.AdditionalPropertiesAllowed == nil || *schema.AdditionalPropertiesAllowed {
+			return 0, false
+		}
+		if schema.AdditionalProperties != nil {
+			return 0, false
+		}
+		sizes := make([]int64, 0, len(schema.Properties))
+		for name, property := range schema.Properties {
+			value, ok := swagger.maxJSONBytes(property, visited)
+			if !ok {
+				return 0, false
+			}
+			key, err := json.Marshal(name)
+			if err != nil {
+				return 0, false
+			}
+			// The key, a colon, the value and a comma
+			size, ok := addBounds(int64(len(key))+2, value)
+			if !ok {
+				return 0, false
+			}
+			sizes = append(sizes, size)
+		}
+		if max := schema.MaxProps; max != nil && uint64(len(sizes)) > *max {
+			// Only the largest properties may be present together
+			sort.Slice(sizes, func(i, j int) bool { return sizes[i] > sizes[j] })
+			sizes = sizes[:*max]
+		}
+		// The braces
+		total := int64(2)
+		for _, size := range sizes {
+			var ok bool
+			if total, ok = addBounds(total, size); !ok {
+				return 0, false
+			}
+		}
+		return total, true
+	}
+	return 0, false
+}
+
+// maxFormBytes returns an upper bound of the size of the form fields of a formData parameter,
+// such as "tag=a&tag=b" for a "multi" array.
+func (parameter *Parameter) maxFormBytes() (int64, bool) {
+	name := int64(len(url.QueryEscape(parameter.Name)))
+	if parameter.Type == "array" && parameter.CollectionFormat == "multi" {
+		if parameter.MaxItems == nil {
+			return 0, false
+		}
+		items := parameter.itemsParameter()
+		if items == nil {
+			return 0, false
+		}
+		item, ok := items.maxFormValueBytes()
+		if !ok {
+			return 0, false
+		}
+		// Each field followed by a "&"
+		return mulBounds(*parameter.MaxItems, name+1+item+1, 0)
+	}
+	value, ok := parameter.maxFormValueBytes()
+	if !ok {
+		return 0, false
+	}
+	return addBounds(name+1, value)
+}
+
+// maxFormValueBytes returns an upper bound of the size of a percent-encoded form value of a parameter.
+func (parameter *Parameter) maxFormValueBytes() (int64, bool) {
+	if len(parameter.Enum) > 0 {
+		var size int64
+		for _, value := range parameter.Enum {
+			if escaped := int64(len(url.QueryEscape(fmt.Sprint(value)))); escaped > size {
+				size = escaped
+			}
+		}
+		return size, true
+	}
+	switch parameter.Type {
+	case "boolean":
+		return maxBooleanBytes, true
+	case "integer":
+		return maxIntegerBytes, true
+	case "number":
+		return maxFormByteBytes * maxNumberBytes, true
+	case "string":
+		if parameter.MaxLength == nil {
+			return 0, false
+		}
+		return mulBounds(*parameter.MaxLength, maxFormByteBytes*maxUTF8CharBytes, 0)
+	case "array":
+		if parameter.MaxItems == nil {
+			return 0, false
+		}
+		items := parameter.itemsParameter()
+		if items == nil {
+			return 0, false
+		}
+		item, ok := items.maxFormValueBytes()
+		if !ok {
+			return 0, false
+		}
+		// Each item followed by an encoded separator
+		return mulBounds(*parameter.MaxItems, item+maxFormByteBytes, 0)
+	}
+	return 0, false
+}
+
+// addBounds returns a+b, or false when it overflows.
+func addBounds(a, b int64) (int64, bool) {
+	if a > math.MaxInt64-b {
+		return 0, false
+	}
+	return a + b, true
+}
+
+// mulBounds returns count*size+extra, or false when it overflows.
+func mulBounds(count uint64, size int64, extra int64) (int64, bool) {
+	if size > 0 && count > uint64(math.MaxInt64/size) {
+		return 0, false
+	}
+	return addBounds(int64(count)*size, extra)
+}
@@ -0,0 +1,74 @@
+package openapi2_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMaxBodyBytesHint(t *testing.T) {
+	swagger := loadSwagger(t, `
+{
+  "info": {"title": "MyAPI", "version": "0.1"},
+  "paths": {
+    "/pets": {
+      "post": {
+        "parameters": [{"in": "body", "name": "pet", "schema": {"$ref": "#/definitions/Pet"}}],
+        "responses": {"201": {"description": "created"}}
+      },
+      "put": {
+        "parameters": [{"in": "body", "name": "pet", "schema": {"type": "object", "properties": {"name": {"type": "string"}}}}],
+        "responses": {"200": {"description": "updated"}}
+      },
+      "get": {
+        "responses": {"200": {"description": "list"}}
+      }
+    },
+    "/pets/{id}/tags": {
+      "parameters": [{"in": "formData", "name": "tag", "type": "string", "maxLength": 4}],
+      "post": {
+        "consumes": ["application/x-www-form-urlencoded"],
+        "responses": {"201": {"description": "tagged"}}
+      },
+      "put": {
+        "consumes": ["multipart/form-data"],
+        "responses": {"200": {"description": "tagged"}}
+      }
+    }
+  },
+  "definitions": {
+    "Pet": {
+      "type": "object",
+      "additionalProperties": false,
+      "properties": {
+        "name": {"type": "string", "maxLength": 10},
+        "vaccinated": {"type": "boolean"},
+        "tags": {"type": "array", "maxItems": 2, "items": {"type": "string", "enum": ["cat", "dog"]}}
+      }
+    }
+  }
+}`)
+	pets := swagger.Paths["/pets"]
+
+	size, ok := pets.Post.MaxBodyBytesHint(swagger, "/pets")
+	require.True(t, ok)
+	// {"name":<122>,"vaccinated":<5>,"tags":<2+2*(5+1)>,}
+	require.Equal(t, int64(2+(6+2+122)+(12+2+5)+(6+2+14)), size)
+
+	// A string without maxLength, in an object accepting any property
+	_, ok = pets.Put.MaxBodyBytesHint(swagger, "/pets")
+	require.False(t, ok)
+
+	size, ok = pets.Get.MaxBodyBytesHint(swagger, "/pets")
+	require.True(t, ok)
+	require.Equal(t, int64(0), size)
+
+	tags := swagger.Paths["/pets/{id}/tags"]
+	size, ok = tags.Post.MaxBodyBytesHint(swagger, "/pets/{id}/tags")
+	require.True(t, ok)
+	// tag=<4 characters of 12 bytes>
+	require.Equal(t, int64(4+48), size)
+
+	_, ok = tags.Put.MaxBodyBytesHint(swagger, "/pets/{id}/tags")
+	require.False(t, ok)
+}
@@ -0,0 +1,86 @@
+package openapi2
+
+import (
+	"strings"
+
+	"github.com/mbilski/kin-openapi/openapi3"
+)
+
+// RouteDef describes a route of a service, for BuildSwagger.
+type RouteDef struct {
+	// Method is an HTTP method such as "GET".
+	Method string
+	// Path is a path template such as "/pets/{id}".
+	Path        string
+	OperationID string
+	Summary     string
+	Parameters  Parameters
+	// Responses are the operation responses by status code.
+	// A "200" response is declared when there are none.
+	Responses map[string]*Response
+}
+
+// BuildSwagger returns a skeleton document with an operation for each route,
+// to be enriched afterwards.
+//
+// The document passes Swagger.Validate as long as info and the route parameters are valid:
+// operations without responses get a "200" response, and the variables of a path template
+// that the route doesn't declare get a required "string" path parameter.
+// It panics on an unsupported HTTP method, like Swagger.AddOperation.
+func BuildSwagger(info openapi3.Info, routes []RouteDef) *Swagger {
+	swagger := &Swagger{Info: info}
+	for _, route := range routes {
+		operation := &Operation{
+			OperationID: route.OperationID,
+			Summary:     route.Summary,
+			Parameters:  append(Parameters{}, route.Parameters...),
+			Responses:   route.Responses,
+		}
+		for _, name := range pathVariableNames(route.Path) {
+			if !declaresPathParameter(route.Parameters, name) {
+				operation.Parameters = append(operation.Parameters, &Parameter{
+					In:       "path",
+					Name:     name,
+					Type:     "string",
+					Required: true,
+				})
+			}
+		}
+		if len(operation.Parameters) == 0 {
+			operation.Parameters = nil
+		}
+		if len(operation.Responses) == 0 {
+			operation.Responses = map[string]*Response{
+				"200": {Description: "OK"},
+			}
+		}
+		swagger.AddOperation(route.Path, strings.ToUpper(route.Method), operation)
+	}
+	return swagger
+}
+
+// pathVariableNames returns the names of the variables of a path template, such as "id" for "/pets/{id}".
+func pathVariableNames(path string) []string {
+	var names []string
+	for {
+		start := strings.IndexByte(path, '{')
+		if start < 0 {
+			return names
+		}
+		end := strings.IndexByte(path[start:], '}')
+		if end < 0 {
+			return names
+		}
+		names = append(names, path[start+1:start+end])
+		path = path[start+end+1:]
+	}
+}
+
+func declaresPathParameter(parameters Parameters, name string) bool {
+	for _, parameter := range parameters {
+		if parameter != nil && parameter.In == "path" && parameter.Name == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,41 @@
+package openapi2_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mbilski/kin-openapi/openapi2"
+	"github.com/mbilski/kin-openapi/openapi3"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildSwagger(t *testing.T) {
+	swagger := openapi2.BuildSwagger(openapi3.Info{Title: "Pets", Version: "1.0"}, []openapi2.RouteDef{
+		{
+			Method:      "get",
+			Path:        "/pets",
+			OperationID: "listPets",
+			Parameters: openapi2.Parameters{
+				{In: "query", Name: "limit", Type: "integer"},
+			},
+		},
+		{
+			Method: "DELETE",
+			Path:   "/pets/{id}",
+			Responses: map[string]*openapi2.Response{
+				"204": {Description: "Deleted"},
+			},
+		},
+	})
+	require.NoError(t, swagger.Validate(context.Background()))
+
+	list := swagger.Paths["/pets"].Get
+	require.Equal(t, "listPets", list.OperationID)
+	require.Len(t, list.Parameters, 1)
+	require.Equal(t, "OK", list.Responses["200"].Description)
+
+	remove := swagger.Paths["/pets/{id}"].Delete
+	require.Len(t, remove.Responses, 1)
+	require.Len(t, remove.Parameters, 1)
+	require.Equal(t, &openapi2.Parameter{In: "path", Name: "id", Type: "string", Required: true}, remove.Parameters[0])
+}
@@ -0,0 +1,155 @@
+package openapi2
+
+import (
+	"fmt"
+	"strings"
+)
+
+// RecursiveDefinitions returns the reference cycles among definitions.
+//
+// Each cycle is a list of definition names where every definition references the next one,
+// starting and ending with the same name, for example ["Node", "Tree", "Node"].
+// A self-reference is reported as ["Node", "Node"].
+// Each cycle is reported once and starts with its lexically smallest definition name.
+func (swagger *Swagger) RecursiveDefinitions() [][]string {
+	var cycles [][]string
+	swagger.findDefinitionCycles(func(cycle []string) bool {
+		cycles = append(cycles, cycle)
+		return true
+	})
+	return cycles
+}
+
+// HasCircularRefs reports whether any definition takes part in a reference cycle.
+// It stops at the first cycle found, without collecting them like RecursiveDefinitions.
+func (swagger *Swagger) HasCircularRefs() bool {
+	circular := false
+	swagger.findDefinitionCycles(func([]string) bool {
+		circular = true
+		return false
+	})
+	return circular
+}
+
+// findDefinitionCycles calls found for each elementary cycle in the definition graph
+// until found returns false.
+func (swagger *Swagger) findDefinitionCycles(found func(cycle []string) bool) {
+	names := sortedSchemaNames(swagger.Definitions)
+	order := make(map[string]int, len(names))
+	for i, name := range names {
+		order[name] = i
+	}
+	dependencies := make(map[string][]string, len(names))
+	for _, name := range names {
+		dependencies[name] = definitionDependencies(swagger.Definitions[name])
+	}
+
+	// A cycle is only searched from its smallest member, so it is found exactly once.
+	for _, start := range names {
+		stack := []string{start}
+		onStack := map[string]bool{start: true}
+		var visit func(name string) bool
+		visit = func(name string) bool {
+			for _, next := range dependencies[name] {
+				i, ok := order[next]
+				if !ok || i < order[start] {
+					continue
+				}
+				if next == start {
+					cycle := make([]string, 0, len(stack)+1)
+					cycle = append(cycle, stack...)
+					cycle = append(cycle, start)
+					if !found(cycle) {
+						return false
+					}
+					continue
+				}
+				if onStack[next] {
+					continue
+				}
+				stack = append(stack, next)
+				onStack[next] = true
+				if !visit(next) {
+					return false
+				}
+				stack = stack[:len(stack)-1]
+				delete(onStack, next)
+			}
+			return true
+		}
+		if !visit(start) {
+			return
+		}
+	}
+}
+
+// RenameDefinition renames the definition old to new and rewrites every $ref pointing at it,
+// including references into it such as "#/definitions/Old/properties/name".
+// It fails if old doesn't exist or new already exists.
+// See PlanRenameDefinition to preview the rewritten references.
+func (swagger *Swagger) RenameDefinition(old, new string) error {
+	if err := swagger.checkRenameDefinition(old, new); err != nil {
+		return err
+	}
+	schemaRef := swagger.Definitions[old]
+	swagger.rewriteRefs(definitionsPrefix+escapePointerToken(old), definitionsPrefix+escapePointerToken(new))
+	delete(swagger.Definitions, old)
+	swagger.Definitions[new] = schemaRef
+	return nil
+}
+
+// RefRewrite is a $ref that a change of the document rewrites.
+type RefRewrite struct {
+	// Pointer is the JSON pointer of the element holding the $ref, such as "/paths/~1pets/get/parameters/0/schema".
+	Pointer string
+	Old     string
+	New     string
+}
+
+// PlanRenameDefinition returns the references that RenameDefinition would rewrite, in the order
+// of the document, without modifying it. It fails like RenameDefinition.
+func (swagger *Swagger) PlanRenameDefinition(old, new string) ([]RefRewrite, error) {
+	if err := swagger.checkRenameDefinition(old, new); err != nil {
+		return nil, err
+	}
+	oldRef, newRef := definitionsPrefix+escapePointerToken(old), definitionsPrefix+escapePointerToken(new)
+	var rewrites []RefRewrite
+	swagger.walkRefs(func(pointer string, ref *string) {
+		if rewritten, ok := rewriteRef(*ref, oldRef, newRef); ok {
+			rewrites = append(rewrites, RefRewrite{Pointer: pointer, Old: *ref, New: rewritten})
+		}
+	})
+	return rewrites, nil
+}
+
+// checkRenameDefinition returns the error of renaming the definition old to new,
+// shared by RenameDefinition and PlanRenameDefinition.
+func (swagger *Swagger) checkRenameDefinition(old, new string) error {
+	if _, ok := swagger.Definitions[old]; !ok {
+		return fmt.Errorf("Definition '%s' does not exist", old)
+	}
+	if _, ok := swagger.Definitions[new]; ok {
+		return fmt.Errorf("Definition '%s' already exists", new)
+	}
+	return nil
+}
+
+// rewriteRefs rewrites every $ref pointing at oldRef, or into it, to point at newRef.
+func (swagger *Swagger) rewriteRefs(oldRef, newRef string) {
+	swagger.walkRefs(func(_ string, ref *string) {
+		if rewritten, ok := rewriteRef(*ref, oldRef, newRef); ok {
+			*ref = rewritten
+		}
+	})
+}
+
+// rewriteRef returns a $ref pointing at oldRef, or into it, rewritten to point at newRef.
+func rewriteRef(ref, oldRef, newRef string) (string, bool) {
+	if ref == oldRef {
+		return newRef, true
+	}
+	if strings.HasPrefix(ref, oldRef+"/") {
+		return newRef + ref[len(oldRef):], true
+	}
+	return "", false
+}
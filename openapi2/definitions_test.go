@@ -0,0 +1,232 @@
+package openapi2_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mbilski/kin-openapi/openapi2"
+	"github.com/stretchr/testify/require"
+)
+
+func loadSwagger(t *testing.T, data string) *openapi2.Swagger {
+	var swagger openapi2.Swagger
+	err := json.Unmarshal([]byte(data), &swagger)
+	require.NoError(t, err)
+	return &swagger
+}
+
+func loadSwaggerFile(t *testing.T, path string) *openapi2.Swagger {
+	data, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	return loadSwagger(t, string(data))
+}
+
+func TestRecursiveDefinitions(t *testing.T) {
+	swagger := loadSwagger(t, `
+{
+  "info": {"title": "MyAPI", "version": "0.1"},
+  "definitions": {
+    "Node": {
+      "type": "object",
+      "properties": {
+        "children": {"type": "array", "items": {"$ref": "#/definitions/Node"}},
+        "tree": {"$ref": "#/definitions/Tree"}
+      }
+    },
+    "Tree": {
+      "type": "object",
+      "properties": {
+        "root": {"$ref": "#/definitions/Node"}
+      }
+    },
+    "Leaf": {
+      "type": "object",
+      "properties": {
+        "tree": {"$ref": "#/definitions/Tree"}
+      }
+    }
+  }
+}`)
+	require.Equal(t, [][]string{
+		{"Node", "Node"},
+		{"Node", "Tree", "Node"},
+	}, swagger.RecursiveDefinitions())
+	require.True(t, swagger.HasCircularRefs())
+}
+
+func TestRecursiveDefinitionsAcyclic(t *testing.T) {
+	swagger := loadSwagger(t, `
+{
+  "info": {"title": "MyAPI", "version": "0.1"},
+  "definitions": {
+    "Pet": {"properties": {"owner": {"$ref": "#/definitions/User"}}},
+    "User": {"properties": {"name": {"type": "string"}}}
+  }
+}`)
+	require.Empty(t, swagger.RecursiveDefinitions())
+	require.False(t, swagger.HasCircularRefs())
+}
+
+func TestEscapedDefinitionName(t *testing.T) {
+	swagger := loadSwagger(t, `
+{
+  "info": {"title": "MyAPI", "version": "0.1"},
+  "paths": {
+    "/pets": {
+      "post": {
+        "parameters": [{"in": "body", "name": "pet", "schema": {"$ref": "#/definitions/Foo~1Bar"}}],
+        "responses": {"201": {"description": "created"}}
+      }
+    }
+  },
+  "definitions": {
+    "Foo/Bar": {
+      "type": "object",
+      "additionalProperties": false,
+      "properties": {
+        "name": {"type": "string", "maxLength": 1},
+        "parent": {"$ref": "#/definitions/Foo~1Bar"}
+      }
+    }
+  }
+}`)
+	require.Equal(t, [][]string{{"Foo/Bar", "Foo/Bar"}}, swagger.RecursiveDefinitions())
+
+	req := httptest.NewRequest(http.MethodPost, "/pets", strings.NewReader(`{"name": 1}`))
+	req.Header.Set("Content-Type", "application/json")
+	err := openapi2.ValidateRequest(swagger, req)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "string")
+
+	// The recursive "parent" is unbounded, but the definition is resolved
+	_, ok := swagger.Paths["/pets"].Post.MaxBodyBytesHint(swagger, "/pets")
+	require.False(t, ok)
+	delete(swagger.Definitions["Foo/Bar"].Value.Properties, "parent")
+	size, ok := swagger.Paths["/pets"].Post.MaxBodyBytesHint(swagger, "/pets")
+	require.True(t, ok)
+	// {"name":<2+12>,}
+	require.Equal(t, int64(2+(6+2+14)), size)
+}
+
+func TestRenameDefinition(t *testing.T) {
+	swagger := loadSwagger(t, `
+{
+  "info": {"title": "MyAPI", "version": "0.1"},
+  "paths": {
+    "/pets": {
+      "post": {
+        "parameters": [
+          {"name": "pet", "in": "body", "schema": {"$ref": "#/definitions/Pet"}}
+        ],
+        "responses": {
+          "200": {
+            "description": "list",
+            "schema": {"type": "array", "items": {"$ref": "#/definitions/Pet"}}
+          }
+        }
+      }
+    }
+  },
+  "parameters": {
+    "pet": {"name": "pet", "in": "body", "schema": {"$ref": "#/definitions/Pet/properties/name"}}
+  },
+  "responses": {
+    "Pet": {"description": "pet", "schema": {"allOf": [{"$ref": "#/definitions/Pet"}]}}
+  },
+  "definitions": {
+    "Pet": {
+      "properties": {
+        "name": {"type": "string"},
+        "parent": {"$ref": "#/definitions/Pet"}
+      }
+    },
+    "Owner": {
+      "properties": {
+        "pets": {"type": "array", "items": {"additionalProperties": {"$ref": "#/definitions/Pet"}}},
+        "petstore": {"$ref": "#/definitions/PetStore"}
+      }
+    },
+    "PetStore": {"type": "object"}
+  }
+}`)
+	require.EqualError(t, swagger.RenameDefinition("Pet", "Owner"), "Definition 'Owner' already exists")
+	require.EqualError(t, swagger.RenameDefinition("Cat", "Animal"), "Definition 'Cat' does not exist")
+
+	require.NoError(t, swagger.RenameDefinition("Pet", "Animal"))
+	require.NotContains(t, swagger.Definitions, "Pet")
+	require.Contains(t, swagger.Definitions, "Animal")
+
+	operation := swagger.Paths["/pets"].Post
+	require.Equal(t, "#/definitions/Animal", operation.Parameters[0].Schema.Ref)
+	require.Equal(t, "#/definitions/Animal", operation.Responses["200"].Schema.Value.Items.Ref)
+	require.Equal(t, "#/definitions/Animal/properties/name", swagger.Parameters["pet"].Schema.Ref)
+	require.Equal(t, "#/definitions/Animal", swagger.Responses["Pet"].Schema.Value.AllOf[0].Ref)
+	animal := swagger.Definitions["Animal"].Value
+	require.Equal(t, "#/definitions/Animal", animal.Properties["parent"].Ref)
+	owner := swagger.Definitions["Owner"].Value
+	require.Equal(t, "#/definitions/Animal", owner.Properties["pets"].Value.Items.Value.AdditionalProperties.Ref)
+	require.Equal(t, "#/definitions/PetStore", owner.Properties["petstore"].Ref)
+}
+
+func TestPlanRenameDefinition(t *testing.T) {
+	const spec = `
+{
+  "info": {"title": "MyAPI", "version": "0.1"},
+  "paths": {
+    "/pets": {
+      "post": {
+        "parameters": [
+          {"name": "pet", "in": "body", "schema": {"$ref": "#/definitions/Pet"}}
+        ],
+        "responses": {
+          "200": {
+            "description": "list",
+            "schema": {"type": "array", "items": {"$ref": "#/definitions/Pet"}}
+          }
+        }
+      }
+    }
+  },
+  "parameters": {
+    "name": {"name": "name", "in": "body", "schema": {"$ref": "#/definitions/Pet/properties/name"}}
+  },
+  "definitions": {
+    "Pet": {
+      "properties": {
+        "name": {"type": "string"},
+        "parent": {"$ref": "#/definitions/Pet"},
+        "store": {"$ref": "#/definitions/PetStore"}
+      }
+    },
+    "PetStore": {"type": "object"}
+  }
+}`
+	swagger := loadSwagger(t, spec)
+	_, err := swagger.PlanRenameDefinition("Pet", "PetStore")
+	require.EqualError(t, err, "Definition 'PetStore' already exists")
+
+	plan, err := swagger.PlanRenameDefinition("Pet", "Animal")
+	require.NoError(t, err)
+	require.Equal(t, []openapi2.RefRewrite{
+		{Pointer: "/paths/~1pets/post/parameters/0/schema", Old: "#/definitions/Pet", New: "#/definitions/Animal"},
+		{Pointer: "/paths/~1pets/post/responses/200/schema/items", Old: "#/definitions/Pet", New: "#/definitions/Animal"},
+		{Pointer: "/definitions/Pet/properties/parent", Old: "#/definitions/Pet", New: "#/definitions/Animal"},
+		{Pointer: "/parameters/name/schema", Old: "#/definitions/Pet/properties/name", New: "#/definitions/Animal/properties/name"},
+	}, plan)
+	// The document is left as it is
+	require.True(t, swagger.Equal(loadSwagger(t, spec)))
+
+	// The plan describes the rename, once the pointers into the definition follow it
+	require.NoError(t, swagger.RenameDefinition("Pet", "Animal"))
+	for _, rewrite := range plan {
+		pointer := strings.Replace(rewrite.Pointer, "/definitions/Pet/", "/definitions/Animal/", 1)
+		ref, err := swagger.ResolvePointer(pointer + "/$ref")
+		require.NoError(t, err)
+		require.Equal(t, rewrite.New, ref, pointer)
+	}
+	require.Empty(t, swagger.RefsTo("#/definitions/Pet"))
+}
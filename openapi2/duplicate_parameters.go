@@ -0,0 +1,50 @@
+package openapi2
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+)
+
+// DuplicateInlineParameters reports the inline parameters that are declared identically
+// by several operations, which could be factored out into "#/parameters".
+//
+// The groups are keyed by a signature of the parameter, such as "query page ecce3999d235",
+// made of its location, its name and a hash of its JSON, and hold the pointers of the
+// operations declaring it, sorted by path and method.
+// Parameters are identical when their JSON is, including their descriptions and extensions.
+// References and path item parameters, which are already shared, are ignored.
+func (swagger *Swagger) DuplicateInlineParameters() map[string][]string {
+	groups := make(map[string][]string)
+	swagger.walkOperations(func(path string, method string, operation *Operation) {
+		pointer := operationPointer(path, method)
+		for _, parameter := range operation.Parameters {
+			if parameter == nil || parameter.Ref != "" {
+				continue
+			}
+			signature, err := parameter.signature()
+			if err != nil {
+				continue
+			}
+			if pointers := groups[signature]; len(pointers) == 0 || pointers[len(pointers)-1] != pointer {
+				groups[signature] = append(pointers, pointer)
+			}
+		}
+	})
+	for signature, pointers := range groups {
+		if len(pointers) < 2 {
+			delete(groups, signature)
+		}
+	}
+	return groups
+}
+
+// signature returns the signature of a parameter used by DuplicateInlineParameters.
+func (parameter *Parameter) signature() (string, error) {
+	data, err := json.Marshal(parameter)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return parameter.In + " " + parameter.Name + " " + hex.EncodeToString(sum[:6]), nil
+}
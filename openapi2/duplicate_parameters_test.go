@@ -0,0 +1,49 @@
+package openapi2_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDuplicateInlineParameters(t *testing.T) {
+	swagger := loadSwagger(t, `
+{
+  "info": {"title": "MyAPI", "version": "0.1"},
+  "parameters": {
+    "limit": {"in": "query", "name": "limit", "type": "integer"}
+  },
+  "paths": {
+    "/pets": {
+      "get": {
+        "parameters": [
+          {"in": "query", "name": "page", "type": "integer", "minimum": 1},
+          {"$ref": "#/parameters/limit"}
+        ],
+        "responses": {"200": {"description": "pets"}}
+      }
+    },
+    "/owners": {
+      "get": {
+        "parameters": [
+          {"in": "query", "name": "page", "type": "integer", "minimum": 1},
+          {"$ref": "#/parameters/limit"}
+        ],
+        "responses": {"200": {"description": "owners"}}
+      }
+    },
+    "/toys": {
+      "get": {
+        "parameters": [
+          {"in": "query", "name": "page", "type": "integer", "minimum": 0}
+        ],
+        "responses": {"200": {"description": "toys"}}
+      }
+    }
+  }
+}`)
+	// The "page" parameter of "/toys" differs, and references are already shared
+	require.Equal(t, map[string][]string{
+		"query page ecce3999d235": {"/paths/~1owners/get", "/paths/~1pets/get"},
+	}, swagger.DuplicateInlineParameters())
+}
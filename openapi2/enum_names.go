@@ -0,0 +1,58 @@
+package openapi2
+
+import (
+	"fmt"
+)
+
+// enumNameExtensions are the extensions read by Parameter.EnumNames, by priority.
+var enumNameExtensions = []string{"x-enumNames", "x-enum-varnames", "x-ms-enum"}
+
+// EnumNames returns the names given to the values of Enum by an enum-naming extension:
+//   - "x-enumNames" or "x-enum-varnames", a list of names
+//   - "x-ms-enum", whose "values" list holds objects with a "name"
+//
+// It returns false when no such extension is present, or when the number of names
+// differs from the number of enum values, which Swagger.Validate reports.
+func (parameter *Parameter) EnumNames() ([]string, bool) {
+	names, _, err := parameter.enumNames()
+	if err != nil || names == nil {
+		return nil, false
+	}
+	return names, true
+}
+
+// enumNames returns the enum names and the extension they come from, or nil if none.
+func (parameter *Parameter) enumNames() ([]string, string, error) {
+	for _, extension := range enumNameExtensions {
+		value, ok := parameter.Extensions[extension]
+		if !ok {
+			continue
+		}
+		var names []string
+		if extension == "x-ms-enum" {
+			var msEnum struct {
+				Values []struct {
+					Name string `json:"name"`
+				} `json:"values"`
+			}
+			if err := decodeExtension(value, &msEnum); err != nil {
+				return nil, extension, fmt.Errorf("Extension '%s' is invalid: %v", extension, err)
+			}
+			if msEnum.Values == nil {
+				// Only names the enum type
+				continue
+			}
+			names = make([]string, 0, len(msEnum.Values))
+			for _, value := range msEnum.Values {
+				names = append(names, value.Name)
+			}
+		} else if err := decodeExtension(value, &names); err != nil {
+			return nil, extension, fmt.Errorf("Extension '%s' must be a list of strings", extension)
+		}
+		if len(names) != len(parameter.Enum) {
+			return nil, extension, fmt.Errorf("Extension '%s' has %d names for %d enum values", extension, len(names), len(parameter.Enum))
+		}
+		return names, extension, nil
+	}
+	return nil, "", nil
+}
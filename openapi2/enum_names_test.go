@@ -0,0 +1,82 @@
+package openapi2_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnumNames(t *testing.T) {
+	spec := `
+{
+  "info": {"title": "MyAPI", "version": "0.1"},
+  "paths": {
+    "/pets": {
+      "get": {
+        "parameters": [
+          {
+            "in": "query", "name": "status", "type": "string", "enum": ["a", "s"],
+            "x-enumNames": ["Available", "Sold"]
+          },
+          {
+            "in": "query", "name": "color", "type": "string", "enum": ["r", "g"],
+            "x-ms-enum": {"name": "Color", "values": [{"value": "r", "name": "Red"}, {"value": "g", "name": "Green"}]}
+          },
+          {
+            "in": "query", "name": "size", "type": "string", "enum": ["s", "m", "l"],
+            "x-ms-enum": {"name": "Size", "modelAsString": true}
+          }
+        ],
+        "responses": {"200": {"description": "list"}}
+      }
+    }
+  }
+}`
+	swagger := loadSwagger(t, spec)
+	require.NoError(t, swagger.Validate(context.Background()))
+	parameters := swagger.Paths["/pets"].Get.Parameters
+
+	names, ok := parameters[0].EnumNames()
+	require.True(t, ok)
+	require.Equal(t, []string{"Available", "Sold"}, names)
+	names, ok = parameters[1].EnumNames()
+	require.True(t, ok)
+	require.Equal(t, []string{"Red", "Green"}, names)
+	_, ok = parameters[2].EnumNames()
+	require.False(t, ok)
+
+	// Extensions survive a round trip
+	data, err := json.Marshal(swagger)
+	require.NoError(t, err)
+	swagger = loadSwagger(t, string(data))
+	names, ok = swagger.Paths["/pets"].Get.Parameters[1].EnumNames()
+	require.True(t, ok)
+	require.Equal(t, []string{"Red", "Green"}, names)
+}
+
+func TestEnumNamesMismatch(t *testing.T) {
+	swagger := loadSwagger(t, `
+{
+  "info": {"title": "MyAPI", "version": "0.1"},
+  "paths": {
+    "/pets": {
+      "get": {
+        "parameters": [
+          {
+            "in": "query", "name": "status", "type": "string", "enum": ["a", "p", "s"],
+            "x-enumNames": ["Available", "Sold"]
+          }
+        ],
+        "responses": {"200": {"description": "list"}}
+      }
+    }
+  }
+}`)
+	_, ok := swagger.Paths["/pets"].Get.Parameters[0].EnumNames()
+	require.False(t, ok)
+	err := swagger.Validate(context.Background())
+	require.EqualError(t, err, "/paths/~1pets/get/parameters/0: Parameter 'status' has invalid enum names: "+
+		"Extension 'x-enumNames' has 2 names for 3 enum values")
+}
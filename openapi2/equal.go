@@ -0,0 +1,203 @@
+package openapi2
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// EqualOptions configures Swagger.EqualWithOptions.
+type EqualOptions struct {
+	// ResolveRefs compares the elements targeted by local $refs, such as "#/definitions/Pet",
+	// instead of the references, so that a document and its inlined form are equal.
+	// The "definitions", "parameters" and "responses" sections are then only compared
+	// through the elements referencing them. A recursive reference is compared as written.
+	ResolveRefs bool
+	// IgnoreExtensions skips the "x-" properties of every object.
+	IgnoreExtensions bool
+	// IgnoreDescriptions skips "description" and "summary" properties, which only document the API.
+	IgnoreDescriptions bool
+}
+
+// Equal reports whether two documents are semantically equal, as EqualWithOptions does
+// with each option disabled: extensions and descriptions are compared, and $refs are compared as written.
+func (swagger *Swagger) Equal(other *Swagger) bool {
+	return swagger.EqualWithOptions(other, EqualOptions{})
+}
+
+// EqualWithOptions reports whether two documents are semantically equal, configured by the given options.
+//
+// Documents are compared as JSON: the order of the keys of an object never matters, and
+// neither does the order of lists that are sets, which are "parameters", "required",
+// "consumes", "produces", "schemes" and "tags". The order of other lists, such as "enum", matters.
+// Examples, defaults, enums and extension values are compared as they are.
+func (swagger *Swagger) EqualWithOptions(other *Swagger, opts EqualOptions) bool {
+	if swagger == nil || other == nil {
+		return swagger == other
+	}
+	left, err := swagger.canonicalMap(opts)
+	if err != nil {
+		return false
+	}
+	right, err := other.canonicalMap(opts)
+	if err != nil {
+		return false
+	}
+	return reflect.DeepEqual(left, right)
+}
+
+// canonicalMap returns the document as generic JSON values, canonicalized for comparison.
+func (swagger *Swagger) canonicalMap(opts EqualOptions) (interface{}, error) {
+	root, err := swagger.ToMap()
+	if err != nil {
+		return nil, err
+	}
+	c := &canonicalizer{opts: opts, root: root}
+	result := c.object(root, nil).(map[string]interface{})
+	if opts.ResolveRefs {
+		delete(result, "definitions")
+		delete(result, "parameters")
+		delete(result, "responses")
+	}
+	return result, nil
+}
+
+// canonicalizer holds the state of Swagger.canonicalMap.
+type canonicalizer struct {
+	opts EqualOptions
+	root map[string]interface{}
+}
+
+// equalNameMaps are the properties whose keys are names rather than properties,
+// such as the properties of a schema, which are kept even if called "description".
+var equalNameMaps = map[string]bool{
+	"definitions":         true,
+	"headers":             true,
+	"parameters":          true,
+	"paths":               true,
+	"properties":          true,
+	"responses":           true,
+	"securityDefinitions": true,
+}
+
+// equalOpaque are the properties holding values rather than elements of the document.
+var equalOpaque = map[string]bool{
+	"default":  true,
+	"enum":     true,
+	"example":  true,
+	"examples": true,
+	"scopes":   true,
+	"security": true,
+}
+
+// equalSets are the lists whose order doesn't matter.
+var equalSets = map[string]bool{
+	"consumes":   true,
+	"parameters": true,
+	"produces":   true,
+	"required":   true,
+	"schemes":    true,
+	"tags":       true,
+}
+
+// object canonicalizes an element of the document.
+// stack holds the references being resolved.
+func (c *canonicalizer) object(value interface{}, stack []string) interface{} {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		if ref, ok := value["$ref"].(string); ok && c.opts.ResolveRefs {
+			if target, ok := c.resolve(ref, stack); ok {
+				return c.object(target, append(stack, ref))
+			}
+		}
+		result := make(map[string]interface{}, len(value))
+		for key, item := range value {
+			switch {
+			case strings.HasPrefix(key, "x-"):
+				if c.opts.IgnoreExtensions {
+					continue
+				}
+				result[key] = item
+			case c.opts.IgnoreDescriptions && (key == "description" || key == "summary"):
+			case equalOpaque[key]:
+				result[key] = item
+			default:
+				result[key] = c.property(key, item, stack)
+			}
+		}
+		return result
+	case []interface{}:
+		result := make([]interface{}, 0, len(value))
+		for _, item := range value {
+			result = append(result, c.object(item, stack))
+		}
+		return result
+	}
+	return value
+}
+
+// property canonicalizes the value of the property key of an element.
+func (c *canonicalizer) property(key string, value interface{}, stack []string) interface{} {
+	if names, ok := value.(map[string]interface{}); ok && equalNameMaps[key] {
+		result := make(map[string]interface{}, len(names))
+		for name, item := range names {
+			if strings.HasPrefix(name, "x-") && c.opts.IgnoreExtensions {
+				continue
+			}
+			result[name] = c.object(item, stack)
+		}
+		return result
+	}
+	value = c.object(value, stack)
+	if items, ok := value.([]interface{}); ok && equalSets[key] {
+		sortEqualSet(items)
+	}
+	return value
+}
+
+// resolve returns the element targeted by a local reference,
+// unless it is already being resolved.
+func (c *canonicalizer) resolve(ref string, stack []string) (interface{}, bool) {
+	if !strings.HasPrefix(ref, "#/") {
+		return nil, false
+	}
+	for _, item := range stack {
+		if item == ref {
+			return nil, false
+		}
+	}
+	var node interface{} = c.root
+	for _, token := range strings.Split(ref[2:], "/") {
+		object, ok := node.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if node, ok = object[unescapePointerToken(token)]; !ok {
+			return nil, false
+		}
+	}
+	return node, true
+}
+
+// sortEqualSet sorts canonicalized values by their JSON.
+func sortEqualSet(items []interface{}) {
+	keys := make([]string, len(items))
+	for i, item := range items {
+		data, _ := json.Marshal(item)
+		keys[i] = string(data)
+	}
+	sort.Sort(equalSet{items: items, keys: keys})
+}
+
+type equalSet struct {
+	items []interface{}
+	keys  []string
+}
+
+func (s equalSet) Len() int           { return len(s.items) }
+func (s equalSet) Less(i, j int) bool { return s.keys[i] < s.keys[j] }
+func (s equalSet) Swap(i, j int) {
+	s.items[i], s.items[j] = s.items[j], s.items[i]
+	s.keys[i], s.keys[j] = s.keys[j], s.keys[i]
+}
@@ -0,0 +1,160 @@
+package openapi2_test
+
+import (
+	"testing"
+
+	"github.com/mbilski/kin-openapi/openapi2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSwaggerEqual(t *testing.T) {
+	load := func(spec string) *openapi2.Swagger {
+		swagger, err := openapi2.NewSwaggerLoader().LoadSwaggerFromData([]byte(spec))
+		require.NoError(t, err)
+		return swagger
+	}
+	swagger := load(`
+swagger: "2.0"
+info: {title: MyAPI, version: "0.1"}
+consumes: [application/json, application/xml]
+paths:
+  /pets:
+    get:
+      summary: List pets
+      x-internal: true
+      parameters:
+      - {name: limit, in: query, type: integer}
+      - {name: tag, in: query, type: string}
+      responses:
+        200:
+          description: list
+          schema: {$ref: "#/definitions/Pet"}
+definitions:
+  Pet:
+    type: object
+    required: [id, name]
+    properties:
+      id: {type: integer}
+      name: {type: string}
+`)
+
+	// Only the order of keys and sets differs
+	reordered := load(`
+swagger: "2.0"
+paths:
+  /pets:
+    get:
+      responses:
+        200:
+          schema: {$ref: "#/definitions/Pet"}
+          description: list
+      parameters:
+      - {in: query, name: tag, type: string}
+      - {name: limit, in: query, type: integer}
+      x-internal: true
+      summary: List pets
+definitions:
+  Pet:
+    properties:
+      name: {type: string}
+      id: {type: integer}
+    required: [name, id]
+    type: object
+consumes: [application/xml, application/json]
+info: {version: "0.1", title: MyAPI}
+`)
+	require.True(t, swagger.Equal(reordered))
+	require.True(t, reordered.Equal(swagger))
+
+	// A type change is meaningful
+	changed := load(`
+swagger: "2.0"
+info: {title: MyAPI, version: "0.1"}
+consumes: [application/json, application/xml]
+paths:
+  /pets:
+    get:
+      summary: List pets
+      x-internal: true
+      parameters:
+      - {name: limit, in: query, type: string}
+      - {name: tag, in: query, type: string}
+      responses:
+        200:
+          description: list
+          schema: {$ref: "#/definitions/Pet"}
+definitions:
+  Pet:
+    type: object
+    required: [id, name]
+    properties:
+      id: {type: integer}
+      name: {type: string}
+`)
+	require.False(t, swagger.Equal(changed))
+	require.False(t, swagger.EqualWithOptions(changed, openapi2.EqualOptions{
+		ResolveRefs:        true,
+		IgnoreExtensions:   true,
+		IgnoreDescriptions: true,
+	}))
+
+	// The inlined form is only equal when resolving references
+	inlined := load(`
+swagger: "2.0"
+info: {title: MyAPI, version: "0.1"}
+consumes: [application/json, application/xml]
+paths:
+  /pets:
+    get:
+      summary: List pets
+      x-internal: true
+      parameters:
+      - {name: limit, in: query, type: integer}
+      - {name: tag, in: query, type: string}
+      responses:
+        200:
+          description: list
+          schema:
+            type: object
+            required: [id, name]
+            properties:
+              id: {type: integer}
+              name: {type: string}
+`)
+	require.False(t, swagger.Equal(inlined))
+	require.True(t, swagger.EqualWithOptions(inlined, openapi2.EqualOptions{ResolveRefs: true}))
+
+	// Extensions and descriptions participate unless ignored
+	undocumented := load(`
+swagger: "2.0"
+info: {title: MyAPI, version: "0.1"}
+consumes: [application/json, application/xml]
+paths:
+  /pets:
+    get:
+      parameters:
+      - {name: limit, in: query, type: integer}
+      - {name: tag, in: query, type: string}
+      responses:
+        200:
+          description: all the pets
+          schema: {$ref: "#/definitions/Pet"}
+definitions:
+  Pet:
+    type: object
+    required: [id, name]
+    properties:
+      id: {type: integer}
+      name: {type: string}
+`)
+	require.False(t, swagger.Equal(undocumented))
+	require.False(t, swagger.EqualWithOptions(undocumented, openapi2.EqualOptions{IgnoreDescriptions: true}))
+	require.False(t, swagger.EqualWithOptions(undocumented, openapi2.EqualOptions{IgnoreExtensions: true}))
+	require.True(t, swagger.EqualWithOptions(undocumented, openapi2.EqualOptions{
+		IgnoreExtensions:   true,
+		IgnoreDescriptions: true,
+	}))
+
+	require.False(t, swagger.Equal(nil))
+	require.True(t, (*openapi2.Swagger)(nil).Equal(nil))
+}
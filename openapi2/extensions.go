@@ -0,0 +1,110 @@
+package openapi2
+
+import "github.com/mbilski/kin-openapi/openapi3"
+
+// WalkExtensions calls fn with the extensions of every element of the document that carries them:
+// the document itself, its info with its contact and license, path items, operations, parameters,
+// responses, response headers and security schemes.
+//
+// The location is the JSON pointer of the element, "" being the document.
+// Elements are visited in a stable order. The map may be modified in place:
+// an element without extensions is given an empty map first, so fn can also add keys.
+func (swagger *Swagger) WalkExtensions(fn func(location string, ext map[string]interface{})) {
+	swagger.walkExtensions(func(location string, ext *map[string]interface{}) {
+		if *ext == nil {
+			*ext = make(map[string]interface{})
+		}
+		fn(location, *ext)
+	})
+}
+
+// walkExtensions calls fn with the extensions field of every element visited by WalkExtensions,
+// which holds nil for an element without extensions.
+func (swagger *Swagger) walkExtensions(fn func(location string, ext *map[string]interface{})) {
+	visit := func(location string, props *ExtensionProps) {
+		fn(location, &props.Extensions)
+	}
+
+	visit("", &swagger.ExtensionProps)
+	fn("/info", &swagger.Info.Extensions)
+	if contact := swagger.Info.Contact; contact != nil {
+		fn("/info/contact", &contact.Extensions)
+	}
+	if license := swagger.Info.License; license != nil {
+		fn("/info/license", &license.Extensions)
+	}
+
+	visitResponse := func(pointer string, response *Response) {
+		visit(pointer, &response.ExtensionProps)
+		for _, name := range sortedHeaderNames(response.Headers) {
+			if header := response.Headers[name]; header != nil {
+				visit(pointer+"/headers/"+escapePointerToken(name), &header.ExtensionProps)
+			}
+		}
+	}
+
+	for _, path := range swagger.sortedPaths() {
+		if pathItem := swagger.Paths[path]; pathItem != nil {
+			visit(pathPointer(path), &pathItem.ExtensionProps)
+		}
+	}
+	swagger.walkOperations(func(path string, method string, operation *Operation) {
+		visit(operationPointer(path, method), &operation.ExtensionProps)
+	})
+	swagger.walkParameters(func(pointer string, parameter *Parameter) {
+		visit(pointer, &parameter.ExtensionProps)
+	})
+	swagger.walkResponses(visitResponse)
+
+	for _, name := range sortedSecuritySchemeNames(swagger.SecurityDefinitions) {
+		if securityScheme := swagger.SecurityDefinitions[name]; securityScheme != nil {
+			visit("/securityDefinitions/"+escapePointerToken(name), &securityScheme.ExtensionProps)
+		}
+	}
+}
+
+// RemoveExtensions deletes the extensions whose key satisfies predicate from every element visited
+// by WalkExtensions, and from the external docs and the schemas of the document:
+// definitions, parameter schemas and items, and response schemas, with the schemas nested in them.
+//
+// Use it to strip internal extensions before publishing a document:
+//
+//	swagger.RemoveExtensions(func(key string) bool { return strings.HasPrefix(key, "x-internal") })
+func (swagger *Swagger) RemoveExtensions(predicate func(key string) bool) {
+	remove := func(ext map[string]interface{}) {
+		for key := range ext {
+			if predicate(key) {
+				delete(ext, key)
+			}
+		}
+	}
+	// Elements without extensions are left without a map
+	swagger.walkExtensions(func(_ string, ext *map[string]interface{}) {
+		remove(*ext)
+	})
+	removeFromSchema := func(schemaRef *openapi3.SchemaRef) {
+		walkSchemaRef("", schemaRef, func(_ string, schemaRef *openapi3.SchemaRef) {
+			if schemaRef.Ref == "" && schemaRef.Value != nil {
+				remove(schemaRef.Value.Extensions)
+			}
+		})
+	}
+	for _, name := range sortedSchemaNames(swagger.Definitions) {
+		removeFromSchema(swagger.Definitions[name])
+	}
+	swagger.walkParameters(func(_ string, parameter *Parameter) {
+		removeFromSchema(parameter.Schema)
+		removeFromSchema(parameter.Items)
+	})
+	swagger.walkResponses(func(_ string, response *Response) {
+		removeFromSchema(response.Schema)
+	})
+	if externalDocs := swagger.ExternalDocs; externalDocs != nil {
+		remove(externalDocs.Extensions)
+	}
+	swagger.walkOperations(func(_ string, _ string, operation *Operation) {
+		if externalDocs := operation.ExternalDocs; externalDocs != nil {
+			remove(externalDocs.Extensions)
+		}
+	})
+}
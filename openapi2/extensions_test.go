@@ -0,0 +1,115 @@
+package openapi2_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/mbilski/kin-openapi/openapi2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWalkExtensionsStripInternal(t *testing.T) {
+	swagger := loadSwagger(t, `
+{
+  "info": {"title": "MyAPI", "version": "0.1", "x-internal-owner": "team-a"},
+  "x-internal-build": 42,
+  "x-logo": "logo.png",
+  "paths": {
+    "/pets": {
+      "x-internal-router": "pets",
+      "parameters": [{"in": "query", "name": "limit", "type": "integer", "x-internal-note": "cap"}],
+      "get": {
+        "x-internal-team": "pets",
+        "x-public": true,
+        "responses": {
+          "200": {
+            "description": "ok",
+            "x-internal-cache": "1h",
+            "headers": {"X-Rate": {"type": "integer", "x-internal-source": "gateway"}}
+          }
+        }
+      }
+    }
+  },
+  "securityDefinitions": {
+    "key": {"type": "apiKey", "in": "header", "name": "X-Key", "x-internal-rotation": "daily"}
+  }
+}`)
+
+	var locations []string
+	swagger.WalkExtensions(func(location string, ext map[string]interface{}) {
+		for key := range ext {
+			if strings.HasPrefix(key, "x-internal-") {
+				delete(ext, key)
+				locations = append(locations, location)
+			}
+		}
+	})
+	require.Equal(t, []string{
+		"",
+		"/info",
+		"/paths/~1pets",
+		"/paths/~1pets/get",
+		"/paths/~1pets/parameters/0",
+		"/paths/~1pets/get/responses/200",
+		"/paths/~1pets/get/responses/200/headers/X-Rate",
+		"/securityDefinitions/key",
+	}, locations)
+
+	data, err := json.Marshal(swagger)
+	require.NoError(t, err)
+	require.NotContains(t, string(data), "x-internal-")
+	require.Contains(t, string(data), `"x-logo":"logo.png"`)
+	require.Contains(t, string(data), `"x-public":true`)
+}
+
+func TestRemoveExtensions(t *testing.T) {
+	swagger := loadSwagger(t, `
+{
+  "info": {
+    "title": "MyAPI",
+    "version": "0.1",
+    "x-internal-owner": "team-a",
+    "contact": {"name": "Team A", "x-internal-pager": "123"},
+    "license": {"name": "MIT", "x-internal-legal": "approved"}
+  },
+  "x-internal": true,
+  "x-logo": "logo.png",
+  "externalDocs": {"url": "https://example.com", "x-internal-wiki": "pets"},
+  "paths": {
+    "/pets": {
+      "get": {
+        "x-internal-team": "pets",
+        "x-public": true,
+        "parameters": [
+          {"in": "body", "name": "pet", "schema": {"type": "object", "x-internal-hint": 1}}
+        ],
+        "responses": {
+          "200": {"description": "ok", "x-internal-cache": "1h", "schema": {"$ref": "#/definitions/Pet"}}
+        }
+      }
+    }
+  },
+  "definitions": {
+    "Pet": {
+      "type": "object",
+      "x-go-name": "Pet",
+      "properties": {"secret": {"type": "string", "x-internal-pii": true}}
+    }
+  }
+}`)
+	// Elements without extensions are left as they are
+	swagger.SecurityDefinitions = map[string]*openapi2.SecurityScheme{"basic": {Type: "basic"}}
+	swagger.RemoveExtensions(func(key string) bool { return strings.HasPrefix(key, "x-internal") })
+	require.Nil(t, swagger.SecurityDefinitions["basic"].Extensions)
+
+	data, err := json.Marshal(swagger)
+	require.NoError(t, err)
+	require.NotContains(t, string(data), "x-internal")
+	require.Contains(t, swagger.Extensions, "x-logo")
+	require.Contains(t, swagger.Paths["/pets"].Get.Extensions, "x-public")
+	require.Contains(t, swagger.Definitions["Pet"].Value.Extensions, "x-go-name")
+	require.Empty(t, swagger.Info.Contact.Extensions)
+	require.Empty(t, swagger.Info.License.Extensions)
+}
@@ -0,0 +1,378 @@
+package openapi2
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/mbilski/kin-openapi/openapi3"
+)
+
+// RefLoader loads the documents targeted by external $refs, such as "common.yaml#/definitions/Error".
+type RefLoader interface {
+	// LoadRef returns the JSON or YAML document at location.
+	// A relative $ref is resolved against the location of the referring document first.
+	LoadRef(location *url.URL) ([]byte, error)
+}
+
+// RefLoaderFunc adapts a function into a RefLoader.
+type RefLoaderFunc func(location *url.URL) ([]byte, error)
+
+func (f RefLoaderFunc) LoadRef(location *url.URL) ([]byte, error) {
+	return f(location)
+}
+
+// FileRefLoader is a RefLoader reading from the local file system.
+var FileRefLoader RefLoader = RefLoaderFunc(func(location *url.URL) ([]byte, error) {
+	if (location.Scheme != "" && location.Scheme != "file") || location.Host != "" {
+		return nil, fmt.Errorf("Unsupported URI: '%s'", location.String())
+	}
+	return ioutil.ReadFile(location.Path)
+})
+
+// ResolveRefsIn resolves the external $refs of a document located at base, using swaggerLoader.RefLoader.
+// base may be nil, in which case relative references are passed to the RefLoader as they are.
+//
+// Local references of the document, such as "#/definitions/Pet", are left alone.
+// A schema reference keeps its $ref and gets the loaded schema as its value.
+// Path items, parameters, responses and headers, which can't hold both, are replaced by the loaded element.
+// References inside a loaded element resolve against the document it comes from.
+//
+// Each external document is loaded once.
+// A reference cycle within one external document is kept as a recursive schema,
+// but a cycle across documents is reported as an error.
+func (swaggerLoader *SwaggerLoader) ResolveRefsIn(swagger *Swagger, base *url.URL) error {
+	if swaggerLoader.RefLoader == nil {
+		return fmt.Errorf("Resolving external references requires a RefLoader")
+	}
+	r := &refResolver{
+		loader:    swaggerLoader.RefLoader,
+		documents: make(map[string]interface{}),
+		schemas:   make(map[string]*openapi3.Schema),
+		resolving: make(map[string]bool),
+	}
+	if base == nil {
+		base = &url.URL{}
+	}
+	var err error
+	swagger.walkParameters(func(_ string, parameter *Parameter) {
+		if err == nil {
+			err = r.resolveParameter(base, false, parameter, nil)
+		}
+	})
+	swagger.walkResponses(func(_ string, response *Response) {
+		if err == nil {
+			err = r.resolveResponse(base, false, response, nil)
+		}
+	})
+	for _, name := range sortedSchemaNames(swagger.Definitions) {
+		if err == nil {
+			err = r.resolveSchemaRefs(base, false, swagger.Definitions[name], nil)
+		}
+	}
+	// Last, so that the loaded path items aren't walked again from the root document
+	for _, path := range swagger.sortedPaths() {
+		if pathItem := swagger.Paths[path]; err == nil && pathItem != nil && pathItem.Ref != "" {
+			err = r.resolvePathItem(base, false, pathItem, nil)
+		}
+	}
+	return err
+}
+
+// refResolver holds the state of SwaggerLoader.ResolveRefsIn.
+type refResolver struct {
+	loader RefLoader
+	// documents are the loaded external documents, by location.
+	documents map[string]interface{}
+	// schemas are the resolved schemas, by location and fragment.
+	schemas map[string]*openapi3.Schema
+	// resolving holds the schemas being resolved.
+	resolving map[string]bool
+}
+
+// target returns the location of the document a $ref points into, and the JSON pointer in it.
+// It returns false for a local reference of the root document, which isn't resolved.
+func (r *refResolver) target(base *url.URL, external bool, ref string) (*url.URL, string, bool, error) {
+	parsed, err := url.Parse(ref)
+	if err != nil {
+		return nil, "", false, fmt.Errorf("Can't parse reference: '%s': %v", ref, err)
+	}
+	fragment := parsed.Fragment
+	parsed.Fragment = ""
+	if parsed.String() == "" {
+		return base, fragment, external, nil
+	}
+	if parsed.Scheme != "" || parsed.Host != "" {
+		return parsed, fragment, true, nil
+	}
+	location := *base
+	location.Fragment = ""
+	location.RawQuery = parsed.RawQuery
+	if strings.HasPrefix(parsed.Path, "/") || base.Path == "" {
+		location.Path = parsed.Path
+	} else {
+		location.Path = path.Join(path.Dir(base.Path), parsed.Path)
+	}
+	return &location, fragment, true, nil
+}
+
+// node returns the JSON of the element at the JSON pointer fragment of the document at location.
+func (r *refResolver) node(location *url.URL, fragment string) ([]byte, error) {
+	key := location.String()
+	document, ok := r.documents[key]
+	if !ok {
+		data, err := r.loader.LoadRef(location)
+		if err != nil {
+			return nil, err
+		}
+		if data, err = yaml.YAMLToJSON(data); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(data, &document); err != nil {
+			return nil, err
+		}
+		r.documents[key] = document
+	}
+	if fragment != "" {
+		if !strings.HasPrefix(fragment, "/") {
+			return nil, fmt.Errorf("Failed to resolve fragment in URI: '%s#%s'", key, fragment)
+		}
+		for _, token := range strings.Split(fragment[1:], "/") {
+			token = unescapePointerToken(token)
+			switch value := document.(type) {
+			case map[string]interface{}:
+				if document, ok = value[token]; !ok {
+					return nil, fmt.Errorf("Failed to resolve '%s' in fragment in URI: '%s#%s'", token, key, fragment)
+				}
+			case []interface{}:
+				i, err := strconv.Atoi(token)
+				if err != nil || i < 0 || i >= len(value) {
+					return nil, fmt.Errorf("Failed to resolve '%s' in fragment in URI: '%s#%s'", token, key, fragment)
+				}
+				document = value[i]
+			default:
+				return nil, fmt.Errorf("Failed to resolve '%s' in fragment in URI: '%s#%s'", token, key, fragment)
+			}
+		}
+	}
+	return json.Marshal(document)
+}
+
+func (r *refResolver) resolveSchemaRefs(base *url.URL, external bool, schemaRef *openapi3.SchemaRef, stack []string) error {
+	var err error
+	walkSchemaRef("", schemaRef, func(_ string, schemaRef *openapi3.SchemaRef) {
+		if err != nil || schemaRef.Ref == "" {
+			return
+		}
+		location, fragment, ok, targetErr := r.target(base, external, schemaRef.Ref)
+		if targetErr != nil {
+			err = targetErr
+			return
+		}
+		if !ok {
+			return
+		}
+		var value *openapi3.Schema
+		if value, err = r.resolveSchema(location, fragment, stack); err != nil {
+			err = fmt.Errorf("Error while resolving reference '%s': %v", schemaRef.Ref, err)
+			return
+		}
+		schemaRef.Value = value
+	})
+	return err
+}
+
+func (r *refResolver) resolveSchema(location *url.URL, fragment string, stack []string) (*openapi3.Schema, error) {
+	key := location.String() + "#" + fragment
+	if schema, ok := r.schemas[key]; ok {
+		if r.resolving[key] {
+			if err := crossDocumentCycle(stack, key); err != nil {
+				return nil, err
+			}
+		}
+		return schema, nil
+	}
+	data, err := r.node(location, fragment)
+	if err != nil {
+		return nil, err
+	}
+	schemaRef := &openapi3.SchemaRef{}
+	if err := json.Unmarshal(data, schemaRef); err != nil {
+		return nil, err
+	}
+	// Publish before resolving nested references, so that recursive schemas share it
+	schema := &openapi3.Schema{}
+	if schemaRef.Value != nil {
+		schema = schemaRef.Value
+	}
+	r.schemas[key] = schema
+	r.resolving[key] = true
+	defer delete(r.resolving, key)
+	stack = append(stack, key)
+	if schemaRef.Ref != "" {
+		// The element is itself a reference
+		target, targetFragment, _, err := r.target(location, true, schemaRef.Ref)
+		if err != nil {
+			return nil, err
+		}
+		resolved, err := r.resolveSchema(target, targetFragment, stack)
+		if err != nil {
+			return nil, err
+		}
+		*schema = *resolved
+		return schema, nil
+	}
+	if err := r.resolveSchemaRefs(location, true, schemaRef, stack); err != nil {
+		return nil, err
+	}
+	return schema, nil
+}
+
+// crossDocumentCycle returns an error if the cycle closed by key spans several documents.
+func crossDocumentCycle(stack []string, key string) error {
+	document := key[:strings.LastIndexByte(key, '#')]
+	for i := len(stack) - 1; i >= 0; i-- {
+		if stack[i] == key {
+			cycle := append(append([]string{}, stack[i:]...), key)
+			for _, item := range cycle {
+				if item[:strings.LastIndexByte(item, '#')] != document {
+					return fmt.Errorf("Cyclic reference across documents: %s", strings.Join(cycle, " -> "))
+				}
+			}
+			return nil
+		}
+	}
+	return nil
+}
+
+// load decodes the element that a $ref points at into value.
+// It returns false for a local reference of the root document, which isn't resolved,
+// and otherwise the location of the element and the stack to resolve its own references with.
+func (r *refResolver) load(base *url.URL, external bool, ref string, stack []string, value interface{}) (*url.URL, []string, bool, error) {
+	location, fragment, ok, err := r.target(base, external, ref)
+	if err != nil || !ok {
+		return nil, nil, false, err
+	}
+	key := location.String() + "#" + fragment
+	for _, item := range stack {
+		if item == key {
+			return nil, nil, false, fmt.Errorf("Cyclic reference: %s", strings.Join(append(stack, key), " -> "))
+		}
+	}
+	data, err := r.node(location, fragment)
+	if err != nil {
+		return nil, nil, false, fmt.Errorf("Error while resolving reference '%s': %v", ref, err)
+	}
+	if err := json.Unmarshal(data, value); err != nil {
+		return nil, nil, false, fmt.Errorf("Error while resolving reference '%s': %v", ref, err)
+	}
+	return location, append(stack, key), true, nil
+}
+
+func (r *refResolver) resolveParameter(base *url.URL, external bool, parameter *Parameter, stack []string) error {
+	if parameter == nil {
+		return nil
+	}
+	if ref := parameter.Ref; ref != "" {
+		resolved := &Parameter{}
+		location, stack, ok, err := r.load(base, external, ref, stack, resolved)
+		if err != nil || !ok {
+			return err
+		}
+		if err := r.resolveParameter(location, true, resolved, stack); err != nil {
+			return err
+		}
+		*parameter = *resolved
+		return nil
+	}
+	if err := r.resolveSchemaRefs(base, external, parameter.Schema, stack); err != nil {
+		return err
+	}
+	return r.resolveSchemaRefs(base, external, parameter.Items, stack)
+}
+
+func (r *refResolver) resolveResponse(base *url.URL, external bool, response *Response, stack []string) error {
+	if response == nil {
+		return nil
+	}
+	if ref := response.Ref; ref != "" {
+		resolved := &Response{}
+		location, stack, ok, err := r.load(base, external, ref, stack, resolved)
+		if err != nil || !ok {
+			return err
+		}
+		if err := r.resolveResponse(location, true, resolved, stack); err != nil {
+			return err
+		}
+		*response = *resolved
+		return nil
+	}
+	for _, name := range sortedHeaderNames(response.Headers) {
+		if err := r.resolveHeader(base, external, response.Headers[name], stack); err != nil {
+			return err
+		}
+	}
+	return r.resolveSchemaRefs(base, external, response.Schema, stack)
+}
+
+func (r *refResolver) resolveHeader(base *url.URL, external bool, header *Header, stack []string) error {
+	if header == nil || header.Ref == "" {
+		return nil
+	}
+	resolved := &Header{}
+	location, stack, ok, err := r.load(base, external, header.Ref, stack, resolved)
+	if err != nil || !ok {
+		return err
+	}
+	if err := r.resolveHeader(location, true, resolved, stack); err != nil {
+		return err
+	}
+	*header = *resolved
+	return nil
+}
+
+// resolvePathItem resolves the $ref of a path item, and the references of the path item it loads.
+func (r *refResolver) resolvePathItem(base *url.URL, external bool, pathItem *PathItem, stack []string) error {
+	if pathItem == nil {
+		return nil
+	}
+	if ref := pathItem.Ref; ref != "" {
+		resolved := &PathItem{}
+		location, stack, ok, err := r.load(base, external, ref, stack, resolved)
+		if err != nil || !ok {
+			return err
+		}
+		if err := r.resolvePathItem(location, true, resolved, stack); err != nil {
+			return err
+		}
+		*pathItem = *resolved
+		return nil
+	}
+	for _, parameter := range pathItem.Parameters {
+		if err := r.resolveParameter(base, external, parameter, stack); err != nil {
+			return err
+		}
+	}
+	for _, method := range operationMethods {
+		operation := pathItem.GetOperation(method)
+		if operation == nil {
+			continue
+		}
+		for _, parameter := range operation.Parameters {
+			if err := r.resolveParameter(base, external, parameter, stack); err != nil {
+				return err
+			}
+		}
+		for _, status := range sortedResponseKeys(operation.Responses) {
+			if err := r.resolveResponse(base, external, operation.Responses[status], stack); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
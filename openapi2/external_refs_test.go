@@ -0,0 +1,170 @@
+package openapi2_test
+
+import (
+	"fmt"
+	"net/url"
+	"testing"
+
+	"github.com/mbilski/kin-openapi/openapi2"
+	"github.com/stretchr/testify/require"
+)
+
+func mapRefLoader(files map[string]string, loads map[string]int) openapi2.RefLoader {
+	return openapi2.RefLoaderFunc(func(location *url.URL) ([]byte, error) {
+		loads[location.String()]++
+		data, ok := files[location.String()]
+		if !ok {
+			return nil, fmt.Errorf("File not found: '%s'", location)
+		}
+		return []byte(data), nil
+	})
+}
+
+func TestResolveExternalRefs(t *testing.T) {
+	spec := []byte(`
+info: {title: MyAPI, version: "0.1"}
+paths:
+  /pets:
+    get:
+      parameters:
+        - $ref: 'common/parameters.yaml#/limit'
+      responses:
+        200:
+          description: list
+          schema: {type: array, items: {$ref: '#/definitions/Pet'}}
+        404: {$ref: 'common/errors.yaml#/responses/NotFound'}
+        default:
+          description: error
+          schema: {$ref: 'common/errors.yaml#/definitions/Error'}
+definitions:
+  Pet: {type: object}
+`)
+	files := map[string]string{
+		"specs/common/errors.yaml": `
+definitions:
+  Error:
+    properties:
+      code: {$ref: '#/definitions/Code'}
+      cause: {$ref: '#/definitions/Error'}
+  Code: {type: integer}
+responses:
+  NotFound:
+    description: not found
+    schema: {$ref: '#/definitions/Error'}
+`,
+		"specs/common/parameters.yaml": `
+limit: {name: limit, in: query, type: integer}
+`,
+	}
+	loads := make(map[string]int)
+	loader := openapi2.NewSwaggerLoader()
+	swagger, err := loader.LoadSwaggerFromData(spec)
+	require.NoError(t, err)
+	loader.RefLoader = mapRefLoader(files, loads)
+	err = loader.ResolveRefsIn(swagger, &url.URL{Path: "specs/api.yaml"})
+	require.NoError(t, err)
+	require.Equal(t, map[string]int{
+		"specs/common/errors.yaml":     1,
+		"specs/common/parameters.yaml": 1,
+	}, loads)
+
+	operation := swagger.Paths["/pets"].Get
+	require.Equal(t, "limit", operation.Parameters[0].Name)
+	require.Empty(t, operation.Parameters[0].Ref)
+
+	// Local references of the root document are left alone
+	require.Nil(t, operation.Responses["200"].Schema.Value.Items.Value)
+
+	notFound := operation.Responses["404"]
+	require.Equal(t, "not found", notFound.Description)
+	errorSchema := operation.Responses["default"].Schema
+	require.Equal(t, "common/errors.yaml#/definitions/Error", errorSchema.Ref)
+	require.Equal(t, "integer", errorSchema.Value.Properties["code"].Value.Type)
+	// A recursive schema within one document shares its value
+	require.True(t, errorSchema.Value == errorSchema.Value.Properties["cause"].Value)
+	require.True(t, errorSchema.Value == notFound.Schema.Value)
+}
+
+func TestResolveExternalRefsPathItemsAndHeaders(t *testing.T) {
+	spec := []byte(`
+info: {title: MyAPI, version: "0.1"}
+paths:
+  /pets:
+    $ref: 'paths/pets.yaml#/pets'
+  /users:
+    get:
+      responses:
+        200:
+          description: list
+          headers:
+            X-Rate-Limit: {$ref: 'common/headers.yaml#/RateLimit'}
+`)
+	files := map[string]string{
+		"specs/paths/pets.yaml": `
+pets:
+  get:
+    responses:
+      200:
+        description: list
+        schema: {$ref: '#/definitions/Pet'}
+        headers:
+          X-Rate-Limit: {$ref: '../common/headers.yaml#/RateLimit'}
+definitions:
+  Pet: {type: object}
+`,
+		"specs/common/headers.yaml": `
+RateLimit: {$ref: '#/Limit'}
+Limit: {type: integer, description: requests per hour}
+`,
+	}
+	loads := make(map[string]int)
+	loader := openapi2.NewSwaggerLoader()
+	swagger, err := loader.LoadSwaggerFromData(spec)
+	require.NoError(t, err)
+	loader.RefLoader = mapRefLoader(files, loads)
+	err = loader.ResolveRefsIn(swagger, &url.URL{Path: "specs/api.yaml"})
+	require.NoError(t, err)
+	require.Equal(t, map[string]int{
+		"specs/common/headers.yaml": 1,
+		"specs/paths/pets.yaml":     1,
+	}, loads)
+
+	pets := swagger.Paths["/pets"]
+	require.Empty(t, pets.Ref)
+	response := pets.Get.Responses["200"]
+	// References inside the loaded path item resolve against its document
+	require.Equal(t, "object", response.Schema.Value.Type)
+	header := response.Headers["X-Rate-Limit"]
+	require.Empty(t, header.Ref)
+	require.Equal(t, "integer", header.Type)
+
+	header = swagger.Paths["/users"].Get.Responses["200"].Headers["X-Rate-Limit"]
+	require.Empty(t, header.Ref)
+	require.Equal(t, "integer", header.Type)
+	require.Equal(t, "requests per hour", header.Description)
+}
+
+func TestResolveExternalRefsCycle(t *testing.T) {
+	spec := []byte(`
+info: {title: MyAPI, version: "0.1"}
+definitions:
+  Pet: {$ref: 'a.yaml#/A'}
+`)
+	files := map[string]string{
+		"a.yaml": `A: {properties: {b: {$ref: 'b.yaml#/B'}}}`,
+		"b.yaml": `B: {properties: {a: {$ref: 'a.yaml#/A'}}}`,
+	}
+	loader := &openapi2.SwaggerLoader{RefLoader: mapRefLoader(files, make(map[string]int))}
+	_, err := loader.LoadSwaggerFromData(spec)
+	require.EqualError(t, err, "Error while resolving reference 'a.yaml#/A': "+
+		"Error while resolving reference 'b.yaml#/B': "+
+		"Error while resolving reference 'a.yaml#/A': "+
+		"Cyclic reference across documents: a.yaml#/A -> b.yaml#/B -> a.yaml#/A")
+
+	_, err = loader.LoadSwaggerFromData([]byte(`
+info: {title: MyAPI, version: "0.1"}
+definitions:
+  Pet: {$ref: 'missing.yaml#/Pet'}
+`))
+	require.EqualError(t, err, "Error while resolving reference 'missing.yaml#/Pet': File not found: 'missing.yaml'")
+}
@@ -0,0 +1,52 @@
+package openapi2
+
+import (
+	"strings"
+)
+
+// Resolve returns the header that the $ref of this header points at, or the header itself.
+//
+// OpenAPI 2 has no section for shared headers, so references are resolved loosely:
+//   - "#/responses/{response}/headers/{header}" reuses a header of a shared response
+//   - "#/definitions/{name}" builds a header from the type and description of a definition
+//
+// Any other reference, one pointing at something missing, or a reference cycle is an error.
+// A nil header resolves to nil.
+func (header *Header) Resolve(swagger *Swagger) (*Header, error) {
+	visited := make(map[string]struct{})
+	for header != nil && header.Ref != "" {
+		ref := header.Ref
+		if _, ok := visited[ref]; ok {
+			// A reference cycle
+			return nil, failedToResolveRef(ref)
+		}
+		visited[ref] = struct{}{}
+		if name, rest, ok := definitionName(ref); ok && rest == "" {
+			schemaRef, err := swagger.resolveSchemaRef(swagger.Definitions[name])
+			if err != nil || schemaRef == nil || schemaRef.Value == nil {
+				return nil, failedToResolveRef(ref)
+			}
+			return &Header{
+				Description: schemaRef.Value.Description,
+				Type:        schemaRef.Value.Type,
+			}, nil
+		}
+		if !strings.HasPrefix(ref, responsesPrefix) {
+			return nil, failedToResolveRef(ref)
+		}
+		parts := strings.Split(ref[len(responsesPrefix):], "/")
+		if len(parts) != 3 || parts[1] != "headers" {
+			return nil, failedToResolveRef(ref)
+		}
+		response, err := swagger.resolveResponse(swagger.Responses[unescapePointerToken(parts[0])])
+		if err != nil || response == nil {
+			return nil, failedToResolveRef(ref)
+		}
+		next := response.Headers[unescapePointerToken(parts[2])]
+		if next == nil {
+			return nil, failedToResolveRef(ref)
+		}
+		header = next
+	}
+	return header, nil
+}
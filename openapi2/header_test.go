@@ -0,0 +1,104 @@
+package openapi2_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mbilski/kin-openapi/openapi2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHeaderResolve(t *testing.T) {
+	swagger := loadSwagger(t, `
+{
+  "info": {"title": "MyAPI", "version": "0.1"},
+  "definitions": {
+    "RequestID": {"type": "string", "description": "Request identifier"}
+  },
+  "responses": {
+    "Limited": {
+      "description": "rate limited",
+      "headers": {"X-Rate-Limit": {"type": "integer", "description": "Calls per hour"}}
+    }
+  },
+  "paths": {
+    "/pets": {
+      "get": {
+        "responses": {
+          "200": {
+            "description": "ok",
+            "headers": {
+              "X-Inline": {"type": "string"},
+              "X-Rate-Limit": {"$ref": "#/responses/Limited/headers/X-Rate-Limit"},
+              "X-Request-Id": {"$ref": "#/definitions/RequestID"}
+            }
+          },
+          "429": {
+            "description": "too many",
+            "headers": {
+              "X-Missing": {"$ref": "#/responses/Limited/headers/X-Missing"},
+              "X-Untyped": {"description": "no type"}
+            }
+          }
+        }
+      }
+    }
+  }
+}`)
+	headers := swagger.Paths["/pets"].Get.Responses["200"].Headers
+
+	header, err := headers["X-Inline"].Resolve(swagger)
+	require.NoError(t, err)
+	require.Equal(t, "string", header.Type)
+
+	header, err = headers["X-Rate-Limit"].Resolve(swagger)
+	require.NoError(t, err)
+	require.Equal(t, "integer", header.Type)
+	require.Equal(t, "Calls per hour", header.Description)
+
+	header, err = headers["X-Request-Id"].Resolve(swagger)
+	require.NoError(t, err)
+	require.Equal(t, "string", header.Type)
+	require.Equal(t, "Request identifier", header.Description)
+
+	_, err = swagger.Paths["/pets"].Get.Responses["429"].Headers["X-Missing"].Resolve(swagger)
+	require.EqualError(t, err, "Failed to resolve ref: '#/responses/Limited/headers/X-Missing'")
+
+	err = swagger.Validate(context.Background())
+	require.EqualError(t, err, "/paths/~1pets/get/responses/429/headers/X-Missing: Failed to resolve ref: '#/responses/Limited/headers/X-Missing'"+
+		" | /paths/~1pets/get/responses/429/headers/X-Untyped: Header 'X-Untyped' must have a type")
+}
+
+func TestHeaderResolveChain(t *testing.T) {
+	swagger := loadSwagger(t, `
+{
+  "info": {"title": "MyAPI", "version": "0.1"},
+  "responses": {
+    "Limited": {
+      "description": "rate limited",
+      "headers": {
+        "A": {"$ref": "#/responses/Limited/headers/B"},
+        "B": {"$ref": "#/responses/Limited/headers/C"},
+        "C": {"type": "integer"},
+        "Loop": {"$ref": "#/responses/Limited/headers/Pool"},
+        "Pool": {"$ref": "#/responses/Limited/headers/Loop"}
+      }
+    }
+  },
+  "paths": {}
+}`)
+	headers := swagger.Responses["Limited"].Headers
+
+	// The chain is longer than the number of shared responses
+	header, err := headers["A"].Resolve(swagger)
+	require.NoError(t, err)
+	require.Equal(t, "integer", header.Type)
+
+	_, err = headers["Loop"].Resolve(swagger)
+	require.EqualError(t, err, "Failed to resolve ref: '#/responses/Limited/headers/Pool'")
+
+	var missing *openapi2.Header
+	header, err = missing.Resolve(swagger)
+	require.NoError(t, err)
+	require.Nil(t, header)
+}
@@ -0,0 +1,205 @@
+package openapi2
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/mbilski/kin-openapi/openapi3"
+)
+
+// RequestSample is a sample request of an operation, such as a request of a Postman collection,
+// for Swagger.ImportPaths.
+type RequestSample struct {
+	// Method is an HTTP method such as "GET".
+	Method string
+	// Path is a path template such as "/pets/{id}".
+	Path   string
+	Query  url.Values
+	Header http.Header
+	// Body is a sample JSON body, if any.
+	Body json.RawMessage
+}
+
+// importSkippedHeaders are the headers that describe the transport or the security
+// of a request rather than a parameter of the operation.
+var importSkippedHeaders = map[string]bool{
+	"Accept":         true,
+	"Authorization":  true,
+	"Content-Length": true,
+	"Content-Type":   true,
+	"Cookie":         true,
+	"Host":           true,
+	"User-Agent":     true,
+}
+
+// ImportPaths adds an operation for each sample, to bootstrap a document from existing requests.
+// It is a best-effort scaffolder: the operations are to be reviewed and enriched afterwards.
+//
+// The parameters and the body schema are inferred from the sample values:
+//   - The variables of the path template are required "string" path parameters.
+//   - Query values are "integer", "number", "boolean" or "string" parameters,
+//     and a repeated query key is an "array" parameter with the "multi" collection format.
+//   - Headers are "string" parameters, but for standard headers such as Content-Type.
+//   - A JSON body is a required "body" parameter, whose schema has the types of the sample values.
+//     The items of an array have the schema of its first item, and null has an empty schema.
+//     A body that isn't valid JSON gets an empty schema.
+//
+// A sample of an operation that already exists adds the parameters the operation doesn't declare.
+// New operations get a "200" response, like BuildSwagger does.
+// It panics on an unsupported HTTP method, like Swagger.AddOperation.
+func (swagger *Swagger) ImportPaths(samples []RequestSample) {
+	for _, sample := range samples {
+		method := strings.ToUpper(sample.Method)
+		var operation *Operation
+		if pathItem := swagger.Paths[sample.Path]; pathItem != nil {
+			operation = pathItem.GetOperation(method)
+		}
+		if operation == nil {
+			operation = &Operation{
+				Responses: map[string]*Response{
+					"200": {Description: "OK"},
+				},
+			}
+			swagger.AddOperation(sample.Path, method, operation)
+		}
+		for _, parameter := range sample.parameters() {
+			if !declaresParameter(operation.Parameters, parameter) {
+				operation.Parameters = append(operation.Parameters, parameter)
+			}
+		}
+		if len(sample.Body) > 0 && !hasConsumes(operation.Consumes, "application/json") {
+			operation.Consumes = append(operation.Consumes, "application/json")
+		}
+	}
+}
+
+// parameters returns the parameters inferred from a sample.
+func (sample *RequestSample) parameters() Parameters {
+	var parameters Parameters
+	for _, name := range pathVariableNames(sample.Path) {
+		parameters = append(parameters, &Parameter{
+			In:       "path",
+			Name:     name,
+			Type:     "string",
+			Required: true,
+		})
+	}
+	names := make([]string, 0, len(sample.Query))
+	for name := range sample.Query {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		values := sample.Query[name]
+		// A key without values, which url.Values allows, is a string parameter
+		parameter := &Parameter{In: "query", Name: name, Type: "string"}
+		if len(values) > 0 {
+			parameter.Type = inferValueType(values[0])
+		}
+		if len(values) > 1 {
+			parameter.Items = &openapi3.SchemaRef{Value: &openapi3.Schema{Type: parameter.Type}}
+			parameter.Type = "array"
+			parameter.CollectionFormat = "multi"
+		}
+		parameters = append(parameters, parameter)
+	}
+	names = names[:0]
+	for name := range sample.Header {
+		if !importSkippedHeaders[http.CanonicalHeaderKey(name)] {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		parameters = append(parameters, &Parameter{In: "header", Name: name, Type: "string"})
+	}
+	if len(sample.Body) > 0 {
+		parameters = append(parameters, &Parameter{
+			In:       "body",
+			Name:     "body",
+			Required: true,
+			Schema:   &openapi3.SchemaRef{Value: inferJSONSchema(sample.Body)},
+		})
+	}
+	return parameters
+}
+
+// declaresParameter reports whether parameters declare a parameter, or a body parameter
+// when it is one.
+func declaresParameter(parameters Parameters, parameter *Parameter) bool {
+	for _, item := range parameters {
+		if item == nil || item.In != parameter.In {
+			continue
+		}
+		if item.In == "body" || item.Name == parameter.Name {
+			return true
+		}
+	}
+	return false
+}
+
+func hasConsumes(consumes []string, mediaType string) bool {
+	for _, item := range consumes {
+		if item == mediaType {
+			return true
+		}
+	}
+	return false
+}
+
+// inferValueType returns the type of a sample value of a parameter.
+func inferValueType(value string) string {
+	if _, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return "integer"
+	}
+	if _, err := strconv.ParseFloat(value, 64); err == nil {
+		return "number"
+	}
+	if value == "true" || value == "false" {
+		return "boolean"
+	}
+	return "string"
+}
+
+// inferJSONSchema returns a schema with the types of a sample JSON value.
+func inferJSONSchema(data json.RawMessage) *openapi3.Schema {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return &openapi3.Schema{}
+	}
+	return inferValueSchema(value)
+}
+
+func inferValueSchema(value interface{}) *openapi3.Schema {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		schema := openapi3.NewObjectSchema()
+		for name, property := range value {
+			schema.Properties[name] = &openapi3.SchemaRef{Value: inferValueSchema(property)}
+		}
+		return schema
+	case []interface{}:
+		items := &openapi3.Schema{}
+		if len(value) > 0 {
+			items = inferValueSchema(value[0])
+		}
+		return openapi3.NewArraySchema().WithItems(items)
+	case string:
+		return openapi3.NewStringSchema()
+	case json.Number:
+		if _, err := value.Int64(); err == nil {
+			return openapi3.NewIntegerSchema()
+		}
+		return openapi3.NewFloat64Schema()
+	case bool:
+		return openapi3.NewBoolSchema()
+	}
+	return &openapi3.Schema{}
+}
@@ -0,0 +1,93 @@
+package openapi2_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/mbilski/kin-openapi/openapi2"
+	"github.com/mbilski/kin-openapi/openapi3"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImportPaths(t *testing.T) {
+	swagger := &openapi2.Swagger{Info: openapi3.Info{Title: "MyAPI", Version: "0.1"}}
+	swagger.ImportPaths([]openapi2.RequestSample{
+		{
+			Method: "get",
+			Path:   "/pets",
+			Query:  url.Values{"limit": {"10"}, "tag": {"cat", "dog"}, "vaccinated": {"true"}},
+			Header: http.Header{"X-Request-Id": {"abc"}, "Accept": {"application/json"}},
+		},
+		{
+			Method: "PUT",
+			Path:   "/pets/{id}",
+			Header: http.Header{"Content-Type": {"application/json"}},
+			Body:   json.RawMessage(`{"name": "Rex", "age": 3, "weight": 12.5, "tags": ["dog"], "owner": {"id": 1}, "nickname": null}`),
+		},
+	})
+	require.NoError(t, swagger.Validate(context.Background()))
+
+	list := swagger.Paths["/pets"].Get
+	require.NotNil(t, list)
+	require.Len(t, list.Parameters, 4)
+	limit, tag, vaccinated, requestID := list.Parameters[0], list.Parameters[1], list.Parameters[2], list.Parameters[3]
+	require.Equal(t, "query", limit.In)
+	require.Equal(t, "limit", limit.Name)
+	require.Equal(t, "integer", limit.Type)
+	require.Equal(t, "tag", tag.Name)
+	require.Equal(t, "array", tag.Type)
+	require.Equal(t, "multi", tag.CollectionFormat)
+	require.Equal(t, "string", tag.Items.Value.Type)
+	require.Equal(t, "vaccinated", vaccinated.Name)
+	require.Equal(t, "boolean", vaccinated.Type)
+	require.Equal(t, "header", requestID.In)
+	require.Equal(t, "X-Request-Id", requestID.Name)
+	require.Contains(t, list.Responses, "200")
+
+	update := swagger.Paths["/pets/{id}"].Put
+	require.NotNil(t, update)
+	require.Equal(t, []string{"application/json"}, update.Consumes)
+	require.Len(t, update.Parameters, 2)
+	id, body := update.Parameters[0], update.Parameters[1]
+	require.Equal(t, "path", id.In)
+	require.Equal(t, "id", id.Name)
+	require.True(t, id.Required)
+	require.Equal(t, "body", body.In)
+	schema := body.Schema.Value
+	require.Equal(t, "object", schema.Type)
+	require.Equal(t, "string", schema.Properties["name"].Value.Type)
+	require.Equal(t, "integer", schema.Properties["age"].Value.Type)
+	require.Equal(t, "number", schema.Properties["weight"].Value.Type)
+	require.Equal(t, "array", schema.Properties["tags"].Value.Type)
+	require.Equal(t, "string", schema.Properties["tags"].Value.Items.Value.Type)
+	require.Equal(t, "object", schema.Properties["owner"].Value.Type)
+	require.Equal(t, "integer", schema.Properties["owner"].Value.Properties["id"].Value.Type)
+	require.Equal(t, "", schema.Properties["nickname"].Value.Type)
+
+	// Another sample of an operation only adds the parameters it doesn't declare
+	swagger.ImportPaths([]openapi2.RequestSample{{
+		Method: "GET",
+		Path:   "/pets",
+		Query:  url.Values{"limit": {"abc"}, "offset": {"20"}},
+	}})
+	require.Len(t, list.Parameters, 5)
+	require.Equal(t, "integer", list.Parameters[0].Type)
+	require.Equal(t, "offset", list.Parameters[4].Name)
+}
+
+func TestImportPathsEmptyQueryValues(t *testing.T) {
+	swagger := &openapi2.Swagger{}
+	swagger.ImportPaths([]openapi2.RequestSample{{
+		Method: "GET",
+		Path:   "/pets",
+		Query:  url.Values{"debug": {}, "limit": {"10"}},
+	}})
+	parameters := swagger.Paths["/pets"].Get.Parameters
+	require.Len(t, parameters, 2)
+	require.Equal(t, "debug", parameters[0].Name)
+	require.Equal(t, "string", parameters[0].Type)
+	require.Equal(t, "integer", parameters[1].Type)
+}
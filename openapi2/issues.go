@@ -0,0 +1,191 @@
+package openapi2
+
+import (
+	"context"
+	"strings"
+)
+
+// Severity tells whether an Issue makes the document invalid.
+type Severity string
+
+const (
+	SeverityError   = Severity("error")
+	SeverityWarning = Severity("warning")
+)
+
+// Issue is a problem reported by Swagger.ValidateIssues.
+type Issue struct {
+	Severity Severity `json:"severity"`
+	// Pointer is the JSON pointer of the offending element, such as "/paths/~1pets/get".
+	Pointer string `json:"pointer"`
+	// Rule names the check reporting the issue, such as "parameters".
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+func (issue Issue) String() string {
+	return string(issue.Severity) + ": " + issue.Pointer + ": " + issue.Message
+}
+
+// ValidationReport is the outcome of Swagger.ValidateDetailed, ready to be encoded as JSON.
+type ValidationReport struct {
+	// Valid is false when an issue has SeverityError.
+	Valid bool `json:"valid"`
+	// Issues is never nil, so that it encodes as an empty list.
+	Issues []Issue `json:"issues"`
+}
+
+// ValidateDetailed validates the document like ValidateIssues, and reports the issues
+// with whether the document is valid.
+func (swagger *Swagger) ValidateDetailed(c context.Context, opts ValidationOptions) ValidationReport {
+	report := ValidationReport{Valid: true, Issues: swagger.ValidateIssues(c, opts)}
+	if report.Issues == nil {
+		report.Issues = []Issue{}
+	}
+	for _, issue := range report.Issues {
+		if issue.Severity == SeverityError {
+			report.Valid = false
+		}
+	}
+	return report
+}
+
+// ValidateIssues is like ValidateWithOptions, but returns the problems as issues.
+// The errors of ValidateWithOptions come first, with SeverityError,
+// followed by the warnings, with SeverityWarning: operations producing media types without
+// any response schema, and the checks enabled by the options.
+// Warnings don't make a document invalid: ValidateWithOptions ignores them.
+func (swagger *Swagger) ValidateIssues(c context.Context, opts ValidationOptions) []Issue {
+	var issues []Issue
+	add := func(severity Severity, namedRules []namedRule) {
+		for _, namedRule := range namedRules {
+			for _, err := range namedRule.rule(swagger) {
+				issue := Issue{Severity: severity, Rule: namedRule.name, Message: err.Error()}
+				if lintErr, ok := err.(*LintError); ok {
+					issue.Pointer = lintErr.Pointer
+					issue.Message = lintErr.Reason
+				}
+				issues = append(issues, issue)
+			}
+		}
+	}
+	v := &validator{c: c, opts: opts}
+	add(SeverityError, v.namedRules())
+	add(SeverityWarning, v.warningRules())
+	return issues
+}
+
+func (v *validator) warningRules() []namedRule {
+	rules := []namedRule{
+		{"produces-unused", v.producesWithoutSchemas},
+	}
+	if v.opts.RequireProduces {
+		rules = append(rules, namedRule{"require-produces", v.requireProduces})
+	}
+	if v.opts.RequireDescriptions {
+		rules = append(rules, namedRule{"require-descriptions", v.requireDescriptions})
+	}
+	if v.opts.CheckPatternFormats {
+		rules = append(rules, namedRule{"pattern-formats", v.checkPatternFormats})
+	}
+	if v.opts.CheckRequiredDefaults {
+		rules = append(rules, namedRule{"required-defaults", v.checkRequiredDefaults})
+	}
+	if v.opts.ResponseCoverageSeverity != SeverityError {
+		rules = append(rules, v.responseCoverageRules()...)
+	}
+	return rules
+}
+
+func (v *validator) requireDescriptions(swagger *Swagger) []error {
+	var errs []error
+	swagger.walkOperations(func(path string, method string, operation *Operation) {
+		if isBlank(operation.Summary) && isBlank(operation.Description) {
+			errs = append(errs, &LintError{
+				Pointer: operationPointer(path, method),
+				Reason:  "Operation has no summary or description",
+			})
+		}
+	})
+	swagger.walkParameters(func(pointer string, parameter *Parameter) {
+		if parameter.Ref == "" && isBlank(parameter.Description) {
+			errs = append(errs, &LintError{
+				Pointer: pointer,
+				Reason:  "Parameter '" + parameter.Name + "' has no description",
+			})
+		}
+	})
+	swagger.walkResponses(func(pointer string, response *Response) {
+		if response.Ref == "" && isBlank(response.Description) {
+			errs = append(errs, &LintError{Pointer: pointer, Reason: "Response has no description"})
+		}
+	})
+	return errs
+}
+
+// checkRequiredDefaults warns about required parameters with a default, or allowing an empty value.
+// Path parameters, which can't have a default, are reported by the "parameters" rule instead.
+func (v *validator) checkRequiredDefaults(swagger *Swagger) []error {
+	var errs []error
+	swagger.walkParameters(func(pointer string, parameter *Parameter) {
+		if parameter.Ref != "" || !parameter.Required {
+			return
+		}
+		if parameter.In != "path" && parameter.Default != nil {
+			errs = append(errs, &LintError{
+				Pointer: pointer,
+				Reason:  "Required parameter '" + parameter.Name + "' has a default, which never applies",
+			})
+		}
+		if parameter.AllowEmptyValue {
+			errs = append(errs, &LintError{
+				Pointer: pointer,
+				Reason:  "Required parameter '" + parameter.Name + "' allows an empty value, so its value may be missing",
+			})
+		}
+	})
+	return errs
+}
+
+// requireProduces warns about operations with a response schema that produce no media type,
+// which tells clients how to decode the response.
+func (v *validator) requireProduces(swagger *Swagger) []error {
+	var errs []error
+	swagger.walkOperations(func(path string, method string, operation *Operation) {
+		if len(operation.Produces) == 0 && len(swagger.Produces) == 0 && swagger.hasResponseSchema(operation) {
+			errs = append(errs, &LintError{
+				Pointer: operationPointer(path, method),
+				Reason:  "Operation with response schemas must produce at least one media type",
+			})
+		}
+	})
+	return errs
+}
+
+// hasResponseSchema reports whether a response of an operation, once resolved, has a schema.
+func (swagger *Swagger) hasResponseSchema(operation *Operation) bool {
+	for _, response := range operation.Responses {
+		if resolved, err := swagger.resolveResponse(response); err == nil && resolved != nil && resolved.Schema != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// producesWithoutSchemas warns about operations producing media types without any response schema.
+func (v *validator) producesWithoutSchemas(swagger *Swagger) []error {
+	var errs []error
+	swagger.walkOperations(func(path string, method string, operation *Operation) {
+		if len(operation.Produces) > 0 && len(operation.Responses) > 0 && !swagger.hasResponseSchema(operation) {
+			errs = append(errs, &LintError{
+				Pointer: operationPointer(path, method),
+				Reason:  "Operation produces media types but none of its responses has a schema",
+			})
+		}
+	})
+	return errs
+}
+
+func isBlank(s string) bool {
+	return strings.TrimSpace(s) == ""
+}
@@ -0,0 +1,221 @@
+package openapi2_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/mbilski/kin-openapi/openapi2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateIssuesRequireDescriptions(t *testing.T) {
+	swagger := loadSwagger(t, `
+{
+  "info": {"title": "MyAPI", "version": "0.1"},
+  "parameters": {
+    "limit": {"in": "query", "name": "limit", "type": "integer"}
+  },
+  "responses": {
+    "NotFound": {"description": " "}
+  },
+  "paths": {
+    "/pets": {
+      "get": {
+        "summary": "List pets",
+        "parameters": [
+          {"$ref": "#/parameters/limit"},
+          {"in": "query", "name": "tag", "type": "string", "description": "Tag to filter by"}
+        ],
+        "responses": {
+          "200": {"description": "pets"},
+          "404": {"$ref": "#/responses/NotFound"}
+        }
+      },
+      "post": {
+        "parameters": [{"in": "query", "name": "dryRun", "type": "bogus"}],
+        "responses": {"201": {"description": ""}}
+      }
+    }
+  }
+}`)
+	ctx := context.Background()
+	issues := swagger.ValidateIssues(ctx, openapi2.ValidationOptions{})
+	require.Equal(t, []openapi2.Issue{{
+		Severity: openapi2.SeverityError,
+		Pointer:  "/paths/~1pets/post/parameters/0",
+		Rule:     "parameters",
+		Message:  "Parameter 'dryRun' has unsupported type 'bogus'",
+	}}, issues)
+
+	issues = swagger.ValidateIssues(ctx, openapi2.ValidationOptions{RequireDescriptions: true})
+	var warnings []string
+	for _, issue := range issues {
+		if issue.Severity == openapi2.SeverityWarning {
+			warnings = append(warnings, issue.Pointer+": "+issue.Message)
+		}
+	}
+	require.Len(t, issues, 6)
+	require.Equal(t, openapi2.SeverityError, issues[0].Severity)
+	require.Equal(t, []string{
+		"/paths/~1pets/post: Operation has no summary or description",
+		"/parameters/limit: Parameter 'limit' has no description",
+		"/paths/~1pets/post/parameters/0: Parameter 'dryRun' has no description",
+		"/responses/NotFound: Response has no description",
+		"/paths/~1pets/post/responses/201: Response has no description",
+	}, warnings)
+
+	// Warnings don't fail validation
+	err := swagger.ValidateWithOptions(ctx, openapi2.ValidationOptions{RequireDescriptions: true})
+	require.Len(t, err, 1)
+}
+
+func TestValidateIssuesRequiredDefaults(t *testing.T) {
+	swagger := loadSwagger(t, `
+{
+  "info": {"title": "MyAPI", "version": "0.1"},
+  "paths": {
+    "/pets/{id}": {
+      "parameters": [{"in": "path", "name": "id", "type": "string", "required": true, "default": "1"}],
+      "get": {
+        "parameters": [
+          {"in": "query", "name": "limit", "type": "integer", "required": true, "default": 10},
+          {"in": "query", "name": "offset", "type": "integer", "default": 0},
+          {"in": "query", "name": "tag", "type": "string", "required": true, "allowEmptyValue": true}
+        ],
+        "responses": {"200": {"description": "pet"}}
+      }
+    }
+  }
+}`)
+	ctx := context.Background()
+	pathError := openapi2.Issue{
+		Severity: openapi2.SeverityError,
+		Pointer:  "/paths/~1pets~1{id}/parameters/0",
+		Rule:     "parameters",
+		Message:  "Path parameter 'id' must not have a default",
+	}
+	require.Equal(t, []openapi2.Issue{pathError}, swagger.ValidateIssues(ctx, openapi2.ValidationOptions{}))
+
+	require.Equal(t, []openapi2.Issue{
+		pathError,
+		{
+			Severity: openapi2.SeverityWarning,
+			Pointer:  "/paths/~1pets~1{id}/get/parameters/0",
+			Rule:     "required-defaults",
+			Message:  "Required parameter 'limit' has a default, which never applies",
+		},
+		{
+			Severity: openapi2.SeverityWarning,
+			Pointer:  "/paths/~1pets~1{id}/get/parameters/2",
+			Rule:     "required-defaults",
+			Message:  "Required parameter 'tag' allows an empty value, so its value may be missing",
+		},
+	}, swagger.ValidateIssues(ctx, openapi2.ValidationOptions{CheckRequiredDefaults: true}))
+}
+
+func TestValidateIssuesRequireProduces(t *testing.T) {
+	swagger := loadSwagger(t, `
+{
+  "info": {"title": "MyAPI", "version": "0.1"},
+  "responses": {
+    "Pets": {"description": "pets", "schema": {"type": "array", "items": {"type": "string"}}}
+  },
+  "paths": {
+    "/pets": {
+      "get": {
+        "responses": {"200": {"$ref": "#/responses/Pets"}}
+      },
+      "delete": {
+        "produces": ["application/json"],
+        "responses": {"204": {"description": "deleted"}}
+      },
+      "post": {
+        "produces": ["application/json"],
+        "responses": {"201": {"description": "created", "schema": {"type": "string"}}}
+      }
+    }
+  }
+}`)
+	ctx := context.Background()
+	// The implicit JSON media type keeps the document valid
+	require.NoError(t, swagger.Validate(ctx))
+	unused := openapi2.Issue{
+		Severity: openapi2.SeverityWarning,
+		Pointer:  "/paths/~1pets/delete",
+		Rule:     "produces-unused",
+		Message:  "Operation produces media types but none of its responses has a schema",
+	}
+	require.Equal(t, []openapi2.Issue{unused}, swagger.ValidateIssues(ctx, openapi2.ValidationOptions{}))
+
+	opts := openapi2.ValidationOptions{RequireProduces: true}
+	require.Equal(t, []openapi2.Issue{
+		unused,
+		{
+			Severity: openapi2.SeverityWarning,
+			Pointer:  "/paths/~1pets/get",
+			Rule:     "require-produces",
+			Message:  "Operation with response schemas must produce at least one media type",
+		},
+	}, swagger.ValidateIssues(ctx, opts))
+
+	// The document default applies
+	swagger.Produces = []string{"application/json"}
+	require.Equal(t, []openapi2.Issue{unused}, swagger.ValidateIssues(ctx, opts))
+}
+
+func TestValidateDetailed(t *testing.T) {
+	swagger := loadSwagger(t, `
+{
+  "info": {"title": "MyAPI", "version": "0.1"},
+  "paths": {
+    "/pets": {
+      "get": {
+        "summary": "List pets",
+        "responses": {"200": {"description": "pets"}}
+      },
+      "post": {
+        "summary": "Create a pet",
+        "responses": {}
+      },
+      "put": {
+        "responses": {"200": {"description": "updated"}}
+      }
+    }
+  }
+}`)
+	ctx := context.Background()
+	report := swagger.ValidateDetailed(ctx, openapi2.ValidationOptions{RequireDescriptions: true})
+	require.Equal(t, openapi2.ValidationReport{
+		Valid: false,
+		Issues: []openapi2.Issue{
+			{
+				Severity: openapi2.SeverityError,
+				Pointer:  "/paths/~1pets/post/responses",
+				Rule:     "operations",
+				Message:  "Operation must declare at least one response",
+			},
+			{
+				Severity: openapi2.SeverityWarning,
+				Pointer:  "/paths/~1pets/put",
+				Rule:     "require-descriptions",
+				Message:  "Operation has no summary or description",
+			},
+		},
+	}, report)
+	data, err := json.Marshal(report.Issues[0])
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+  "severity": "error",
+  "pointer": "/paths/~1pets/post/responses",
+  "rule": "operations",
+  "message": "Operation must declare at least one response"
+}`, string(data))
+
+	swagger.Paths["/pets"].Post = nil
+	report = swagger.ValidateDetailed(ctx, openapi2.ValidationOptions{})
+	require.True(t, report.Valid)
+	data, err = json.Marshal(report)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"valid": true, "issues": []}`, string(data))
+}
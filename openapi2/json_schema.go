@@ -0,0 +1,109 @@
+package openapi2
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// JSONSchemaDraft07 is the "$schema" of the documents returned by Swagger.ExtractJSONSchema.
+const JSONSchemaDraft07 = "http://json-schema.org/draft-07/schema#"
+
+// ExtractJSONSchema returns a standalone JSON Schema document for the definition with the given name.
+//
+// The definitions it references, directly or transitively, are bundled under "definitions",
+// the draft-07 keyword, so their "#/definitions/..." references resolve as they are.
+// A reference back to the extracted definition becomes "#", or "#/properties/..." for a reference
+// inside it, so recursive schemas are kept.
+// A reference to a missing definition is an error.
+func (swagger *Swagger) ExtractJSONSchema(name string) ([]byte, error) {
+	if swagger.Definitions[name] == nil {
+		return nil, fmt.Errorf("Definition '%s' does not exist", name)
+	}
+	var pending []string
+	rewrite := func(ref string) (string, error) {
+		target, rest, ok := definitionName(ref)
+		if !ok {
+			return ref, nil
+		}
+		if swagger.Definitions[target] == nil {
+			return "", fmt.Errorf("Failed to resolve ref: '%s'", ref)
+		}
+		if target == name {
+			return "#" + rest, nil
+		}
+		pending = append(pending, target)
+		return ref, nil
+	}
+
+	root, err := definitionJSON(swagger, name, rewrite)
+	if err != nil {
+		return nil, err
+	}
+	defs := make(map[string]interface{})
+	for len(pending) > 0 {
+		target := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+		if _, ok := defs[target]; ok {
+			continue
+		}
+		// Registered first, so that a cycle between definitions ends
+		defs[target] = nil
+		if defs[target], err = definitionJSON(swagger, target, rewrite); err != nil {
+			return nil, err
+		}
+	}
+
+	document, ok := root.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("Definition '%s' is not an object", name)
+	}
+	document["$schema"] = JSONSchemaDraft07
+	if len(defs) > 0 {
+		document["definitions"] = defs
+	}
+	return json.Marshal(document)
+}
+
+// definitionJSON returns the decoded JSON of a definition, with its "$ref" values replaced by rewrite.
+func definitionJSON(swagger *Swagger, name string, rewrite func(ref string) (string, error)) (interface{}, error) {
+	data, err := json.Marshal(swagger.Definitions[name])
+	if err != nil {
+		return nil, err
+	}
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return nil, err
+	}
+	if err := rewriteRefs(value, rewrite); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+func rewriteRefs(value interface{}, rewrite func(ref string) (string, error)) error {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		for key, item := range value {
+			// A property named "$ref" holds a schema, not a string
+			if ref, ok := item.(string); ok && key == "$ref" && strings.HasPrefix(ref, "#") {
+				rewritten, err := rewrite(ref)
+				if err != nil {
+					return err
+				}
+				value[key] = rewritten
+				continue
+			}
+			if err := rewriteRefs(item, rewrite); err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		for _, item := range value {
+			if err := rewriteRefs(item, rewrite); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
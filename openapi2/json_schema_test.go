@@ -0,0 +1,100 @@
+package openapi2_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtractJSONSchema(t *testing.T) {
+	swagger := loadSwagger(t, `
+{
+  "info": {"title": "MyAPI", "version": "0.1"},
+  "paths": {},
+  "definitions": {
+    "Pet": {
+      "type": "object",
+      "properties": {
+        "owner": {"$ref": "#/definitions/Owner"},
+        "tags": {"type": "array", "items": {"$ref": "#/definitions/Tag"}},
+        "parent": {"$ref": "#/definitions/Pet"}
+      }
+    },
+    "Owner": {
+      "type": "object",
+      "properties": {
+        "name": {"type": "string"},
+        "pets": {"type": "array", "items": {"$ref": "#/definitions/Pet"}},
+        "tag": {"$ref": "#/definitions/Tag"}
+      }
+    },
+    "Tag": {"type": "string"},
+    "Unused": {"type": "integer"},
+    "Broken": {"$ref": "#/definitions/Missing"}
+  }
+}`)
+	data, err := swagger.ExtractJSONSchema("Pet")
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+  "$schema": "http://json-schema.org/draft-07/schema#",
+  "type": "object",
+  "properties": {
+    "owner": {"$ref": "#/definitions/Owner"},
+    "tags": {"type": "array", "items": {"$ref": "#/definitions/Tag"}},
+    "parent": {"$ref": "#"}
+  },
+  "definitions": {
+    "Owner": {
+      "type": "object",
+      "properties": {
+        "name": {"type": "string"},
+        "pets": {"type": "array", "items": {"$ref": "#"}},
+        "tag": {"$ref": "#/definitions/Tag"}
+      }
+    },
+    "Tag": {"type": "string"}
+  }
+}`, string(data))
+
+	data, err = swagger.ExtractJSONSchema("Tag")
+	require.NoError(t, err)
+	require.JSONEq(t, `{"$schema": "http://json-schema.org/draft-07/schema#", "type": "string"}`, string(data))
+
+	_, err = swagger.ExtractJSONSchema("Missing")
+	require.EqualError(t, err, "Definition 'Missing' does not exist")
+	_, err = swagger.ExtractJSONSchema("Broken")
+	require.EqualError(t, err, "Failed to resolve ref: '#/definitions/Missing'")
+}
+
+func TestExtractJSONSchemaEscapedNames(t *testing.T) {
+	swagger := loadSwagger(t, `
+{
+  "info": {"title": "MyAPI", "version": "0.1"},
+  "definitions": {
+    "Pet": {
+      "type": "object",
+      "properties": {
+        "name": {"type": "string"},
+        "nickname": {"$ref": "#/definitions/Pet/properties/name"},
+        "owner": {"$ref": "#/definitions/Foo~1Bar"}
+      }
+    },
+    "Foo/Bar": {"type": "string"}
+  }
+}`)
+	data, err := swagger.ExtractJSONSchema("Pet")
+	require.NoError(t, err)
+	require.JSONEq(t, `
+{
+  "$schema": "http://json-schema.org/draft-07/schema#",
+  "type": "object",
+  "properties": {
+    "name": {"type": "string"},
+    "nickname": {"$ref": "#/properties/name"},
+    "owner": {"$ref": "#/definitions/Foo~1Bar"}
+  },
+  "definitions": {
+    "Foo/Bar": {"type": "string"}
+  }
+}`, string(data))
+}
@@ -0,0 +1,251 @@
+package openapi2
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Rule is a check run by Swagger.Lint.
+// It returns one error for each problem found in the document.
+type Rule func(swagger *Swagger) []error
+
+// LintError describes a problem found by a Rule or by Swagger.Validate.
+type LintError struct {
+	// Pointer is the JSON pointer of the offending element, such as "/paths/~1pets/get".
+	Pointer string
+	Reason  string
+}
+
+func (err *LintError) Error() string {
+	return fmt.Sprintf("%s: %s", err.Pointer, err.Reason)
+}
+
+// Lint runs the given rules and returns all problems they found, in rule order.
+func (swagger *Swagger) Lint(rules ...Rule) []error {
+	var errs []error
+	for _, rule := range rules {
+		errs = append(errs, rule(swagger)...)
+	}
+	return errs
+}
+
+// OperationsHaveDescriptions is a Rule requiring every operation to have a description.
+func OperationsHaveDescriptions(swagger *Swagger) []error {
+	var errs []error
+	swagger.walkOperations(func(path string, method string, operation *Operation) {
+		if strings.TrimSpace(operation.Description) == "" {
+			errs = append(errs, &LintError{
+				Pointer: operationPointer(path, method),
+				Reason:  "Operation has no description",
+			})
+		}
+	})
+	return errs
+}
+
+// OperationsHaveOperationIDs is a Rule requiring every operation to have an operationId.
+func OperationsHaveOperationIDs(swagger *Swagger) []error {
+	var errs []error
+	swagger.walkOperations(func(path string, method string, operation *Operation) {
+		if operation.OperationID == "" {
+			errs = append(errs, &LintError{
+				Pointer: operationPointer(path, method),
+				Reason:  "Operation has no operationId",
+			})
+		}
+	})
+	return errs
+}
+
+// OperationIDsAreUnique is a Rule requiring operationIds to be unique in the document.
+func OperationIDsAreUnique(swagger *Swagger) []error {
+	var errs []error
+	seen := make(map[string]string)
+	swagger.walkOperations(func(path string, method string, operation *Operation) {
+		id := operation.OperationID
+		if id == "" {
+			return
+		}
+		pointer := operationPointer(path, method)
+		if other, ok := seen[id]; ok {
+			errs = append(errs, &LintError{
+				Pointer: pointer,
+				Reason:  fmt.Sprintf("Operation ID '%s' is already used by '%s'", id, other),
+			})
+			return
+		}
+		seen[id] = pointer
+	})
+	return errs
+}
+
+// SuccessResponsesHaveSchemas is a Rule requiring every 2xx response to have a schema.
+// "204" responses are exempt as they carry no content.
+func SuccessResponsesHaveSchemas(swagger *Swagger) []error {
+	var errs []error
+	swagger.walkOperations(func(path string, method string, operation *Operation) {
+		for _, status := range sortedResponseKeys(operation.Responses) {
+			if len(status) != 3 || status[0] != '2' || status == "204" {
+				continue
+			}
+			pointer := operationPointer(path, method) + "/responses/" + status
+			response, err := swagger.resolveResponse(operation.Responses[status])
+			if err != nil {
+				errs = append(errs, &LintError{Pointer: pointer, Reason: err.Error()})
+				continue
+			}
+			if response == nil || response.Schema == nil {
+				errs = append(errs, &LintError{
+					Pointer: pointer,
+					Reason:  "Success response has no schema",
+				})
+			}
+		}
+	})
+	return errs
+}
+
+// OperationRef locates an operation of a document.
+type OperationRef struct {
+	Path      string
+	Method    string
+	Operation *Operation
+}
+
+// OperationsWithoutSuccessResponse returns the operations that declare neither a success response,
+// with a "2XX" range or a 2xx status code, nor a "default" response, sorted by path and method.
+// A response whose $ref can't be resolved isn't counted.
+func (swagger *Swagger) OperationsWithoutSuccessResponse() []OperationRef {
+	var result []OperationRef
+	swagger.walkOperations(func(path string, method string, operation *Operation) {
+		for key, response := range operation.Responses {
+			status := strings.ToUpper(strings.TrimSpace(key))
+			if status != "DEFAULT" && (len(status) != 3 || status[0] != '2') {
+				continue
+			}
+			if response, err := swagger.resolveResponse(response); err == nil && response != nil {
+				return
+			}
+		}
+		result = append(result, OperationRef{Path: path, Method: method, Operation: operation})
+	})
+	return result
+}
+
+// OperationsHaveSuccessResponses is a Rule requiring every operation to have
+// a success or "default" response, see Swagger.OperationsWithoutSuccessResponse.
+func OperationsHaveSuccessResponses(swagger *Swagger) []error {
+	var errs []error
+	for _, operation := range swagger.OperationsWithoutSuccessResponse() {
+		errs = append(errs, &LintError{
+			Pointer: operationPointer(operation.Path, operation.Method) + "/responses",
+			Reason:  "Operation has no success or default response",
+		})
+	}
+	return errs
+}
+
+// AmbiguousPaths returns the groups of path templates that a router can't tell apart.
+//
+// Two templates collide when they have the same number of segments, ignoring a trailing "/",
+// and every pair of segments at the same position either matches once variable names are
+// ignored, as "/users/{id}" and "/users/{userId}", or pairs a literal with a variable
+// segment that shadows it, as "/users/me" and "/users/{id}".
+// Templates colliding directly or through another one form a group.
+// Each group is sorted, and groups are sorted by their first path.
+func (swagger *Swagger) AmbiguousPaths() [][]string {
+	paths := swagger.sortedPaths()
+	segments := make([][]string, len(paths))
+	for i, path := range paths {
+		segments[i] = pathSegments(path)
+	}
+	// Union-find over the path indices, keeping the smallest index as the root
+	parents := make([]int, len(paths))
+	for i := range parents {
+		parents[i] = i
+	}
+	var root func(i int) int
+	root = func(i int) int {
+		if parents[i] != i {
+			parents[i] = root(parents[i])
+		}
+		return parents[i]
+	}
+	for i := range paths {
+		for j := i + 1; j < len(paths); j++ {
+			if segmentsCollide(segments[i], segments[j]) {
+				a, b := root(i), root(j)
+				if a > b {
+					a, b = b, a
+				}
+				parents[b] = a
+			}
+		}
+	}
+	groups := make(map[int][]string)
+	var roots []int
+	for i, path := range paths {
+		r := root(i)
+		if _, ok := groups[r]; !ok {
+			roots = append(roots, r)
+		}
+		groups[r] = append(groups[r], path)
+	}
+	var result [][]string
+	for _, r := range roots {
+		if group := groups[r]; len(group) > 1 {
+			result = append(result, group)
+		}
+	}
+	return result
+}
+
+// pathSegments splits a path template into segments, with variable names removed,
+// such as []string{"users", "{}"} for "/users/{id}".
+func pathSegments(path string) []string {
+	path = strings.TrimRight(path, "/")
+	segments := strings.Split(strings.TrimPrefix(path, "/"), "/")
+	for i, segment := range segments {
+		var sb strings.Builder
+		for {
+			start := strings.IndexByte(segment, '{')
+			end := strings.IndexByte(segment, '}')
+			if start < 0 || end < start {
+				sb.WriteString(segment)
+				break
+			}
+			sb.WriteString(segment[:start])
+			sb.WriteString("{}")
+			segment = segment[end+1:]
+		}
+		segments[i] = sb.String()
+	}
+	return segments
+}
+
+func segmentsCollide(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] && a[i] != "{}" && b[i] != "{}" {
+			return false
+		}
+	}
+	return true
+}
+
+// PathsAreUnambiguous is a Rule requiring path templates not to collide,
+// see Swagger.AmbiguousPaths. Every path of a group but the first is reported.
+func PathsAreUnambiguous(swagger *Swagger) []error {
+	var errs []error
+	for _, group := range swagger.AmbiguousPaths() {
+		for _, path := range group[1:] {
+			errs = append(errs, &LintError{
+				Pointer: pathPointer(path),
+				Reason:  fmt.Sprintf("Path is ambiguous with '%s'", group[0]),
+			})
+		}
+	}
+	return errs
+}
@@ -0,0 +1,132 @@
+package openapi2_test
+
+import (
+	"testing"
+
+	"github.com/mbilski/kin-openapi/openapi2"
+	"github.com/stretchr/testify/require"
+)
+
+// requireTagsRule is an organization-specific rule: every operation must be tagged.
+func requireTagsRule(swagger *openapi2.Swagger) []error {
+	var errs []error
+	for path, pathItem := range swagger.Paths {
+		for method, operation := range pathItem.Operations() {
+			if len(operation.Tags) == 0 {
+				errs = append(errs, &openapi2.LintError{
+					Pointer: method + " " + path,
+					Reason:  "Operation has no tags",
+				})
+			}
+		}
+	}
+	return errs
+}
+
+func TestLint(t *testing.T) {
+	swagger := loadSwagger(t, `
+{
+  "info": {"title": "MyAPI", "version": "0.1"},
+  "paths": {
+    "/pets": {
+      "get": {
+        "operationId": "listPets",
+        "description": "Lists pets",
+        "tags": ["pets"],
+        "responses": {"200": {"description": "ok", "schema": {"type": "array"}}}
+      },
+      "post": {
+        "operationId": "listPets",
+        "responses": {"201": {"description": "created"}, "204": {"description": "nothing"}}
+      }
+    }
+  }
+}`)
+
+	require.Empty(t, swagger.Lint())
+
+	errs := swagger.Lint(
+		openapi2.OperationsHaveDescriptions,
+		openapi2.OperationsHaveOperationIDs,
+		openapi2.OperationIDsAreUnique,
+		openapi2.SuccessResponsesHaveSchemas,
+		requireTagsRule,
+	)
+	var messages []string
+	for _, err := range errs {
+		messages = append(messages, err.Error())
+	}
+	require.Equal(t, []string{
+		"/paths/~1pets/post: Operation has no description",
+		"/paths/~1pets/post: Operation ID 'listPets' is already used by '/paths/~1pets/get'",
+		"/paths/~1pets/post/responses/201: Success response has no schema",
+		"POST /pets: Operation has no tags",
+	}, messages)
+}
+
+func TestOperationsWithoutSuccessResponse(t *testing.T) {
+	swagger := loadSwagger(t, `
+{
+  "info": {"title": "MyAPI", "version": "0.1"},
+  "responses": {
+    "Ok": {"description": "ok"}
+  },
+  "paths": {
+    "/pets": {
+      "get": {"responses": {"404": {"description": "not found"}}},
+      "post": {"responses": {"default": {"description": "anything"}}},
+      "put": {"responses": {"2XX": {"description": "ok"}}},
+      "delete": {"responses": {"204": {"$ref": "#/responses/Ok"}}}
+    },
+    "/owners": {
+      "get": {"responses": {"200": {"$ref": "#/responses/Missing"}, "500": {"description": "error"}}}
+    }
+  }
+}`)
+	operations := swagger.OperationsWithoutSuccessResponse()
+	require.Len(t, operations, 2)
+	require.Equal(t, "/owners", operations[0].Path)
+	require.Equal(t, "GET", operations[0].Method)
+	require.Equal(t, "/pets", operations[1].Path)
+	require.Equal(t, "GET", operations[1].Method)
+	require.True(t, swagger.Paths["/pets"].Get == operations[1].Operation)
+
+	var messages []string
+	for _, err := range swagger.Lint(openapi2.OperationsHaveSuccessResponses) {
+		messages = append(messages, err.Error())
+	}
+	require.Equal(t, []string{
+		"/paths/~1owners/get/responses: Operation has no success or default response",
+		"/paths/~1pets/get/responses: Operation has no success or default response",
+	}, messages)
+}
+
+func TestAmbiguousPaths(t *testing.T) {
+	swagger := loadSwagger(t, `
+{
+  "info": {"title": "MyAPI", "version": "0.1"},
+  "paths": {
+    "/users/{id}": {},
+    "/users/{userId}": {},
+    "/users/{id}/posts": {},
+    "/pets/me": {},
+    "/pets/{petId}/": {},
+    "/files/{name}.json": {},
+    "/files/{name}.xml": {},
+    "/orders": {}
+  }
+}`)
+	require.Equal(t, [][]string{
+		{"/pets/me", "/pets/{petId}/"},
+		{"/users/{id}", "/users/{userId}"},
+	}, swagger.AmbiguousPaths())
+
+	var messages []string
+	for _, err := range swagger.Lint(openapi2.PathsAreUnambiguous) {
+		messages = append(messages, err.Error())
+	}
+	require.Equal(t, []string{
+		"/paths/~1pets~1{petId}~1: Path is ambiguous with '/pets/me'",
+		"/paths/~1users~1{userId}: Path is ambiguous with '/users/{id}'",
+	}, messages)
+}
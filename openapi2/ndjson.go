@@ -0,0 +1,90 @@
+package openapi2
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/mbilski/kin-openapi/openapi3"
+)
+
+// LineError reports an invalid line of a newline-delimited JSON (NDJSON) body.
+type LineError struct {
+	// Line is the number of the line, starting at 1.
+	Line int
+	Err  error
+}
+
+func (err *LineError) Error() string {
+	return fmt.Sprintf("Line %d: %v", err.Line, err.Err)
+}
+
+// ValidateNDJSONResponse validates a newline-delimited JSON (NDJSON) response body,
+// as written by streaming endpoints, by validating each line against the schema of an item.
+// OpenAPI 2 can't describe such a body, so the item schema is given by the caller,
+// such as a definition of the document.
+//
+// The body is read line by line, without being buffered whole.
+// Blank lines, and a line ending with "\r\n", are accepted.
+// The first invalid line is returned as a *LineError, and a reading failure as it is.
+// Local schema references are resolved on a copy, like ValidateRequest does,
+// or come from the cache of Swagger.ResolveParameters for a definition of the document.
+// Without an item schema, the lines are only checked to be JSON values.
+func ValidateNDJSONResponse(r io.Reader, itemSchema *openapi3.SchemaRef, swagger *Swagger) error {
+	return ValidateNDJSONResponseWithOptions(r, itemSchema, swagger, ValidationOptions{})
+}
+
+// ValidateNDJSONResponseWithOptions is like ValidateNDJSONResponse, configured by the given options.
+// With AllNDJSONLines, the whole body is read and every invalid line is reported.
+func ValidateNDJSONResponseWithOptions(r io.Reader, itemSchema *openapi3.SchemaRef, swagger *Swagger, opts ValidationOptions) error {
+	var schema *openapi3.Schema
+	if itemSchema != nil {
+		schemaRef, err := swagger.validationSchema(itemSchema)
+		if err != nil {
+			return err
+		}
+		schema = schemaRef.Value
+	}
+	var errs MultiError
+	reader := bufio.NewReader(r)
+	for line := 1; ; line++ {
+		data, readErr := reader.ReadBytes('\n')
+		if readErr != nil && readErr != io.EOF {
+			return readErr
+		}
+		if data = bytes.TrimSpace(data); len(data) > 0 {
+			if err := validateNDJSONLine(data, schema, opts); err != nil {
+				lineErr := &LineError{Line: line, Err: err}
+				if !opts.AllNDJSONLines {
+					return lineErr
+				}
+				errs = append(errs, lineErr)
+			}
+		}
+		if readErr == io.EOF {
+			break
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// validateNDJSONLine validates a line of an NDJSON body against the schema of an item, if any.
+func validateNDJSONLine(data []byte, schema *openapi3.Schema, opts ValidationOptions) error {
+	value, err := decodeJSONValue(data)
+	if err != nil {
+		return fmt.Errorf("Value is not valid JSON: %v", err)
+	}
+	if schema == nil {
+		return nil
+	}
+	if opts.StrictInteger {
+		if err := checkStrictIntegers(schema, value); err != nil {
+			return err
+		}
+	}
+	return visitJSONSchema(schema, jsonNumbersToFloats(value))
+}
@@ -0,0 +1,45 @@
+package openapi2_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mbilski/kin-openapi/openapi2"
+	"github.com/mbilski/kin-openapi/openapi3"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateNDJSONResponse(t *testing.T) {
+	swagger := loadSwagger(t, `
+{
+  "info": {"title": "MyAPI", "version": "0.1"},
+  "definitions": {
+    "Event": {
+      "type": "object",
+      "required": ["id"],
+      "properties": {"id": {"type": "integer"}}
+    }
+  },
+  "paths": {}
+}`)
+	itemSchema := &openapi3.SchemaRef{Ref: "#/definitions/Event"}
+	body := "{\"id\": 1}\r\n\n{\"id\": \"two\"}\n{\"id\": 3}\n{\"id\"\n"
+
+	err := openapi2.ValidateNDJSONResponse(strings.NewReader(body), itemSchema, swagger)
+	require.IsType(t, &openapi2.LineError{}, err)
+	require.Equal(t, 3, err.(*openapi2.LineError).Line)
+	require.Contains(t, err.Error(), "Line 3: ")
+
+	err = openapi2.ValidateNDJSONResponseWithOptions(strings.NewReader(body), itemSchema, swagger, openapi2.ValidationOptions{AllNDJSONLines: true})
+	require.IsType(t, openapi2.MultiError{}, err)
+	var lines []int
+	for _, err := range err.(openapi2.MultiError) {
+		lines = append(lines, err.(*openapi2.LineError).Line)
+	}
+	require.Equal(t, []int{3, 5}, lines)
+	require.Contains(t, err.Error(), "Line 5: Value is not valid JSON")
+
+	// Blank lines and a missing trailing newline are fine
+	err = openapi2.ValidateNDJSONResponse(strings.NewReader("\n{\"id\": 1}\n\n{\"id\": 2}"), itemSchema, swagger)
+	require.NoError(t, err)
+}
@@ -0,0 +1,113 @@
+package openapi2
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// NormalizeOptions configures Swagger.NormalizeAndValidate.
+// Each normalization step runs only when enabled.
+type NormalizeOptions struct {
+	// TrimKeys trims the whitespace around the keys of paths, definitions,
+	// shared parameters and shared responses, and rewrites the $refs pointing at them.
+	TrimKeys bool
+	// ResponseKeys canonicalizes the response keys of the operations, as Swagger.NormalizeResponseKeys does.
+	ResponseKeys bool
+	// MediaTypes removes the duplicate media types of the "consumes" and "produces" lists,
+	// as Swagger.NormalizeMediaTypes does.
+	MediaTypes bool
+	// OperationIDs fills the missing operationIds, as Swagger.FillMissingOperationIDs does.
+	OperationIDs bool
+	// Validation configures the validation that follows the normalization.
+	Validation ValidationOptions
+}
+
+// NormalizeAndValidate repairs the trivially fixable problems of the document enabled by opts,
+// in the order of the fields of NormalizeOptions, then validates it, as ValidateWithOptions does.
+//
+// It returns a MultiError of *LintError holding the problems that remain, or nil:
+// first the keys that couldn't be normalized, such as a key colliding with another one
+// once trimmed, which are left as they are, then the validation problems.
+func (swagger *Swagger) NormalizeAndValidate(ctx context.Context, opts NormalizeOptions) error {
+	var errs MultiError
+	if opts.TrimKeys {
+		errs = append(errs, swagger.trimKeys()...)
+	}
+	if opts.ResponseKeys {
+		swagger.walkOperations(func(path string, method string, operation *Operation) {
+			if err := operation.NormalizeResponseKeys(); err != nil {
+				errs = append(errs, &LintError{Pointer: operationPointer(path, method) + "/responses", Reason: err.Error()})
+			}
+		})
+	}
+	if opts.MediaTypes {
+		swagger.NormalizeMediaTypes()
+	}
+	if opts.OperationIDs {
+		swagger.FillMissingOperationIDs()
+	}
+	if err := swagger.ValidateWithOptions(ctx, opts.Validation); err != nil {
+		errs = append(errs, err.(MultiError)...)
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// trimKeys trims the keys of paths, definitions, shared parameters and shared responses.
+func (swagger *Swagger) trimKeys() []error {
+	var errs []error
+	for _, path := range untrimmed(swagger.sortedPaths()) {
+		trimmed := strings.TrimSpace(path)
+		if _, ok := swagger.Paths[trimmed]; ok {
+			errs = append(errs, keyCollision(pathPointer(path), trimmed))
+			continue
+		}
+		swagger.Paths[trimmed] = swagger.Paths[path]
+		delete(swagger.Paths, path)
+	}
+	for _, name := range untrimmed(sortedSchemaNames(swagger.Definitions)) {
+		trimmed := strings.TrimSpace(name)
+		if err := swagger.RenameDefinition(name, trimmed); err != nil {
+			errs = append(errs, keyCollision("/definitions/"+escapePointerToken(name), trimmed))
+		}
+	}
+	for _, name := range untrimmed(sortedParameterNames(swagger.Parameters)) {
+		trimmed := strings.TrimSpace(name)
+		if _, ok := swagger.Parameters[trimmed]; ok {
+			errs = append(errs, keyCollision("/parameters/"+escapePointerToken(name), trimmed))
+			continue
+		}
+		swagger.rewriteRefs(parametersPrefix+escapePointerToken(name), parametersPrefix+escapePointerToken(trimmed))
+		swagger.Parameters[trimmed] = swagger.Parameters[name]
+		delete(swagger.Parameters, name)
+	}
+	for _, name := range untrimmed(sortedResponseKeys(swagger.Responses)) {
+		trimmed := strings.TrimSpace(name)
+		if _, ok := swagger.Responses[trimmed]; ok {
+			errs = append(errs, keyCollision("/responses/"+escapePointerToken(name), trimmed))
+			continue
+		}
+		swagger.rewriteRefs(responsesPrefix+escapePointerToken(name), responsesPrefix+escapePointerToken(trimmed))
+		swagger.Responses[trimmed] = swagger.Responses[name]
+		delete(swagger.Responses, name)
+	}
+	return errs
+}
+
+func keyCollision(pointer string, trimmed string) error {
+	return &LintError{Pointer: pointer, Reason: fmt.Sprintf("Key can't be trimmed, as '%s' already exists", trimmed)}
+}
+
+// untrimmed returns the keys that have whitespace around them.
+func untrimmed(keys []string) []string {
+	var result []string
+	for _, key := range keys {
+		if strings.TrimSpace(key) != key {
+			result = append(result, key)
+		}
+	}
+	return result
+}
@@ -0,0 +1,62 @@
+package openapi2_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mbilski/kin-openapi/openapi2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeAndValidate(t *testing.T) {
+	load := func() *openapi2.Swagger {
+		return loadSwagger(t, `
+{
+  "info": {"title": "MyAPI", "version": "0.1"},
+  "produces": ["application/json", "application/json"],
+  "definitions": {
+    " Pet ": {"type": "object"}
+  },
+  "paths": {
+    " /pets": {
+      "get": {
+        "parameters": [{"in": "query", "name": "limit", "type": "int"}],
+        "responses": {
+          " 200 ": {"description": "pets", "schema": {"type": "array", "items": {"$ref": "#/definitions/ Pet "}}}
+        }
+      }
+    }
+  }
+}`)
+	}
+	ctx := context.Background()
+
+	swagger := load()
+	err := swagger.NormalizeAndValidate(ctx, openapi2.NormalizeOptions{})
+	require.EqualError(t, err, "/paths/ ~1pets/get/parameters/0: Parameter 'limit' has unsupported type 'int'")
+	require.Contains(t, swagger.Paths, " /pets")
+	require.Equal(t, []string{"application/json", "application/json"}, swagger.Produces)
+
+	swagger = load()
+	err = swagger.NormalizeAndValidate(ctx, openapi2.NormalizeOptions{
+		TrimKeys:     true,
+		ResponseKeys: true,
+		MediaTypes:   true,
+		OperationIDs: true,
+	})
+	// The fixable issues are repaired, and only the real problem remains
+	require.EqualError(t, err, "/paths/~1pets/get/parameters/0: Parameter 'limit' has unsupported type 'int'")
+	require.Equal(t, []string{"application/json"}, swagger.Produces)
+	require.Contains(t, swagger.Definitions, "Pet")
+	operation := swagger.Paths["/pets"].Get
+	require.NotEmpty(t, operation.OperationID)
+	require.Equal(t, "#/definitions/Pet", operation.Responses["200"].Schema.Value.Items.Ref)
+
+	swagger = load()
+	swagger.Definitions["Pet"] = swagger.Definitions[" Pet "]
+	err = swagger.NormalizeAndValidate(ctx, openapi2.NormalizeOptions{TrimKeys: true})
+	require.Equal(t, openapi2.MultiError{
+		&openapi2.LintError{Pointer: "/definitions/ Pet ", Reason: "Key can't be trimmed, as 'Pet' already exists"},
+		&openapi2.LintError{Pointer: "/paths/~1pets/get/parameters/0", Reason: "Parameter 'limit' has unsupported type 'int'"},
+	}, err)
+}
@@ -8,18 +8,24 @@
 package openapi2
 
 import (
+	"encoding/json"
 	"fmt"
+	"strings"
 
 	"github.com/mbilski/kin-openapi/jsoninfo"
 	"github.com/mbilski/kin-openapi/openapi3"
 )
 
 type Swagger struct {
+	ExtensionProps
+
 	Info                openapi3.Info                  `json:"info"`
 	ExternalDocs        *openapi3.ExternalDocs         `json:"externalDocs,omitempty"`
 	Schemes             []string                       `json:"schemes,omitempty"`
 	Host                string                         `json:"host,omitempty"`
 	BasePath            string                         `json:"basePath,omitempty"`
+	Consumes            []string                       `json:"consumes,omitempty"`
+	Produces            []string                       `json:"produces,omitempty"`
 	Paths               map[string]*PathItem           `json:"paths,omitempty"`
 	Definitions         map[string]*openapi3.SchemaRef `json:"definitions,omitempty,noref"`
 	Parameters          map[string]*Parameter          `json:"parameters,omitempty,noref"`
@@ -27,8 +33,43 @@ type Swagger struct {
 	SecurityDefinitions map[string]*SecurityScheme     `json:"securityDefinitions,omitempty"`
 	Security            SecurityRequirements           `json:"security,omitempty"`
 	Tags                openapi3.Tags                  `json:"tags,omitempty"`
+
+	// Comments are the YAML comments of the document, captured by SwaggerLoader.PreserveComments
+	// and written back by MarshalYAMLWithComments.
+	Comments Comments `json:"-" yaml:"-"`
+
+	// validation caches what ResolveParameters resolves for ValidateRequest, nil until then.
+	// It isn't modified once set, so that a validation never writes to the document.
+	validation *validationCache
+}
+
+func (swagger *Swagger) MarshalJSON() ([]byte, error) {
+	return jsoninfo.MarshalStrictStruct(swagger)
+}
+
+func (swagger *Swagger) UnmarshalJSON(data []byte) error {
+	return jsoninfo.UnmarshalStrictStruct(data, swagger)
 }
 
+// ToMap returns the document as generic JSON values, as written by MarshalJSON,
+// for consumers such as Go templates.
+// Extensions are keys of the object holding them, response status codes are string keys,
+// and numbers are float64.
+// Go maps have no order, so the source order of keys is lost.
+func (swagger *Swagger) ToMap() (map[string]interface{}, error) {
+	data, err := json.Marshal(swagger)
+	if err != nil {
+		return nil, err
+	}
+	var result map[string]interface{}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// AddOperation sets the operation of an HTTP method on a path, creating the path item if needed.
+// The method is case-insensitive, like for PathItem.SetOperation.
 func (swagger *Swagger) AddOperation(path string, method string, operation *Operation) {
 	paths := swagger.Paths
 	if paths == nil {
@@ -44,6 +85,8 @@ func (swagger *Swagger) AddOperation(path string, method string, operation *Oper
 }
 
 type PathItem struct {
+	ExtensionProps
+
 	Ref        string     `json:"$ref,omitempty"`
 	Delete     *Operation `json:"delete,omitempty"`
 	Get        *Operation `json:"get,omitempty"`
@@ -55,34 +98,65 @@ type PathItem struct {
 	Parameters Parameters `json:"parameters,omitempty"`
 }
 
+func (pathItem *PathItem) MarshalJSON() ([]byte, error) {
+	return jsoninfo.MarshalStrictStruct(pathItem)
+}
+
+func (pathItem *PathItem) UnmarshalJSON(data []byte) error {
+	return jsoninfo.UnmarshalStrictStruct(data, pathItem)
+}
+
 func (pathItem *PathItem) Operations() map[string]*Operation {
-	operations := make(map[string]*Operation, 8)
+	return pathItem.OperationsInto(make(map[string]*Operation, 8))
+}
+
+// OperationsInto is like Operations, but fills dst, after clearing it, instead of allocating a map.
+// It returns dst, or a new map when dst is nil.
+func (pathItem *PathItem) OperationsInto(dst map[string]*Operation) map[string]*Operation {
+	if dst == nil {
+		dst = make(map[string]*Operation, 8)
+	}
+	for method := range dst {
+		delete(dst, method)
+	}
+	pathItem.ForEachOperation(func(method string, operation *Operation) {
+		dst[method] = operation
+	})
+	return dst
+}
+
+// ForEachOperation calls fn with each operation of the path item, and its method,
+// in the alphabetical order of the methods. Unlike Operations, it doesn't allocate.
+func (pathItem *PathItem) ForEachOperation(fn func(method string, operation *Operation)) {
 	if v := pathItem.Delete; v != nil {
-		operations["DELETE"] = v
+		fn("DELETE", v)
 	}
 	if v := pathItem.Get; v != nil {
-		operations["GET"] = v
+		fn("GET", v)
 	}
 	if v := pathItem.Head; v != nil {
-		operations["HEAD"] = v
+		fn("HEAD", v)
 	}
 	if v := pathItem.Options; v != nil {
-		operations["OPTIONS"] = v
+		fn("OPTIONS", v)
 	}
 	if v := pathItem.Patch; v != nil {
-		operations["PATCH"] = v
+		fn("PATCH", v)
 	}
 	if v := pathItem.Post; v != nil {
-		operations["POST"] = v
+		fn("POST", v)
 	}
 	if v := pathItem.Put; v != nil {
-		operations["PUT"] = v
+		fn("PUT", v)
 	}
-	return operations
 }
 
+// GetOperation returns the operation of an HTTP method, or nil.
+// The method is case-insensitive: "get" and "GET" designate the same operation,
+// whose canonical form is upper-case, as returned by Operations.
+// It panics on an unsupported HTTP method.
 func (pathItem *PathItem) GetOperation(method string) *Operation {
-	switch method {
+	switch strings.ToUpper(method) {
 	case "DELETE":
 		return pathItem.Delete
 	case "GET":
@@ -102,8 +176,10 @@ func (pathItem *PathItem) GetOperation(method string) *Operation {
 	}
 }
 
+// SetOperation sets the operation of an HTTP method, which is case-insensitive like for GetOperation.
+// It panics on an unsupported HTTP method.
 func (pathItem *PathItem) SetOperation(method string, operation *Operation) {
-	switch method {
+	switch strings.ToUpper(method) {
 	case "DELETE":
 		pathItem.Delete = operation
 	case "GET":
@@ -124,6 +200,8 @@ func (pathItem *PathItem) SetOperation(method string, operation *Operation) {
 }
 
 type Operation struct {
+	ExtensionProps
+
 	Summary      string                 `json:"summary,omitempty"`
 	Description  string                 `json:"description,omitempty"`
 	ExternalDocs *openapi3.ExternalDocs `json:"externalDocs,omitempty"`
@@ -134,11 +212,32 @@ type Operation struct {
 	Consumes     []string               `json:"consumes,omitempty"`
 	Produces     []string               `json:"produces,omitempty"`
 	Security     *SecurityRequirements  `json:"security,omitempty"`
+
+	// ResolvedParameters caches the effective parameters of the operation, set by
+	// Swagger.ResolveParameters and used by the request validation. It is never serialized.
+	ResolvedParameters Parameters `json:"-" yaml:"-"`
+}
+
+// SecurityExplicitlyNone reports whether the operation declares an empty security list,
+// such as "security": [], which removes the document security requirements.
+// A missing security list instead inherits them.
+func (operation *Operation) SecurityExplicitlyNone() bool {
+	return operation.Security != nil && len(*operation.Security) == 0
+}
+
+func (operation *Operation) MarshalJSON() ([]byte, error) {
+	return jsoninfo.MarshalStrictStruct(operation)
+}
+
+func (operation *Operation) UnmarshalJSON(data []byte) error {
+	return jsoninfo.UnmarshalStrictStruct(data, operation)
 }
 
 type Parameters []*Parameter
 
 type Parameter struct {
+	ExtensionProps
+
 	Ref          string              `json:"$ref,omitempty"`
 	In           string              `json:"in,omitempty"`
 	Name         string              `json:"name,omitempty"`
@@ -153,16 +252,31 @@ type Parameter struct {
 	Enum         []interface{}       `json:"enum,omitempty"`
 	Minimum      *float64            `json:"minimum,omitempty"`
 	Maximum      *float64            `json:"maximum,omitempty"`
+	MultipleOf   *float64            `json:"multipleOf,omitempty"`
 	MinLength    uint64              `json:"minLength,omitempty"`
 	MaxLength    *uint64             `json:"maxLength,omitempty"`
 	Pattern      string              `json:"pattern,omitempty"`
 	Items        *openapi3.SchemaRef `json:"items,omitempty"`
-	MinItems     uint64              `json:"minItems,omitempty"`
-	MaxItems     *uint64             `json:"maxItems,omitempty"`
-	Default      interface{}         `json:"default,omitempty"`
+	// CollectionFormat is one of "csv" (the default), "ssv", "tsv", "pipes" or "multi".
+	CollectionFormat string      `json:"collectionFormat,omitempty"`
+	MinItems         uint64      `json:"minItems,omitempty"`
+	MaxItems         *uint64     `json:"maxItems,omitempty"`
+	Default          interface{} `json:"default,omitempty"`
+	// AllowEmptyValue declares that a query or formData parameter may be sent without a value, such as "?tag=".
+	AllowEmptyValue bool `json:"allowEmptyValue,omitempty"`
+}
+
+func (parameter *Parameter) MarshalJSON() ([]byte, error) {
+	return jsoninfo.MarshalStrictStruct(parameter)
+}
+
+func (parameter *Parameter) UnmarshalJSON(data []byte) error {
+	return jsoninfo.UnmarshalStrictStruct(data, parameter)
 }
 
 type Response struct {
+	ExtensionProps
+
 	Ref         string                 `json:"$ref,omitempty"`
 	Description string                 `json:"description,omitempty"`
 	Schema      *openapi3.SchemaRef    `json:"schema,omitempty"`
@@ -170,14 +284,41 @@ type Response struct {
 	Examples    map[string]interface{} `json:"examples,omitempty"`
 }
 
+func (response *Response) MarshalJSON() ([]byte, error) {
+	return jsoninfo.MarshalStrictStruct(response)
+}
+
+func (response *Response) UnmarshalJSON(data []byte) error {
+	return jsoninfo.UnmarshalStrictStruct(data, response)
+}
+
 type Header struct {
+	ExtensionProps
+
 	Ref         string `json:"$ref,omitempty"`
 	Description string `json:"description,omitempty"`
 	Type        string `json:"type,omitempty"`
 }
 
+func (header *Header) MarshalJSON() ([]byte, error) {
+	return jsoninfo.MarshalStrictStruct(header)
+}
+
+func (header *Header) UnmarshalJSON(data []byte) error {
+	return jsoninfo.UnmarshalStrictStruct(data, header)
+}
+
 type SecurityRequirements []map[string][]string
 
+// MarshalJSON writes nil requirements as an empty list,
+// so that an operation pointing at them still opts out of security once reloaded.
+func (requirements SecurityRequirements) MarshalJSON() ([]byte, error) {
+	if requirements == nil {
+		return []byte("[]"), nil
+	}
+	return jsoninfo.CurrentJSONLibrary().Marshal([]map[string][]string(requirements))
+}
+
 type SecurityScheme struct {
 	ExtensionProps
 
@@ -203,13 +344,29 @@ func (ss *SecurityScheme) UnmarshalJSON(data []byte) error {
 
 type ExtensionProps struct {
 	Extensions map[string]interface{} `json:"-" yaml:"-"`
+
+	// FieldOrder is the order of the keys in the source document.
+	// It is only recorded by jsoninfo.UnmarshalWithOptions with DecoderOptions.PreserveFieldOrder,
+	// and only honored by jsoninfo.MarshalWithOptions with EncoderOptions.PreserveFieldOrder.
+	FieldOrder []string `json:"-" yaml:"-"`
 }
 
 // Assert that the type implements the interface
 var _ jsoninfo.StrictStruct = &ExtensionProps{}
 
+// decodeExtension decodes the value of an extension into target.
+// Extensions hold raw JSON when decoded, and any value when set in code.
+func decodeExtension(value interface{}, target interface{}) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, target)
+}
+
 // EncodeWith will be invoked by package "jsoninfo"
 func (props *ExtensionProps) EncodeWith(encoder *jsoninfo.ObjectEncoder, value interface{}) error {
+	encoder.EncodeFieldOrder(props.FieldOrder)
 	for k, v := range props.Extensions {
 		if err := encoder.EncodeExtension(k, v); err != nil {
 			return err
@@ -229,5 +386,6 @@ func (props *ExtensionProps) DecodeWith(decoder *jsoninfo.ObjectDecoder, value i
 		result[k] = v
 	}
 	props.Extensions = result
+	props.FieldOrder = decoder.DecodeFieldOrder()
 	return nil
 }
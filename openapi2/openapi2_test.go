@@ -0,0 +1,98 @@
+package openapi2_test
+
+import (
+	"testing"
+
+	"github.com/mbilski/kin-openapi/openapi2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToMap(t *testing.T) {
+	swagger := loadSwagger(t, `
+{
+  "info": {"title": "MyAPI", "version": "0.1"},
+  "x-audience": "public",
+  "paths": {
+    "/pets": {
+      "get": {
+        "x-rate-limit": 100,
+        "responses": {"200": {"description": "list"}}
+      }
+    }
+  }
+}`)
+	m, err := swagger.ToMap()
+	require.NoError(t, err)
+	require.Equal(t, "public", m["x-audience"])
+	get := m["paths"].(map[string]interface{})["/pets"].(map[string]interface{})["get"].(map[string]interface{})
+	require.Equal(t, float64(100), get["x-rate-limit"])
+	response := get["responses"].(map[string]interface{})["200"].(map[string]interface{})
+	require.Equal(t, "list", response["description"])
+}
+
+func TestPathItemOperations(t *testing.T) {
+	get, post := &openapi2.Operation{}, &openapi2.Operation{}
+	pathItem := &openapi2.PathItem{Get: get, Post: post}
+	expected := map[string]*openapi2.Operation{"GET": get, "POST": post}
+	require.Equal(t, expected, pathItem.Operations())
+
+	dst := map[string]*openapi2.Operation{"PUT": {}}
+	require.Equal(t, expected, pathItem.OperationsInto(dst))
+	require.Equal(t, expected, dst)
+	require.Equal(t, expected, pathItem.OperationsInto(nil))
+
+	var methods []string
+	pathItem.ForEachOperation(func(method string, operation *openapi2.Operation) {
+		require.True(t, operation == expected[method])
+		methods = append(methods, method)
+	})
+	require.Equal(t, []string{"GET", "POST"}, methods)
+
+	count := 0
+	fn := func(method string, operation *openapi2.Operation) { count++ }
+	require.Zero(t, testing.AllocsPerRun(100, func() { pathItem.ForEachOperation(fn) }))
+	require.Zero(t, testing.AllocsPerRun(100, func() { pathItem.OperationsInto(dst) }))
+}
+
+func TestPathItemMethodCase(t *testing.T) {
+	get, post := &openapi2.Operation{}, &openapi2.Operation{}
+	swagger := &openapi2.Swagger{}
+	swagger.AddOperation("/pets", "get", get)
+	pathItem := swagger.Paths["/pets"]
+	pathItem.SetOperation("Post", post)
+
+	require.True(t, pathItem.GetOperation("GET") == get)
+	require.True(t, pathItem.GetOperation("post") == post)
+	require.True(t, pathItem.Get == get)
+	require.Equal(t, map[string]*openapi2.Operation{"GET": get, "POST": post}, pathItem.Operations())
+
+	pathItem.SetOperation("GET", nil)
+	require.Nil(t, pathItem.GetOperation("get"))
+	require.Panics(t, func() { pathItem.GetOperation("fetch") })
+}
+
+// operationsSink keeps the maps of BenchmarkOperations from being optimized away.
+var operationsSink map[string]*openapi2.Operation
+
+func BenchmarkOperations(b *testing.B) {
+	pathItem := &openapi2.PathItem{Get: &openapi2.Operation{}, Post: &openapi2.Operation{}}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		operationsSink = pathItem.Operations()
+	}
+}
+
+func BenchmarkForEachOperation(b *testing.B) {
+	pathItem := &openapi2.PathItem{Get: &openapi2.Operation{}, Post: &openapi2.Operation{}}
+	count := 0
+	fn := func(method string, operation *openapi2.Operation) { count++ }
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		pathItem.ForEachOperation(fn)
+	}
+	if count != 2*b.N {
+		b.Fatal("expected 2 operations per iteration")
+	}
+}
@@ -0,0 +1,155 @@
+package openapi2
+
+import (
+	"fmt"
+	"mime"
+	"sort"
+	"strings"
+
+	"github.com/mbilski/kin-openapi/openapi3"
+)
+
+// RequestBodySchema returns the schema of the operation's "body" parameter.
+//
+// Parameter and schema references are resolved against the given document.
+// It returns false when the operation takes no body, takes form data instead,
+// or when its body can't be determined: the specification forbids combining a
+// "body" parameter with "formData" parameters and allows at most one "body" parameter,
+// so such operations (and unresolvable references) also yield false.
+func (operation *Operation) RequestBodySchema(swagger *Swagger) (*openapi3.SchemaRef, bool) {
+	var body *Parameter
+	for _, parameter := range operation.Parameters {
+		parameter, err := swagger.resolveParameter(parameter)
+		if err != nil || parameter == nil {
+			return nil, false
+		}
+		switch parameter.In {
+		case "body":
+			if body != nil {
+				return nil, false
+			}
+			body = parameter
+		case "formData":
+			return nil, false
+		}
+	}
+	if body == nil || body.Schema == nil {
+		return nil, false
+	}
+	schemaRef, err := swagger.resolveSchemaRef(body.Schema)
+	if err != nil {
+		return nil, false
+	}
+	return schemaRef, true
+}
+
+// NormalizeMediaTypes removes duplicates from the operation's "consumes" and "produces" lists
+// and sorts them.
+// Media types are compared case-insensitively, ignoring the order of their parameters,
+// and the first occurrence of a duplicate is kept as written.
+func (operation *Operation) NormalizeMediaTypes() {
+	operation.Consumes = normalizeMediaTypes(operation.Consumes)
+	operation.Produces = normalizeMediaTypes(operation.Produces)
+}
+
+// NormalizeMediaTypes normalizes the "consumes" and "produces" lists of the document
+// and of all its operations, like Operation.NormalizeMediaTypes.
+func (swagger *Swagger) NormalizeMediaTypes() {
+	swagger.Consumes = normalizeMediaTypes(swagger.Consumes)
+	swagger.Produces = normalizeMediaTypes(swagger.Produces)
+	swagger.walkOperations(func(_ string, _ string, operation *Operation) {
+		operation.NormalizeMediaTypes()
+	})
+}
+
+func normalizeMediaTypes(mediaTypes []string) []string {
+	if len(mediaTypes) == 0 {
+		return mediaTypes
+	}
+	seen := make(map[string]struct{}, len(mediaTypes))
+	result := make([]string, 0, len(mediaTypes))
+	for _, mediaType := range mediaTypes {
+		key := mediaTypeKey(mediaType)
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+		result = append(result, mediaType)
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return mediaTypeKey(result[i]) < mediaTypeKey(result[j])
+	})
+	return result
+}
+
+// mediaTypeKey returns the canonical form of a media type, used to compare media types.
+func mediaTypeKey(mediaType string) string {
+	parsed, params, err := mime.ParseMediaType(mediaType)
+	if err != nil {
+		return strings.ToLower(strings.TrimSpace(mediaType))
+	}
+	// FormatMediaType lowercases the type and parameter names, and sorts parameters
+	if formatted := mime.FormatMediaType(parsed, params); formatted != "" {
+		return formatted
+	}
+	return parsed
+}
+
+// NormalizeResponseKeys canonicalizes the status code keys of the operation responses.
+// Keys are trimmed, "default" is lowercased and a range such as "2xx" is uppercased.
+// It fails, leaving the responses unchanged, on a key that isn't a three-digit status code,
+// a range or "default", and on keys that become the same.
+func (operation *Operation) NormalizeResponseKeys() error {
+	if len(operation.Responses) == 0 {
+		return nil
+	}
+	responses := make(map[string]*Response, len(operation.Responses))
+	for _, key := range sortedResponseKeys(operation.Responses) {
+		normalized, err := normalizeResponseKey(key)
+		if err != nil {
+			return err
+		}
+		if _, ok := responses[normalized]; ok {
+			return fmt.Errorf("Response key '%s' duplicates key '%s'", key, normalized)
+		}
+		responses[normalized] = operation.Responses[key]
+	}
+	operation.Responses = responses
+	return nil
+}
+
+// NormalizeResponseKeys normalizes the response keys of every operation,
+// like Operation.NormalizeResponseKeys.
+// It stops at the first operation that fails, in path and method order.
+func (swagger *Swagger) NormalizeResponseKeys() error {
+	var err error
+	swagger.walkOperations(func(path string, method string, operation *Operation) {
+		if err == nil {
+			if opErr := operation.NormalizeResponseKeys(); opErr != nil {
+				err = fmt.Errorf("%s: %v", operationPointer(path, method)+"/responses", opErr)
+			}
+		}
+	})
+	return err
+}
+
+func normalizeResponseKey(key string) (string, error) {
+	normalized := strings.TrimSpace(key)
+	if strings.EqualFold(normalized, "default") {
+		return "default", nil
+	}
+	normalized = strings.ToUpper(normalized)
+	if len(normalized) == 3 && normalized[0] >= '1' && normalized[0] <= '5' {
+		if normalized[1:] == "XX" {
+			return normalized, nil
+		}
+		if isDigit(normalized[1]) && isDigit(normalized[2]) {
+			return normalized, nil
+		}
+	}
+	return "", fmt.Errorf("Invalid response key '%s'", key)
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
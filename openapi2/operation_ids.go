@@ -0,0 +1,56 @@
+package openapi2
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// CanonicalOperationID returns the operationId synthesized for an operation without one.
+//
+// The id is the lowercased method followed by every word of the path template, where a word
+// is a run of letters and digits and starts with an upper-case letter.
+// Braces and other separators are dropped, so "GET /users/{userId}/posts" gives "getUsersUserIdPosts".
+// The id doesn't depend on the rest of the document: see FillMissingOperationIDs to avoid collisions.
+func (swagger *Swagger) CanonicalOperationID(path, method string) string {
+	var sb strings.Builder
+	sb.WriteString(strings.ToLower(method))
+	words := strings.FieldsFunc(path, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for _, word := range words {
+		runes := []rune(word)
+		runes[0] = unicode.ToUpper(runes[0])
+		sb.WriteString(string(runes))
+	}
+	return sb.String()
+}
+
+// FillMissingOperationIDs assigns CanonicalOperationID to every operation without an operationId.
+//
+// When the canonical id is already used, by an existing operationId or an id assigned before,
+// the first free id with a numeric suffix starting at 2 is used instead, such as "getPets2".
+// Operations are visited by path, then method, so the result is stable.
+func (swagger *Swagger) FillMissingOperationIDs() {
+	used := make(map[string]struct{})
+	swagger.walkOperations(func(path string, method string, operation *Operation) {
+		if id := operation.OperationID; id != "" {
+			used[id] = struct{}{}
+		}
+	})
+	swagger.walkOperations(func(path string, method string, operation *Operation) {
+		if operation.OperationID != "" {
+			return
+		}
+		base := swagger.CanonicalOperationID(path, method)
+		id := base
+		for i := 2; ; i++ {
+			if _, ok := used[id]; !ok {
+				break
+			}
+			id = base + strconv.Itoa(i)
+		}
+		used[id] = struct{}{}
+		operation.OperationID = id
+	})
+}
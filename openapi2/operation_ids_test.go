@@ -0,0 +1,48 @@
+package openapi2_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCanonicalOperationID(t *testing.T) {
+	swagger := loadSwagger(t, `{"info": {"title": "MyAPI", "version": "0.1"}}`)
+	for _, test := range []struct{ path, method, id string }{
+		{"/users/{userId}/posts", "GET", "getUsersUserIdPosts"},
+		{"/users/{user_id}/posts", "get", "getUsersUserIdPosts"},
+		{"/pet-store/v2/items.json", "POST", "postPetStoreV2ItemsJson"},
+		{"/", "DELETE", "delete"},
+	} {
+		require.Equal(t, test.id, swagger.CanonicalOperationID(test.path, test.method), test.path)
+	}
+}
+
+func TestFillMissingOperationIDs(t *testing.T) {
+	swagger := loadSwagger(t, `
+{
+  "info": {"title": "MyAPI", "version": "0.1"},
+  "paths": {
+    "/pets": {
+      "get": {"operationId": "listPets", "responses": {"200": {"description": "list"}}},
+      "post": {"responses": {"201": {"description": "created"}}}
+    },
+    "/pets/{id}": {
+      "get": {"responses": {"200": {"description": "pet"}}}
+    },
+    "/pets/{Id}": {
+      "get": {"responses": {"200": {"description": "pet"}}}
+    },
+    "/stores": {
+      "get": {"operationId": "getPetsId", "responses": {"200": {"description": "list"}}}
+    }
+  }
+}`)
+	swagger.FillMissingOperationIDs()
+	require.Equal(t, "listPets", swagger.Paths["/pets"].Get.OperationID)
+	require.Equal(t, "postPets", swagger.Paths["/pets"].Post.OperationID)
+	// "/pets/{Id}" sorts before "/pets/{id}"
+	require.Equal(t, "getPetsId2", swagger.Paths["/pets/{Id}"].Get.OperationID)
+	require.Equal(t, "getPetsId3", swagger.Paths["/pets/{id}"].Get.OperationID)
+	require.Equal(t, "getPetsId", swagger.Paths["/stores"].Get.OperationID)
+}
@@ -0,0 +1,175 @@
+package openapi2_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/mbilski/kin-openapi/openapi2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestBodySchema(t *testing.T) {
+	swagger := loadSwagger(t, `
+{
+  "info": {"title": "MyAPI", "version": "0.1"},
+  "parameters": {
+    "PetBody": {"in": "body", "name": "body", "schema": {"$ref": "#/definitions/Pet"}}
+  },
+  "definitions": {
+    "Pet": {"type": "object", "properties": {"name": {"type": "string"}}}
+  },
+  "paths": {
+    "/inline": {
+      "post": {
+        "parameters": [{"in": "body", "name": "body", "schema": {"type": "string"}}],
+        "responses": {"200": {"description": "ok"}}
+      }
+    },
+    "/ref": {
+      "post": {
+        "parameters": [{"$ref": "#/parameters/PetBody"}],
+        "responses": {"200": {"description": "ok"}}
+      }
+    },
+    "/form": {
+      "post": {
+        "parameters": [{"in": "formData", "name": "name", "type": "string"}],
+        "responses": {"200": {"description": "ok"}}
+      }
+    },
+    "/mixed": {
+      "post": {
+        "parameters": [
+          {"in": "body", "name": "body", "schema": {"type": "string"}},
+          {"in": "formData", "name": "name", "type": "string"}
+        ],
+        "responses": {"200": {"description": "ok"}}
+      }
+    },
+    "/none": {
+      "get": {
+        "parameters": [{"in": "query", "name": "q", "type": "string"}],
+        "responses": {"200": {"description": "ok"}}
+      }
+    }
+  }
+}`)
+
+	schema, ok := swagger.Paths["/inline"].Post.RequestBodySchema(swagger)
+	require.True(t, ok)
+	require.Equal(t, "string", schema.Value.Type)
+
+	schema, ok = swagger.Paths["/ref"].Post.RequestBodySchema(swagger)
+	require.True(t, ok)
+	require.Equal(t, "#/definitions/Pet", schema.Ref)
+	require.Equal(t, "object", schema.Value.Type)
+	require.Contains(t, schema.Value.Properties, "name")
+
+	_, ok = swagger.Paths["/form"].Post.RequestBodySchema(swagger)
+	require.False(t, ok)
+
+	_, ok = swagger.Paths["/mixed"].Post.RequestBodySchema(swagger)
+	require.False(t, ok)
+
+	_, ok = swagger.Paths["/none"].Get.RequestBodySchema(swagger)
+	require.False(t, ok)
+}
+
+func TestNormalizeMediaTypes(t *testing.T) {
+	operation := &openapi2.Operation{
+		Consumes: []string{"application/xml", "Application/JSON", "application/json", "text/plain; charset=utf-8", "TEXT/PLAIN; Charset=utf-8"},
+		Produces: []string{"application/json"},
+	}
+	operation.NormalizeMediaTypes()
+	require.Equal(t, []string{"Application/JSON", "application/xml", "text/plain; charset=utf-8"}, operation.Consumes)
+	require.Equal(t, []string{"application/json"}, operation.Produces)
+
+	swagger := &openapi2.Swagger{
+		Produces: []string{"text/xml", "application/json", "Text/XML"},
+		Paths: map[string]*openapi2.PathItem{
+			"/pets": {Get: &openapi2.Operation{Produces: []string{"image/png", "image/PNG", "image/gif"}}},
+		},
+	}
+	swagger.NormalizeMediaTypes()
+	require.Equal(t, []string{"application/json", "text/xml"}, swagger.Produces)
+	require.Nil(t, swagger.Consumes)
+	require.Equal(t, []string{"image/gif", "image/png"}, swagger.Paths["/pets"].Get.Produces)
+}
+
+func TestOperationSecurity(t *testing.T) {
+	swagger := loadSwagger(t, `
+{
+  "info": {"title": "MyAPI", "version": "0.1"},
+  "security": [{"key": []}],
+  "paths": {
+    "/public": {"get": {"security": [], "responses": {"200": {"description": "ok"}}}},
+    "/private": {"get": {"responses": {"200": {"description": "ok"}}}},
+    "/admin": {"get": {"security": [{"oauth": ["admin"]}], "responses": {"200": {"description": "ok"}}}}
+  }
+}`)
+	public := swagger.Paths["/public"].Get
+	require.NotNil(t, public.Security)
+	require.NotNil(t, *public.Security)
+	require.Empty(t, *public.Security)
+	require.True(t, public.SecurityExplicitlyNone())
+	private := swagger.Paths["/private"].Get
+	require.Nil(t, private.Security)
+	require.False(t, private.SecurityExplicitlyNone())
+	require.False(t, swagger.Paths["/admin"].Get.SecurityExplicitlyNone())
+
+	// An empty list built in code survives a round trip
+	var nilRequirements openapi2.SecurityRequirements
+	for _, security := range []*openapi2.SecurityRequirements{{}, &nilRequirements} {
+		data, err := json.Marshal(&openapi2.Operation{Security: security})
+		require.NoError(t, err)
+		require.JSONEq(t, `{"responses": null, "security": []}`, string(data))
+		var decoded openapi2.Operation
+		require.NoError(t, json.Unmarshal(data, &decoded))
+		require.True(t, decoded.SecurityExplicitlyNone())
+	}
+
+	data, err := json.Marshal(swagger)
+	require.NoError(t, err)
+	require.Contains(t, string(data), `"security":[{"key":[]}]`)
+
+	// Missing document requirements stay missing
+	data, err = json.Marshal(&openapi2.Swagger{})
+	require.NoError(t, err)
+	require.NotContains(t, string(data), `"security"`)
+}
+
+func TestNormalizeResponseKeys(t *testing.T) {
+	ok := &openapi2.Response{Description: "ok"}
+	operation := &openapi2.Operation{Responses: map[string]*openapi2.Response{
+		" 200":    ok,
+		"4xx":     {Description: "client error"},
+		"Default": {Description: "error"},
+	}}
+	require.NoError(t, operation.NormalizeResponseKeys())
+	require.Len(t, operation.Responses, 3)
+	require.True(t, operation.Responses["200"] == ok)
+	require.Contains(t, operation.Responses, "4XX")
+	require.Contains(t, operation.Responses, "default")
+
+	for key, message := range map[string]string{
+		"20O":  "Invalid response key '20O'",
+		"2000": "Invalid response key '2000'",
+		"600":  "Invalid response key '600'",
+		"ok":   "Invalid response key 'ok'",
+	} {
+		operation := &openapi2.Operation{Responses: map[string]*openapi2.Response{key: ok, "200": ok}}
+		require.EqualError(t, operation.NormalizeResponseKeys(), message)
+		require.Contains(t, operation.Responses, key)
+	}
+
+	swagger := loadSwagger(t, `
+{
+  "info": {"title": "MyAPI", "version": "0.1"},
+  "paths": {
+    "/pets": {
+      "get": {"responses": {"200": {"description": "ok"}, "200 ": {"description": "ok"}}}
+    }
+  }
+}`)
+	require.EqualError(t, swagger.NormalizeResponseKeys(), "/paths/~1pets/get/responses: Response key '200 ' duplicates key '200'")
+}
@@ -0,0 +1,33 @@
+package openapi2
+
+// ParameterKey identifies a parameter of an operation by location and name.
+type ParameterKey struct {
+	In   string
+	Name string
+}
+
+// ParameterIndex maps the parameters of an operation by location and name.
+type ParameterIndex map[ParameterKey]*Parameter
+
+// Get returns the parameter with the given location and name, or nil.
+func (index ParameterIndex) Get(in, name string) *Parameter {
+	return index[ParameterKey{In: in, Name: name}]
+}
+
+// BuildParameterIndex returns the index of the effective parameters of every operation:
+// its own parameters and those of its path item that it doesn't override, with $refs resolved.
+// Parameters whose $ref can't be resolved are left out, Swagger.Validate reports them.
+//
+// The index is a snapshot: it must be rebuilt after the document changes.
+func (swagger *Swagger) BuildParameterIndex() map[*Operation]ParameterIndex {
+	result := make(map[*Operation]ParameterIndex)
+	swagger.walkOperations(func(path string, method string, operation *Operation) {
+		parameters, _ := swagger.effectiveParameters(swagger.Paths[path], operation)
+		index := make(ParameterIndex, len(parameters))
+		for _, parameter := range parameters {
+			index[ParameterKey{In: parameter.In, Name: parameter.Name}] = parameter
+		}
+		result[operation] = index
+	})
+	return result
+}
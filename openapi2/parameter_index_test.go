@@ -0,0 +1,86 @@
+package openapi2_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/mbilski/kin-openapi/openapi2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildParameterIndex(t *testing.T) {
+	swagger := loadSwagger(t, `
+{
+  "info": {"title": "MyAPI", "version": "0.1"},
+  "parameters": {
+    "limit": {"in": "query", "name": "limit", "type": "integer"}
+  },
+  "paths": {
+    "/pets/{id}": {
+      "parameters": [
+        {"in": "path", "name": "id", "type": "string", "required": true},
+        {"in": "header", "name": "X-Trace", "type": "string"}
+      ],
+      "get": {
+        "parameters": [
+          {"$ref": "#/parameters/limit"},
+          {"$ref": "#/parameters/missing"},
+          {"in": "path", "name": "id", "type": "integer", "required": true}
+        ],
+        "responses": {"200": {"description": "pet"}}
+      }
+    }
+  }
+}`)
+	operation := swagger.Paths["/pets/{id}"].Get
+	index := swagger.BuildParameterIndex()[operation]
+	require.Len(t, index, 3)
+	require.Equal(t, "integer", index.Get("path", "id").Type)
+	require.Equal(t, swagger.Parameters["limit"], index.Get("query", "limit"))
+	require.NotNil(t, index.Get("header", "X-Trace"))
+	require.Nil(t, index.Get("query", "id"))
+}
+
+func manyParametersSwagger() (*openapi2.Swagger, *openapi2.Operation) {
+	operation := &openapi2.Operation{}
+	for i := 0; i < 50; i++ {
+		operation.Parameters = append(operation.Parameters, &openapi2.Parameter{
+			In:   "query",
+			Name: fmt.Sprintf("param%d", i),
+			Type: "string",
+		})
+	}
+	swagger := &openapi2.Swagger{}
+	swagger.AddOperation("/search", "GET", operation)
+	return swagger, operation
+}
+
+func BenchmarkParameterScan(b *testing.B) {
+	_, operation := manyParametersSwagger()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		name := fmt.Sprintf("param%d", i%50)
+		var found *openapi2.Parameter
+		for _, parameter := range operation.Parameters {
+			if parameter.In == "query" && parameter.Name == name {
+				found = parameter
+				break
+			}
+		}
+		if found == nil {
+			b.Fatal(name)
+		}
+	}
+}
+
+func BenchmarkParameterIndex(b *testing.B) {
+	swagger, operation := manyParametersSwagger()
+	index := swagger.BuildParameterIndex()[operation]
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		name := fmt.Sprintf("param%d", i%50)
+		if index.Get("query", name) == nil {
+			b.Fatal(name)
+		}
+	}
+}
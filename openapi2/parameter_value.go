@@ -0,0 +1,183 @@
+package openapi2
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"strconv"
+	"unicode/utf8"
+
+	"github.com/mbilski/kin-openapi/openapi3"
+)
+
+// validateValue checks that a decoded JSON value satisfies the type and constraints
+// of a non-body parameter.
+// String lengths are counted in Unicode code points.
+// The items of an array value are checked against the "items" of the parameter.
+func (parameter *Parameter) validateValue(value interface{}, opts PatternOptions) error {
+	switch parameter.Type {
+	case "string":
+		s, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("Value %v must be a string", value)
+		}
+		length := uint64(utf8.RuneCountInString(s))
+		if length < parameter.MinLength {
+			return fmt.Errorf("Value '%s' must be at least %d characters long", s, parameter.MinLength)
+		}
+		if max := parameter.MaxLength; max != nil && length > *max {
+			return fmt.Errorf("Value '%s' must be at most %d characters long", s, *max)
+		}
+		if pattern := parameter.Pattern; pattern != "" {
+			re, err := CompilePattern(pattern, opts)
+			if err != nil {
+				return err
+			}
+			if !re.MatchString(s) {
+				return fmt.Errorf("Value '%s' doesn't match pattern '%s'", s, pattern)
+			}
+		}
+	case "number", "integer":
+		number, ok := toFloat64(value)
+		if !ok {
+			return fmt.Errorf("Value %v must be a number", value)
+		}
+		if parameter.Type == "integer" && number != math.Trunc(number) {
+			return fmt.Errorf("Value %v must be an integer", value)
+		}
+		if min := parameter.Minimum; min != nil {
+			if parameter.ExclusiveMin && !(number > *min) {
+				return fmt.Errorf("Value %v must be more than %g", value, *min)
+			}
+			if number < *min {
+				return fmt.Errorf("Value %v must be at least %g", value, *min)
+			}
+		}
+		if max := parameter.Maximum; max != nil {
+			if parameter.ExclusiveMax && !(number < *max) {
+				return fmt.Errorf("Value %v must be less than %g", value, *max)
+			}
+			if number > *max {
+				return fmt.Errorf("Value %v must be at most %g", value, *max)
+			}
+		}
+		if multipleOf := parameter.MultipleOf; multipleOf != nil && *multipleOf != 0 {
+			if !isMultipleOf(number, *multipleOf) {
+				return fmt.Errorf("Value %v must be a multiple of %g", value, *multipleOf)
+			}
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return fmt.Errorf("Value %v must be a boolean", value)
+		}
+	case "array":
+		items, ok := value.([]interface{})
+		if !ok {
+			return fmt.Errorf("Value %v must be an array", value)
+		}
+		count := uint64(len(items))
+		if count < parameter.MinItems {
+			return fmt.Errorf("Value must have at least %d items", parameter.MinItems)
+		}
+		if max := parameter.MaxItems; max != nil && count > *max {
+			return fmt.Errorf("Value must have at most %d items", *max)
+		}
+		if parameter.UniqueItems {
+			for i := range items {
+				for j := 0; j < i; j++ {
+					if reflect.DeepEqual(items[i], items[j]) {
+						return fmt.Errorf("Value has duplicate item %v", items[i])
+					}
+				}
+			}
+		}
+		if itemsParameter := parameter.itemsParameter(); itemsParameter != nil {
+			for i, item := range items {
+				if err := itemsParameter.validateValue(item, opts); err != nil {
+					return fmt.Errorf("Item %d is invalid: %v", i, err)
+				}
+			}
+		}
+	}
+	if enum := parameter.Enum; len(enum) > 0 && !enumContains(enum, value) {
+		return fmt.Errorf("Value %v is not one of the allowed values %v", value, enum)
+	}
+	return nil
+}
+
+// itemsParameter returns the "items" of an array parameter as a parameter,
+// so that array items are validated like parameter values.
+// It returns nil when the items are missing or are a $ref.
+func (parameter *Parameter) itemsParameter() *Parameter {
+	if parameter.Items == nil || parameter.Items.Value == nil {
+		return nil
+	}
+	return schemaParameter(parameter.Items.Value)
+}
+
+func schemaParameter(schema *openapi3.Schema) *Parameter {
+	return &Parameter{
+		Type:         schema.Type,
+		Format:       schema.Format,
+		Enum:         schema.Enum,
+		Minimum:      schema.Min,
+		Maximum:      schema.Max,
+		MultipleOf:   schema.MultipleOf,
+		ExclusiveMin: schema.ExclusiveMin,
+		ExclusiveMax: schema.ExclusiveMax,
+		MinLength:    schema.MinLength,
+		MaxLength:    schema.MaxLength,
+		Pattern:      schema.Pattern,
+		Items:        schema.Items,
+		MinItems:     schema.MinItems,
+		MaxItems:     schema.MaxItems,
+		UniqueItems:  schema.UniqueItems,
+	}
+}
+
+func enumContains(enum []interface{}, value interface{}) bool {
+	for _, item := range enum {
+		if reflect.DeepEqual(item, value) {
+			return true
+		}
+		if a, ok := toFloat64(item); ok {
+			if b, ok := toFloat64(value); ok && a == b {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func toFloat64(value interface{}) (float64, bool) {
+	switch value := value.(type) {
+	case float64:
+		return value, true
+	case float32:
+		return float64(value), true
+	case int:
+		return float64(value), true
+	case int32:
+		return float64(value), true
+	case int64:
+		return float64(value), true
+	case uint64:
+		return float64(value), true
+	}
+	return 0, false
+}
+
+// isMultipleOf reports whether number is a multiple of multipleOf, comparing the decimals they're written as
+// so that binary rounding doesn't reject 0.3 as a multiple of 0.1.
+func isMultipleOf(number float64, multipleOf float64) bool {
+	quotient, ok := new(big.Rat).SetString(strconv.FormatFloat(number, 'g', -1, 64))
+	if !ok {
+		return false
+	}
+	divisor, ok := new(big.Rat).SetString(strconv.FormatFloat(multipleOf, 'g', -1, 64))
+	if !ok {
+		return false
+	}
+	return quotient.Quo(quotient, divisor).IsInt()
+}
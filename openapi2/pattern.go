@@ -0,0 +1,105 @@
+package openapi2
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// PatternOptions configures how "pattern" regular expressions are compiled.
+//
+// Patterns are written for ECMA 262 (JavaScript) regular expressions, while Go uses RE2.
+// Like in ECMA 262, patterns aren't implicitly anchored: use '^' and '$' to match the whole value.
+type PatternOptions struct {
+	// ECMAShim rewrites ECMA 262 constructs that have an RE2 equivalent before compiling:
+	//   * "(?<name>...)" named groups become "(?P<name>...)"
+	//   * "\uFFFF" escapes become "\x{FFFF}"
+	//   * "[^]" (any character, including newlines) becomes "[\s\S]"
+	// Constructs without an RE2 equivalent, such as backreferences and lookarounds, are still rejected.
+	ECMAShim bool
+}
+
+var ecmaUnicodeEscape = regexp.MustCompile(`\\u([0-9a-fA-F]{4})`)
+
+// CompilePattern compiles the regular expression of a "pattern".
+//
+// When the pattern uses a construct that RE2 doesn't support, the error names the construct
+// instead of reporting a generic syntax error.
+func CompilePattern(pattern string, opts PatternOptions) (*regexp.Regexp, error) {
+	source := pattern
+	if opts.ECMAShim {
+		source = ecmaShim(source)
+	}
+	re, err := regexp.Compile(source)
+	if err == nil {
+		return re, nil
+	}
+	if construct := unsupportedPatternConstruct(source); construct != "" {
+		return nil, fmt.Errorf("Pattern '%s' uses %s, which is not supported by Go regular expressions (RE2)", pattern, construct)
+	}
+	return nil, fmt.Errorf("Pattern '%s' is not a valid regular expression: %v", pattern, err)
+}
+
+func ecmaShim(pattern string) string {
+	pattern = strings.Replace(pattern, "[^]", `[\s\S]`, -1)
+	pattern = ecmaUnicodeEscape.ReplaceAllString(pattern, `\x{$1}`)
+	var sb strings.Builder
+	for i := 0; i < len(pattern); i++ {
+		c := pattern[i]
+		if c == '\\' && i+1 < len(pattern) {
+			sb.WriteString(pattern[i : i+2])
+			i++
+			continue
+		}
+		if c == '(' && strings.HasPrefix(pattern[i:], "(?<") &&
+			!strings.HasPrefix(pattern[i:], "(?<=") && !strings.HasPrefix(pattern[i:], "(?<!") {
+			sb.WriteString("(?P<")
+			i += 2
+			continue
+		}
+		sb.WriteByte(c)
+	}
+	return sb.String()
+}
+
+// unsupportedPatternConstruct returns a description of the first ECMA 262 construct
+// that has no RE2 equivalent, or an empty string.
+func unsupportedPatternConstruct(pattern string) string {
+	inClass := false
+	for i := 0; i < len(pattern); i++ {
+		switch c := pattern[i]; c {
+		case '\\':
+			if i+1 >= len(pattern) {
+				return ""
+			}
+			next := pattern[i+1]
+			if !inClass && next >= '1' && next <= '9' {
+				return fmt.Sprintf("a backreference ('\\%c')", next)
+			}
+			if !inClass && next == 'k' && strings.HasPrefix(pattern[i+2:], "<") {
+				return "a named backreference ('\\k<...>')"
+			}
+			i++
+		case '[':
+			inClass = true
+		case ']':
+			inClass = false
+		case '(':
+			if inClass {
+				continue
+			}
+			rest := pattern[i:]
+			switch {
+			case strings.HasPrefix(rest, "(?="):
+				return "a lookahead ('(?=')"
+			case strings.HasPrefix(rest, "(?!"):
+				return "a negative lookahead ('(?!')"
+			case strings.HasPrefix(rest, "(?<="):
+				return "a lookbehind ('(?<=')"
+			case strings.HasPrefix(rest, "(?<!"):
+				return "a negative lookbehind ('(?<!')"
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,72 @@
+package openapi2
+
+import "fmt"
+
+// formatSamples are values of the formats with a known shape.
+// A pattern matching none of them is unlikely to match any value of the format.
+var formatSamples = map[string][]string{
+	"date": {
+		"2006-01-02",
+		"2020-12-31",
+	},
+	"date-time": {
+		"2006-01-02T15:04:05Z",
+		"2020-12-31T23:59:59.999+07:00",
+		"2006-01-02t15:04:05z",
+	},
+	"uuid": {
+		"123e4567-e89b-12d3-a456-426614174000",
+		"123E4567-E89B-12D3-A456-426614174000",
+		"00000000-0000-0000-0000-000000000000",
+	},
+}
+
+// checkPatternFormats warns about string parameters whose pattern contradicts their format.
+//
+// The check is a heuristic: a pattern is compatible when it matches one of a few samples of the format,
+// so a pattern restricting the format further, such as to dates of a given year, may be reported too.
+// A pattern that doesn't compile is reported by the "parameters" rule, and skipped here.
+func (v *validator) checkPatternFormats(swagger *Swagger) []error {
+	var errs []error
+	swagger.walkParameters(func(pointer string, parameter *Parameter) {
+		if parameter.Ref != "" {
+			return
+		}
+		if parameter.Type == "string" && !v.patternMatchesFormat(parameter.Pattern, parameter.Format) {
+			errs = append(errs, &LintError{
+				Pointer: pointer,
+				Reason: fmt.Sprintf("Pattern '%s' of parameter '%s' can't match its format '%s'",
+					parameter.Pattern, parameter.Name, parameter.Format),
+			})
+		}
+		for items := parameter.Items; items != nil && items.Value != nil; items = items.Value.Items {
+			if schema := items.Value; schema.Type == "string" && !v.patternMatchesFormat(schema.Pattern, schema.Format) {
+				errs = append(errs, &LintError{
+					Pointer: pointer,
+					Reason: fmt.Sprintf("Pattern '%s' of items of parameter '%s' can't match their format '%s'",
+						schema.Pattern, parameter.Name, schema.Format),
+				})
+			}
+		}
+	})
+	return errs
+}
+
+// patternMatchesFormat reports whether pattern matches a sample of format.
+// It is true when the format has no samples, or the pattern is missing or invalid.
+func (v *validator) patternMatchesFormat(pattern string, format string) bool {
+	samples, ok := formatSamples[format]
+	if !ok || pattern == "" {
+		return true
+	}
+	re, err := CompilePattern(pattern, v.opts.PatternOptions)
+	if err != nil {
+		return true
+	}
+	for _, sample := range samples {
+		if re.MatchString(sample) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,56 @@
+package openapi2_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mbilski/kin-openapi/openapi2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateIssuesCheckPatternFormats(t *testing.T) {
+	swagger := loadSwagger(t, `
+{
+  "info": {"title": "MyAPI", "version": "0.1"},
+  "paths": {
+    "/pets/{id}": {
+      "get": {
+        "parameters": [
+          {"in": "path", "name": "id", "required": true, "type": "string", "format": "uuid", "pattern": "^[0-9]+$"},
+          {"in": "query", "name": "owner", "type": "string", "format": "uuid", "pattern": "^[0-9a-fA-F-]{36}$"},
+          {"in": "query", "name": "since", "type": "string", "format": "date", "pattern": "^\\d{4}-\\d{2}-\\d{2}$"},
+          {"in": "query", "name": "until", "type": "string", "format": "date-time", "pattern": "^\\d{4}$"},
+          {"in": "query", "name": "name", "type": "string", "pattern": "^[0-9]+$"},
+          {"in": "query", "name": "tags", "type": "array", "items": {"type": "string", "format": "uuid", "pattern": "^x"}}
+        ],
+        "responses": {"200": {"description": "pet"}}
+      }
+    }
+  }
+}`)
+	ctx := context.Background()
+	require.Empty(t, swagger.ValidateIssues(ctx, openapi2.ValidationOptions{}))
+
+	issues := swagger.ValidateIssues(ctx, openapi2.ValidationOptions{CheckPatternFormats: true})
+	require.Equal(t, []openapi2.Issue{
+		{
+			Severity: openapi2.SeverityWarning,
+			Pointer:  "/paths/~1pets~1{id}/get/parameters/0",
+			Rule:     "pattern-formats",
+			Message:  "Pattern '^[0-9]+$' of parameter 'id' can't match its format 'uuid'",
+		},
+		{
+			Severity: openapi2.SeverityWarning,
+			Pointer:  "/paths/~1pets~1{id}/get/parameters/3",
+			Rule:     "pattern-formats",
+			Message:  "Pattern '^\\d{4}$' of parameter 'until' can't match its format 'date-time'",
+		},
+		{
+			Severity: openapi2.SeverityWarning,
+			Pointer:  "/paths/~1pets~1{id}/get/parameters/5",
+			Rule:     "pattern-formats",
+			Message:  "Pattern '^x' of items of parameter 'tags' can't match their format 'uuid'",
+		},
+	}, issues)
+	require.NoError(t, swagger.ValidateWithOptions(ctx, openapi2.ValidationOptions{CheckPatternFormats: true}))
+}
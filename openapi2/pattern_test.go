@@ -0,0 +1,42 @@
+package openapi2_test
+
+import (
+	"testing"
+
+	"github.com/mbilski/kin-openapi/openapi2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompilePatternUnsupported(t *testing.T) {
+	tests := []struct {
+		pattern  string
+		expected string
+	}{
+		{`^(a)\1$`, `Pattern '^(a)\1$' uses a backreference ('\1'), which is not supported by Go regular expressions (RE2)`},
+		{`^(?<x>a)\k<x>$`, `Pattern '^(?<x>a)\k<x>$' uses a named backreference ('\k<...>'), which is not supported by Go regular expressions (RE2)`},
+		{`^a(?=b)`, `Pattern '^a(?=b)' uses a lookahead ('(?='), which is not supported by Go regular expressions (RE2)`},
+		{`(?<!a)b`, `Pattern '(?<!a)b' uses a negative lookbehind ('(?<!'), which is not supported by Go regular expressions (RE2)`},
+	}
+	for _, test := range tests {
+		for _, shim := range []bool{false, true} {
+			_, err := openapi2.CompilePattern(test.pattern, openapi2.PatternOptions{ECMAShim: shim})
+			require.EqualError(t, err, test.expected)
+		}
+	}
+
+	_, err := openapi2.CompilePattern(`^[a-z`, openapi2.PatternOptions{})
+	require.EqualError(t, err, "Pattern '^[a-z' is not a valid regular expression: error parsing regexp: missing closing ]: `[a-z`")
+}
+
+func TestCompilePatternECMAShim(t *testing.T) {
+	re, err := openapi2.CompilePattern(`^\u00e9[^]$`, openapi2.PatternOptions{ECMAShim: true})
+	require.NoError(t, err)
+	require.True(t, re.MatchString("é\n"))
+
+	_, err = openapi2.CompilePattern(`^\u00e9$`, openapi2.PatternOptions{})
+	require.Error(t, err)
+
+	re, err = openapi2.CompilePattern(`^(?<year>[0-9]{4})$`, openapi2.PatternOptions{ECMAShim: true})
+	require.NoError(t, err)
+	require.Equal(t, []string{"", "year"}, re.SubexpNames())
+}
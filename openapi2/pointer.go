@@ -0,0 +1,167 @@
+package openapi2
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/mbilski/kin-openapi/openapi3"
+)
+
+var schemaRefType = reflect.TypeOf(openapi3.SchemaRef{})
+
+// ResolvePointer returns the element at a JSON pointer (RFC 6901) of the document,
+// such as the *Parameter at "/paths/~1users/get/parameters/0".
+// The empty pointer designates the document itself.
+//
+// Elements are returned with their Go type: a struct as a pointer to it, such as *Operation
+// or *openapi3.Info, and any other value as it is, such as a string or a []string.
+// A schema is a *openapi3.SchemaRef, and tokens following it designate the properties of its value,
+// but for "$ref". Tokens following an extension designate the elements of its JSON value,
+// which are returned as generic JSON values.
+//
+// The error names the first token that doesn't resolve.
+func (swagger *Swagger) ResolvePointer(pointer string) (interface{}, error) {
+	if pointer == "" {
+		return swagger, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("Invalid JSON pointer '%s': it must be empty or start with '/'", pointer)
+	}
+	node := reflect.ValueOf(swagger)
+	var generic interface{}
+	isGeneric := false
+	for _, token := range strings.Split(pointer[1:], "/") {
+		token = unescapePointerToken(token)
+		var ok bool
+		if isGeneric {
+			generic, ok = resolveGenericToken(generic, token)
+		} else {
+			node, generic, isGeneric, ok = resolvePointerToken(node, token)
+		}
+		if !ok {
+			return nil, fmt.Errorf("Failed to resolve '%s' in JSON pointer '%s'", token, pointer)
+		}
+	}
+	if isGeneric {
+		return generic, nil
+	}
+	if node.Kind() == reflect.Struct && node.CanAddr() {
+		return node.Addr().Interface(), nil
+	}
+	return node.Interface(), nil
+}
+
+// resolvePointerToken returns the element of node designated by a token.
+// The element of an extension is returned as a generic JSON value.
+func resolvePointerToken(node reflect.Value, token string) (reflect.Value, interface{}, bool, bool) {
+	for node.Kind() == reflect.Ptr || node.Kind() == reflect.Interface {
+		if node.IsNil() {
+			return reflect.Value{}, nil, false, false
+		}
+		if node.Type().Elem() == schemaRefType {
+			if token == "$ref" {
+				return node.Elem().FieldByName("Ref"), nil, false, true
+			}
+			// The properties of a schema are those of its value
+			node = node.Elem().FieldByName("Value")
+			continue
+		}
+		node = node.Elem()
+	}
+	switch node.Kind() {
+	case reflect.Struct:
+		if extensions := node.FieldByName("Extensions"); strings.HasPrefix(token, "x-") && extensions.IsValid() {
+			if extensions, ok := extensions.Interface().(map[string]interface{}); ok {
+				value, ok := extensions[token]
+				if !ok {
+					return reflect.Value{}, nil, false, false
+				}
+				var generic interface{}
+				err := decodeExtension(value, &generic)
+				return reflect.Value{}, generic, true, err == nil
+			}
+		}
+		if field, ok := structField(node, token); ok && !isNilValue(field) {
+			return field, nil, false, true
+		}
+	case reflect.Map:
+		if node.Type().Key().Kind() != reflect.String {
+			break
+		}
+		value := node.MapIndex(reflect.ValueOf(token).Convert(node.Type().Key()))
+		if value.IsValid() && !isNilValue(value) {
+			return value, nil, false, true
+		}
+	case reflect.Slice, reflect.Array:
+		if i, ok := pointerIndex(token, node.Len()); ok {
+			return node.Index(i), nil, false, true
+		}
+	}
+	return reflect.Value{}, nil, false, false
+}
+
+// isNilValue reports whether a value is a nil pointer, interface or map, which designates no element.
+func isNilValue(value reflect.Value) bool {
+	switch value.Kind() {
+	case reflect.Ptr, reflect.Interface, reflect.Map:
+		return value.IsNil()
+	}
+	return false
+}
+
+// structField returns the field of a struct serialized under a JSON name,
+// including the fields of embedded structs.
+func structField(node reflect.Value, name string) (reflect.Value, bool) {
+	t := node.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			tag = field.Tag.Get("multijson")
+		}
+		if field.Anonymous && tag == "" {
+			if value, ok := structField(node.Field(i), name); ok {
+				return value, true
+			}
+			continue
+		}
+		if tag == "" || tag == "-" {
+			continue
+		}
+		if strings.SplitN(tag, ",", 2)[0] == name {
+			return node.Field(i), true
+		}
+	}
+	return reflect.Value{}, false
+}
+
+// resolveGenericToken returns the element of a generic JSON value designated by a token.
+func resolveGenericToken(node interface{}, token string) (interface{}, bool) {
+	switch node := node.(type) {
+	case map[string]interface{}:
+		value, ok := node[token]
+		return value, ok
+	case []interface{}:
+		if i, ok := pointerIndex(token, len(node)); ok {
+			return node[i], true
+		}
+	}
+	return nil, false
+}
+
+// pointerIndex parses an array index token, which has no leading zero.
+func pointerIndex(token string, length int) (int, bool) {
+	if token == "" || (len(token) > 1 && token[0] == '0') {
+		return 0, false
+	}
+	i, err := strconv.Atoi(token)
+	if err != nil || i < 0 || i >= length {
+		return 0, false
+	}
+	return i, true
+}
@@ -0,0 +1,75 @@
+package openapi2_test
+
+import (
+	"testing"
+
+	"github.com/mbilski/kin-openapi/openapi2"
+	"github.com/mbilski/kin-openapi/openapi3"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolvePointer(t *testing.T) {
+	swagger := loadSwagger(t, `
+{
+  "info": {"title": "MyAPI", "version": "0.1"},
+  "paths": {
+    "/users": {
+      "get": {
+        "x-rate-limit": {"tiers": [{"name": "free", "limit": 10}]},
+        "parameters": [
+          {"in": "query", "name": "limit", "type": "integer"},
+          {"in": "query", "name": "a~b", "type": "string"}
+        ],
+        "responses": {
+          "200": {"description": "users", "schema": {"$ref": "#/definitions/Users"}}
+        }
+      }
+    }
+  },
+  "definitions": {
+    "Users": {"type": "array", "items": {"type": "object", "properties": {"name": {"type": "string"}}}}
+  }
+}`)
+	node, err := swagger.ResolvePointer("/paths/~1users")
+	require.NoError(t, err)
+	require.True(t, node.(*openapi2.PathItem) == swagger.Paths["/users"])
+
+	node, err = swagger.ResolvePointer("/paths/~1users/get/parameters/1")
+	require.NoError(t, err)
+	require.Equal(t, "a~b", node.(*openapi2.Parameter).Name)
+
+	node, err = swagger.ResolvePointer("/paths/~1users/get/parameters/0/type")
+	require.NoError(t, err)
+	require.Equal(t, "integer", node)
+
+	node, err = swagger.ResolvePointer("/info")
+	require.NoError(t, err)
+	require.True(t, node.(*openapi3.Info) == &swagger.Info)
+
+	node, err = swagger.ResolvePointer("/definitions/Users/items/properties/name/type")
+	require.NoError(t, err)
+	require.Equal(t, "string", node)
+
+	node, err = swagger.ResolvePointer("/paths/~1users/get/responses/200/schema/$ref")
+	require.NoError(t, err)
+	require.Equal(t, "#/definitions/Users", node)
+
+	node, err = swagger.ResolvePointer("/paths/~1users/get/x-rate-limit/tiers/0/limit")
+	require.NoError(t, err)
+	require.Equal(t, float64(10), node)
+
+	node, err = swagger.ResolvePointer("")
+	require.NoError(t, err)
+	require.True(t, node.(*openapi2.Swagger) == swagger)
+
+	_, err = swagger.ResolvePointer("/paths/~1users/get/parameters/2")
+	require.EqualError(t, err, "Failed to resolve '2' in JSON pointer '/paths/~1users/get/parameters/2'")
+	_, err = swagger.ResolvePointer("/paths/~1users/get/parameters/01")
+	require.EqualError(t, err, "Failed to resolve '01' in JSON pointer '/paths/~1users/get/parameters/01'")
+	_, err = swagger.ResolvePointer("/paths/~1pets/get")
+	require.EqualError(t, err, "Failed to resolve '/pets' in JSON pointer '/paths/~1pets/get'")
+	_, err = swagger.ResolvePointer("/paths/~1users/post/summary")
+	require.EqualError(t, err, "Failed to resolve 'post' in JSON pointer '/paths/~1users/post/summary'")
+	_, err = swagger.ResolvePointer("paths")
+	require.EqualError(t, err, "Invalid JSON pointer 'paths': it must be empty or start with '/'")
+}
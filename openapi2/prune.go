@@ -0,0 +1,186 @@
+package openapi2
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/mbilski/kin-openapi/openapi3"
+)
+
+// PruneOptions configures Swagger.PruneUnused.
+type PruneOptions struct {
+	// FollowExtensionRefs keeps the definitions, parameters and responses named by strings
+	// in extension values, such as "x-links": {"owner": "#/definitions/User"}.
+	// Without it, a definition only referenced from extensions is removed.
+	FollowExtensionRefs bool
+}
+
+// PruneUnused removes the definitions, parameters and responses that the paths don't reference,
+// directly or transitively.
+func (swagger *Swagger) PruneUnused(opts PruneOptions) {
+	swagger.pruneComponents(opts.FollowExtensionRefs)
+}
+
+// pruneComponents removes the definitions, parameters and responses
+// that the paths don't reference, directly or transitively.
+func (swagger *Swagger) pruneComponents(followExtensionRefs bool) {
+	definitions := make(map[string]struct{})
+	parameters := make(map[string]struct{})
+	responses := make(map[string]struct{})
+	var pending []string
+	queue := func(_ string, ref *string) {
+		pending = append(pending, *ref)
+	}
+	queueExtensions := func(props ExtensionProps) {
+		if followExtensionRefs {
+			pending = append(pending, extensionRefs(props.Extensions)...)
+		}
+	}
+	queueSchemaExtensions := func(schemaRef *openapi3.SchemaRef) {
+		if followExtensionRefs {
+			walkSchemaRef("", schemaRef, func(_ string, schemaRef *openapi3.SchemaRef) {
+				if schemaRef.Value != nil {
+					pending = append(pending, extensionRefs(schemaRef.Value.Extensions)...)
+				}
+			})
+		}
+	}
+	queueParameter := func(parameter *Parameter) {
+		walkParameterRefs("", parameter, queue)
+		if parameter != nil {
+			queueExtensions(parameter.ExtensionProps)
+			queueSchemaExtensions(parameter.Schema)
+			queueSchemaExtensions(parameter.Items)
+		}
+	}
+	queueResponse := func(response *Response) {
+		walkResponseRefs("", response, queue)
+		if response != nil {
+			queueExtensions(response.ExtensionProps)
+			queueSchemaExtensions(response.Schema)
+			for _, name := range sortedHeaderNames(response.Headers) {
+				if header := response.Headers[name]; header != nil {
+					queueExtensions(header.ExtensionProps)
+				}
+			}
+		}
+	}
+
+	queueExtensions(swagger.ExtensionProps)
+	if followExtensionRefs {
+		pending = append(pending, extensionRefs(swagger.Info.Extensions)...)
+	}
+	for _, path := range swagger.sortedPaths() {
+		pathItem := swagger.Paths[path]
+		if pathItem == nil {
+			continue
+		}
+		queueExtensions(pathItem.ExtensionProps)
+		for _, parameter := range pathItem.Parameters {
+			queueParameter(parameter)
+		}
+		for _, method := range operationMethods {
+			if operation := pathItem.GetOperation(method); operation != nil {
+				queueExtensions(operation.ExtensionProps)
+				for _, parameter := range operation.Parameters {
+					queueParameter(parameter)
+				}
+				for _, status := range sortedResponseKeys(operation.Responses) {
+					queueResponse(operation.Responses[status])
+				}
+			}
+		}
+	}
+	for _, name := range sortedSecuritySchemeNames(swagger.SecurityDefinitions) {
+		if securityScheme := swagger.SecurityDefinitions[name]; securityScheme != nil {
+			queueExtensions(securityScheme.ExtensionProps)
+		}
+	}
+
+	for len(pending) > 0 {
+		ref := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+		if name, _, ok := definitionName(ref); ok {
+			// References may point inside a definition
+			if _, ok := definitions[name]; ok {
+				continue
+			}
+			definitions[name] = struct{}{}
+			walkSchemaRefRefs("", swagger.Definitions[name], queue)
+			queueSchemaExtensions(swagger.Definitions[name])
+		} else if strings.HasPrefix(ref, parametersPrefix) {
+			name := unescapePointerToken(strings.SplitN(ref[len(parametersPrefix):], "/", 2)[0])
+			if _, ok := parameters[name]; ok {
+				continue
+			}
+			parameters[name] = struct{}{}
+			queueParameter(swagger.Parameters[name])
+		} else if strings.HasPrefix(ref, responsesPrefix) {
+			// Header references point inside a shared response
+			name := unescapePointerToken(strings.SplitN(ref[len(responsesPrefix):], "/", 2)[0])
+			if _, ok := responses[name]; ok {
+				continue
+			}
+			responses[name] = struct{}{}
+			queueResponse(swagger.Responses[name])
+		}
+	}
+	for name := range swagger.Definitions {
+		if _, ok := definitions[name]; !ok {
+			delete(swagger.Definitions, name)
+		}
+	}
+	for name := range swagger.Parameters {
+		if _, ok := parameters[name]; !ok {
+			delete(swagger.Parameters, name)
+		}
+	}
+	for name := range swagger.Responses {
+		if _, ok := responses[name]; !ok {
+			delete(swagger.Responses, name)
+		}
+	}
+}
+
+// extensionRefs returns the strings of extension values that point into the document,
+// such as "#/definitions/User", in a stable order.
+func extensionRefs(extensions map[string]interface{}) []string {
+	keys := make([]string, 0, len(extensions))
+	for key := range extensions {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var refs []string
+	var collect func(value interface{})
+	collect = func(value interface{}) {
+		switch value := value.(type) {
+		case string:
+			if strings.HasPrefix(value, definitionsPrefix) ||
+				strings.HasPrefix(value, parametersPrefix) ||
+				strings.HasPrefix(value, responsesPrefix) {
+				refs = append(refs, value)
+			}
+		case []interface{}:
+			for _, item := range value {
+				collect(item)
+			}
+		case map[string]interface{}:
+			names := make([]string, 0, len(value))
+			for name := range value {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			for _, name := range names {
+				collect(value[name])
+			}
+		}
+	}
+	for _, key := range keys {
+		var value interface{}
+		if err := decodeExtension(extensions[key], &value); err != nil {
+			continue
+		}
+		collect(value)
+	}
+	return refs
+}
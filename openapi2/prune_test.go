@@ -0,0 +1,56 @@
+package openapi2_test
+
+import (
+	"testing"
+
+	"github.com/mbilski/kin-openapi/openapi2"
+	"github.com/stretchr/testify/require"
+)
+
+const pruneSpec = `
+{
+  "info": {"title": "MyAPI", "version": "0.1"},
+  "paths": {
+    "/pets": {
+      "get": {
+        "x-links": {"owner": {"schema": "#/definitions/Owner"}},
+        "parameters": [{"$ref": "#/parameters/limit"}],
+        "responses": {
+          "200": {"description": "pets", "schema": {"$ref": "#/definitions/Pet"}}
+        }
+      }
+    }
+  },
+  "parameters": {
+    "limit": {"in": "query", "name": "limit", "type": "integer"},
+    "offset": {"in": "query", "name": "offset", "type": "integer"}
+  },
+  "responses": {
+    "NotFound": {"description": "not found"}
+  },
+  "definitions": {
+    "Owner": {"type": "object", "properties": {"address": {"$ref": "#/definitions/Address"}}},
+    "Address": {"type": "object"},
+    "Pet": {"type": "object", "x-related": ["#/definitions/Toy"]},
+    "Toy": {"type": "object"},
+    "Unused": {"type": "object"}
+  }
+}`
+
+func TestPruneUnused(t *testing.T) {
+	swagger := loadSwagger(t, pruneSpec)
+	swagger.PruneUnused(openapi2.PruneOptions{FollowExtensionRefs: true})
+	require.Len(t, swagger.Definitions, 4)
+	for _, name := range []string{"Address", "Owner", "Pet", "Toy"} {
+		require.Contains(t, swagger.Definitions, name)
+	}
+	require.Len(t, swagger.Parameters, 1)
+	require.Contains(t, swagger.Parameters, "limit")
+	require.Empty(t, swagger.Responses)
+
+	swagger = loadSwagger(t, pruneSpec)
+	swagger.PruneUnused(openapi2.PruneOptions{})
+	require.Len(t, swagger.Definitions, 1)
+	require.Contains(t, swagger.Definitions, "Pet")
+	require.Len(t, swagger.Parameters, 1)
+}
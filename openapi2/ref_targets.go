@@ -0,0 +1,62 @@
+package openapi2
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mbilski/kin-openapi/openapi3"
+)
+
+// validateRefTargets checks that each local $ref points into the section of the document
+// matching its usage: "#/parameters" for a parameter, "#/responses" for a response,
+// and "#/definitions" for a schema. External $refs are not checked, nor are header $refs,
+// which Header.Resolve accepts in both "#/responses" and "#/definitions".
+func (v *validator) validateRefTargets(swagger *Swagger) []error {
+	var errs []error
+	check := func(pointer string, kind string, ref string, prefix string) {
+		if err := checkRefTarget(kind, ref, prefix); err != nil {
+			errs = append(errs, &LintError{Pointer: pointer, Reason: err.Error()})
+		}
+	}
+	checkSchema := func(pointer string, schemaRef *openapi3.SchemaRef) {
+		walkSchemaRef(pointer, schemaRef, func(pointer string, schemaRef *openapi3.SchemaRef) {
+			check(pointer, "Schema", schemaRef.Ref, definitionsPrefix)
+		})
+	}
+	for _, name := range sortedSchemaNames(swagger.Definitions) {
+		checkSchema("/definitions/"+escapePointerToken(name), swagger.Definitions[name])
+	}
+	swagger.walkParameters(func(pointer string, parameter *Parameter) {
+		if parameter.Ref != "" {
+			check(pointer, "Parameter", parameter.Ref, parametersPrefix)
+			return
+		}
+		checkSchema(pointer+"/schema", parameter.Schema)
+		checkSchema(pointer+"/items", parameter.Items)
+	})
+	swagger.walkResponses(func(pointer string, response *Response) {
+		if response.Ref != "" {
+			check(pointer, "Response", response.Ref, responsesPrefix)
+			return
+		}
+		checkSchema(pointer+"/schema", response.Schema)
+	})
+	return errs
+}
+
+// checkRefTarget reports a local $ref pointing outside of the section starting with prefix.
+func checkRefTarget(kind string, ref string, prefix string) error {
+	if !strings.HasPrefix(ref, "#/") || strings.HasPrefix(ref, prefix) {
+		return nil
+	}
+	target := ref
+	if i := strings.Index(ref[len("#/"):], "/"); i >= 0 {
+		target = ref[:len("#/")+i]
+	}
+	section := strings.TrimSuffix(prefix, "/")
+	if target == section {
+		// Such as "#/definitions", which doesn't resolve either way
+		return nil
+	}
+	return fmt.Errorf("%s $ref '%s' points into '%s', but must point into '%s'", kind, ref, target, section)
+}
@@ -0,0 +1,36 @@
+package openapi2
+
+import "strings"
+
+// RefsTo returns the JSON pointers of the elements whose $ref is target, such as "#/definitions/User".
+// Paths, definitions, shared parameters and shared responses are scanned, including response
+// headers and nested schemas, and pointers are returned in that order.
+func (swagger *Swagger) RefsTo(target string) []string {
+	var pointers []string
+	swagger.walkRefs(func(pointer string, ref *string) {
+		if *ref == target {
+			pointers = append(pointers, pointer)
+		}
+	})
+	return pointers
+}
+
+// RefsFrom returns the $refs held by the element at the JSON pointer and by the elements nested in it,
+// such as "#/definitions/User" for "/paths/~1users/get".
+// Each $ref is returned once, in the order RefsTo scans the document.
+// The empty pointer designates the whole document.
+func (swagger *Swagger) RefsFrom(pointer string) []string {
+	var refs []string
+	seen := make(map[string]struct{})
+	swagger.walkRefs(func(p string, ref *string) {
+		if p != pointer && !strings.HasPrefix(p, pointer+"/") {
+			return
+		}
+		if _, ok := seen[*ref]; ok {
+			return
+		}
+		seen[*ref] = struct{}{}
+		refs = append(refs, *ref)
+	})
+	return refs
+}
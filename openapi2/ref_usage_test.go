@@ -0,0 +1,62 @@
+package openapi2_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRefsTo(t *testing.T) {
+	swagger := loadSwagger(t, `
+{
+  "info": {"title": "MyAPI", "version": "0.1"},
+  "paths": {
+    "/users": {
+      "post": {
+        "parameters": [
+          {"in": "body", "name": "user", "schema": {"$ref": "#/definitions/User"}},
+          {"$ref": "#/parameters/trace"}
+        ],
+        "responses": {
+          "201": {"description": "created", "schema": {"$ref": "#/definitions/Group"}},
+          "404": {"$ref": "#/responses/NotFound"}
+        }
+      }
+    }
+  },
+  "parameters": {
+    "trace": {"in": "header", "name": "X-Trace", "type": "string"}
+  },
+  "responses": {
+    "NotFound": {"description": "not found", "schema": {"$ref": "#/definitions/Error"}}
+  },
+  "definitions": {
+    "Error": {"type": "object"},
+    "Group": {
+      "type": "object",
+      "properties": {
+        "owner": {"$ref": "#/definitions/User"},
+        "members": {"type": "array", "items": {"$ref": "#/definitions/User"}}
+      }
+    },
+    "User": {"type": "object"}
+  }
+}`)
+	require.Equal(t, []string{
+		"/paths/~1users/post/parameters/0/schema",
+		"/definitions/Group/properties/members/items",
+		"/definitions/Group/properties/owner",
+	}, swagger.RefsTo("#/definitions/User"))
+	require.Equal(t, []string{"/responses/NotFound/schema"}, swagger.RefsTo("#/definitions/Error"))
+	require.Empty(t, swagger.RefsTo("#/definitions/Missing"))
+
+	require.Equal(t, []string{
+		"#/definitions/User",
+		"#/parameters/trace",
+		"#/definitions/Group",
+		"#/responses/NotFound",
+	}, swagger.RefsFrom("/paths/~1users/post"))
+	require.Equal(t, []string{"#/definitions/User"}, swagger.RefsFrom("/definitions/Group"))
+	require.Empty(t, swagger.RefsFrom("/definitions/User"))
+	require.Len(t, swagger.RefsFrom(""), 5)
+}
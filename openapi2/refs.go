@@ -0,0 +1,69 @@
+package openapi2
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mbilski/kin-openapi/openapi3"
+)
+
+const (
+	parametersPrefix          = "#/parameters/"
+	responsesPrefix           = "#/responses/"
+	securityDefinitionsPrefix = "#/securityDefinitions/"
+)
+
+func failedToResolveRef(ref string) error {
+	return fmt.Errorf("Failed to resolve ref: '%s'", ref)
+}
+
+// resolveParameter follows the $ref of a parameter into "#/parameters".
+func (swagger *Swagger) resolveParameter(parameter *Parameter) (*Parameter, error) {
+	for seen := 0; parameter != nil && parameter.Ref != ""; seen++ {
+		ref := parameter.Ref
+		if !strings.HasPrefix(ref, parametersPrefix) || seen > len(swagger.Parameters) {
+			return nil, failedToResolveRef(ref)
+		}
+		if parameter = swagger.Parameters[ref[len(parametersPrefix):]]; parameter == nil {
+			return nil, failedToResolveRef(ref)
+		}
+	}
+	return parameter, nil
+}
+
+// resolveResponse follows the $ref of a response into "#/responses".
+func (swagger *Swagger) resolveResponse(response *Response) (*Response, error) {
+	for seen := 0; response != nil && response.Ref != ""; seen++ {
+		ref := response.Ref
+		if !strings.HasPrefix(ref, responsesPrefix) || seen > len(swagger.Responses) {
+			return nil, failedToResolveRef(ref)
+		}
+		if response = swagger.Responses[ref[len(responsesPrefix):]]; response == nil {
+			return nil, failedToResolveRef(ref)
+		}
+	}
+	return response, nil
+}
+
+// resolveSchemaRef follows the $ref of a schema into "#/definitions".
+// The returned SchemaRef keeps the original $ref and carries the resolved value.
+func (swagger *Swagger) resolveSchemaRef(schemaRef *openapi3.SchemaRef) (*openapi3.SchemaRef, error) {
+	if schemaRef == nil || schemaRef.Ref == "" || schemaRef.Value != nil {
+		return schemaRef, nil
+	}
+	ref := schemaRef.Ref
+	current := schemaRef
+	for seen := 0; current.Value == nil; seen++ {
+		name, rest, ok := definitionName(current.Ref)
+		if !ok || rest != "" || seen > len(swagger.Definitions) {
+			return nil, failedToResolveRef(ref)
+		}
+		if current = swagger.Definitions[name]; current == nil {
+			return nil, failedToResolveRef(ref)
+		}
+	}
+	return &openapi3.SchemaRef{
+		Ref:   ref,
+		Value: current.Value,
+	}, nil
+}
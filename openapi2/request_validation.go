@@ -0,0 +1,245 @@
+package openapi2
+
+import (
+	"fmt"
+	"net/http"
+	"net/textproto"
+	"strconv"
+	"strings"
+)
+
+// RequestError describes a request that doesn't satisfy the operation it was routed to.
+type RequestError struct {
+	// Parameter is the invalid parameter, if any.
+	Parameter *Parameter
+	Reason    string
+	Err       error
+}
+
+func (err *RequestError) Error() string {
+	reason := err.Reason
+	if e := err.Err; e != nil {
+		if len(reason) == 0 {
+			reason = e.Error()
+		} else {
+			reason += ": " + e.Error()
+		}
+	}
+	if v := err.Parameter; v != nil {
+		return fmt.Sprintf("Parameter '%s' in %s has an error: %s", v.Name, v.In, reason)
+	}
+	return reason
+}
+
+// effectiveParameters returns the resolved parameters of an operation,
+// with the parameters of its path item that it doesn't override.
+// Parameters that fail to resolve are skipped, and the first failure is returned with the others.
+// The parameters cached by Swagger.ResolveParameters are returned as they are.
+func (swagger *Swagger) effectiveParameters(pathItem *PathItem, operation *Operation) (Parameters, error) {
+	if operation.ResolvedParameters != nil {
+		return operation.ResolvedParameters, nil
+	}
+	var result Parameters
+	var firstErr error
+	index := make(map[ParameterKey]int)
+	add := func(parameters Parameters) {
+		for _, parameter := range parameters {
+			parameter, err := swagger.resolveParameter(parameter)
+			if err != nil {
+				if firstErr == nil {
+					firstErr = err
+				}
+				continue
+			}
+			if parameter == nil {
+				continue
+			}
+			key := ParameterKey{In: parameter.In, Name: parameter.Name}
+			if i, ok := index[key]; ok {
+				result[i] = parameter
+				continue
+			}
+			index[key] = len(result)
+			result = append(result, parameter)
+		}
+	}
+	if pathItem != nil {
+		add(pathItem.Parameters)
+	}
+	add(operation.Parameters)
+	return result, firstErr
+}
+
+// ValidateParameters validates the path, query and header parameters of a request
+// against an operation of pathItem, including the parameters inherited from the path item.
+// pathParams holds the values matched by the path template, such as "id" for "/pets/{id}".
+//
+// Values are received as strings and coerced to the declared type before their constraints
+// are checked: "integer" and "number" values must parse as numbers and "boolean" values
+// must be "true" or "false". The items of an "array" value are split according to its
+// collectionFormat, or read from repeated keys with "multi", and coerced to the type of its items.
+// String lengths are counted in Unicode code points, as JSON Schema does, not in bytes.
+//
+// It returns a MultiError of *RequestError holding every invalid parameter, or nil.
+func (swagger *Swagger) ValidateParameters(req *http.Request, pathItem *PathItem, operation *Operation, pathParams map[string]string) error {
+	return swagger.ValidateParametersWithOptions(req, pathItem, operation, pathParams, ValidationOptions{})
+}
+
+// ValidateParametersWithOptions is like ValidateParameters, configured by the given options.
+func (swagger *Swagger) ValidateParametersWithOptions(req *http.Request, pathItem *PathItem, operation *Operation, pathParams map[string]string, opts ValidationOptions) error {
+	parameters, err := swagger.effectiveParameters(pathItem, operation)
+	if err != nil {
+		return err
+	}
+	var errs MultiError
+	query := req.URL.Query()
+	for _, parameter := range parameters {
+		var values []string
+		switch parameter.In {
+		case "path":
+			// An empty segment is a missing value
+			if value := pathParams[parameter.Name]; value != "" {
+				values = []string{value}
+			}
+		case "query":
+			values = query[parameter.Name]
+		case "header":
+			values = headerValues(req.Header, parameter.Name)
+		default:
+			continue
+		}
+		if err := parameter.validateRequestValues(values, opts); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// headerValues returns the values of a header, matching its name case-insensitively.
+// Headers are usually stored under their canonical name, but a header set directly
+// in the map may not be.
+func headerValues(header http.Header, name string) []string {
+	if values, ok := header[textproto.CanonicalMIMEHeaderKey(name)]; ok {
+		return values
+	}
+	for key, values := range header {
+		if strings.EqualFold(key, name) {
+			return values
+		}
+	}
+	return nil
+}
+
+func (parameter *Parameter) validateRequestValues(values []string, opts ValidationOptions) error {
+	if len(values) == 0 {
+		if parameter.Required {
+			return &RequestError{Parameter: parameter, Reason: "Value is required"}
+		}
+		return nil
+	}
+	value, err := parameter.coerceValues(values, opts)
+	if err != nil {
+		return &RequestError{Parameter: parameter, Err: err}
+	}
+	if err := parameter.validateValue(value, opts.PatternOptions); err != nil {
+		return &RequestError{Parameter: parameter, Err: err}
+	}
+	return nil
+}
+
+// coerceValues converts the request values of a parameter to its type.
+// The items of a "multi" array are the repeated values, such as "?tag=a&tag=b".
+// Otherwise the first value is used.
+func (parameter *Parameter) coerceValues(values []string, opts ValidationOptions) (interface{}, error) {
+	if parameter.Type != "array" || parameter.CollectionFormat != "multi" {
+		return parameter.coerceValue(values[0], opts)
+	}
+	itemsParameter := parameter.itemsParameter()
+	if itemsParameter == nil {
+		itemsParameter = &Parameter{}
+	}
+	result := make([]interface{}, 0, len(values))
+	for _, item := range values {
+		value, err := itemsParameter.coerceValue(item, opts)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, value)
+	}
+	return result, nil
+}
+
+// coerceValue converts a request value to the type of the parameter.
+// The items of an array value are split on the separator of its collection format,
+// and coerced to the type of the parameter items.
+func (parameter *Parameter) coerceValue(raw string, opts ValidationOptions) (interface{}, error) {
+	if parameter.Type != "array" {
+		return coerceValue(parameter.Type, raw, opts)
+	}
+	itemsParameter := parameter.itemsParameter()
+	if itemsParameter == nil {
+		itemsParameter = &Parameter{}
+	}
+	var result []interface{}
+	if raw != "" {
+		for _, item := range strings.Split(raw, collectionSeparator(parameter.CollectionFormat)) {
+			value, err := itemsParameter.coerceValue(item, opts)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, value)
+		}
+	}
+	return result, nil
+}
+
+// collectionSeparator returns the separator of the items of an array value
+// in the given collection format.
+func collectionSeparator(collectionFormat string) string {
+	switch collectionFormat {
+	case "ssv":
+		return " "
+	case "tsv":
+		return "\t"
+	case "pipes":
+		return "|"
+	}
+	return ","
+}
+
+func coerceValue(valueType string, raw string, opts ValidationOptions) (interface{}, error) {
+	switch valueType {
+	case "integer", "number":
+		if valueType == "integer" && opts.StrictInteger && !isIntegerLiteral(raw) {
+			return nil, fmt.Errorf("Value '%s' is not a valid integer", raw)
+		}
+		value, err := strconv.ParseFloat(raw, 64)
+		if err != nil || !isDecimalLiteral(raw) {
+			return nil, fmt.Errorf("Value '%s' is not a valid %s", raw, valueType)
+		}
+		return value, nil
+	case "boolean":
+		switch raw {
+		case "true":
+			return true, nil
+		case "false":
+			return false, nil
+		}
+		return nil, fmt.Errorf("Value '%s' is not a valid boolean", raw)
+	}
+	return raw, nil
+}
+
+// isDecimalLiteral reports whether a number is written in decimal,
+// as ParseFloat also accepts "NaN", "Inf" and hexadecimal numbers such as "0x1p4".
+func isDecimalLiteral(raw string) bool {
+	return strings.Trim(raw, "+-.0123456789eE") == ""
+}
+
+// isIntegerLiteral reports whether a number is written without a fraction or an exponent.
+func isIntegerLiteral(raw string) bool {
+	return !strings.ContainsAny(raw, ".eE")
+}
@@ -0,0 +1,184 @@
+package openapi2_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mbilski/kin-openapi/openapi2"
+	"github.com/stretchr/testify/require"
+)
+
+const requestValidationSpec = `
+{
+  "info": {"title": "MyAPI", "version": "0.1"},
+  "parameters": {
+    "count": {"in": "header", "name": "X-Count", "type": "integer", "maximum": 100}
+  },
+  "paths": {
+    "/pets/{id}": {
+      "parameters": [{"in": "path", "name": "id", "type": "integer", "required": true}],
+      "get": {
+        "parameters": [
+          {"$ref": "#/parameters/count"},
+          {"in": "query", "name": "verbose", "type": "boolean"},
+          {"in": "query", "name": "weight", "type": "number", "minimum": 0},
+          {"in": "query", "name": "ids", "type": "array", "items": {"type": "integer"}}
+        ],
+        "responses": {"200": {"description": "pet"}}
+      }
+    }
+  }
+}`
+
+func validateParameters(t *testing.T, swagger *openapi2.Swagger, target string, header http.Header, id string) []string {
+	pathItem := swagger.Paths["/pets/{id}"]
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	err := swagger.ValidateParameters(req, pathItem, pathItem.Get, map[string]string{"id": id})
+	if err == nil {
+		return nil
+	}
+	require.IsType(t, openapi2.MultiError{}, err)
+	var messages []string
+	for _, err := range err.(openapi2.MultiError) {
+		require.IsType(t, &openapi2.RequestError{}, err)
+		messages = append(messages, err.Error())
+	}
+	return messages
+}
+
+func TestValidateParametersCoercion(t *testing.T) {
+	swagger := loadSwagger(t, requestValidationSpec)
+	messages := validateParameters(t, swagger, "/pets/42?verbose=true&weight=1.5&ids=1,2,3",
+		http.Header{"X-Count": {"42"}}, "42")
+	require.Empty(t, messages)
+}
+
+func TestValidateParametersCoercionFailure(t *testing.T) {
+	swagger := loadSwagger(t, requestValidationSpec)
+	messages := validateParameters(t, swagger, "/pets/abc?verbose=yes&weight=-1&ids=1,two",
+		http.Header{"X-Count": {"abc"}}, "abc")
+	require.Equal(t, []string{
+		"Parameter 'id' in path has an error: Value 'abc' is not a valid integer",
+		"Parameter 'X-Count' in header has an error: Value 'abc' is not a valid integer",
+		"Parameter 'verbose' in query has an error: Value 'yes' is not a valid boolean",
+		"Parameter 'weight' in query has an error: Value -1 must be at least 0",
+		"Parameter 'ids' in query has an error: Value 'two' is not a valid integer",
+	}, messages)
+
+	// Numbers are decimal and finite
+	messages = validateParameters(t, swagger, "/pets/0x2A?weight=NaN&ids=Inf,1e400", http.Header{"X-Count": {"0x1p4"}}, "0x2A")
+	require.Equal(t, []string{
+		"Parameter 'id' in path has an error: Value '0x2A' is not a valid integer",
+		"Parameter 'X-Count' in header has an error: Value '0x1p4' is not a valid integer",
+		"Parameter 'weight' in query has an error: Value 'NaN' is not a valid number",
+		"Parameter 'ids' in query has an error: Value 'Inf' is not a valid integer",
+	}, messages)
+
+	messages = validateParameters(t, swagger, "/pets/4.5", http.Header{"X-Count": {"101"}}, "4.5")
+	require.Equal(t, []string{
+		"Parameter 'id' in path has an error: Value 4.5 must be an integer",
+		"Parameter 'X-Count' in header has an error: Value 101 must be at most 100",
+	}, messages)
+}
+
+func TestValidateParametersHeaderCase(t *testing.T) {
+	swagger := loadSwagger(t, `
+{
+  "info": {"title": "MyAPI", "version": "0.1"},
+  "paths": {
+    "/pets/{id}": {
+      "get": {
+        "parameters": [
+          {"in": "path", "name": "id", "type": "string", "required": true},
+          {"in": "header", "name": "X-Request-ID", "type": "string", "required": true, "minLength": 8},
+          {"in": "query", "name": "Tag", "type": "string", "required": true}
+        ],
+        "responses": {"200": {"description": "pet"}}
+      }
+    }
+  }
+}`)
+	// Set directly, the header keeps its lowercase name
+	messages := validateParameters(t, swagger, "/pets/1?Tag=a", http.Header{"x-request-id": {"12345678"}}, "1")
+	require.Empty(t, messages)
+
+	messages = validateParameters(t, swagger, "/pets/1?tag=a", http.Header{"x-request-id": {"1234"}}, "1")
+	require.Equal(t, []string{
+		"Parameter 'X-Request-ID' in header has an error: Value '1234' must be at least 8 characters long",
+		"Parameter 'Tag' in query has an error: Value is required",
+	}, messages)
+}
+
+func TestValidateParametersItems(t *testing.T) {
+	swagger := loadSwagger(t, `
+{
+  "info": {"title": "MyAPI", "version": "0.1"},
+  "paths": {
+    "/pets/{id}": {
+      "get": {
+        "parameters": [
+          {"in": "path", "name": "id", "type": "string", "required": true},
+          {"in": "query", "name": "status", "type": "array", "items": {"type": "string", "enum": ["available", "sold"]}},
+          {"in": "query", "name": "sizes", "type": "array", "collectionFormat": "pipes",
+           "items": {"type": "integer", "maximum": 10}},
+          {"in": "query", "name": "grid", "type": "array", "collectionFormat": "ssv",
+           "items": {"type": "array", "items": {"type": "integer"}}}
+        ],
+        "responses": {"200": {"description": "pet"}}
+      }
+    }
+  }
+}`)
+	messages := validateParameters(t, swagger, "/pets/1?status=available,sold&sizes=1|10&grid=1,2+3,4", nil, "1")
+	require.Empty(t, messages)
+
+	messages = validateParameters(t, swagger, "/pets/1?status=available,lost&sizes=1|11&grid=1,2+3,x", nil, "1")
+	require.Equal(t, []string{
+		"Parameter 'status' in query has an error: Item 1 is invalid: Value lost is not one of the allowed values [available sold]",
+		"Parameter 'sizes' in query has an error: Item 1 is invalid: Value 11 must be at most 10",
+		"Parameter 'grid' in query has an error: Value 'x' is not a valid integer",
+	}, messages)
+}
+
+func TestValidateParametersCollectionFormats(t *testing.T) {
+	swagger := loadSwagger(t, `
+{
+  "info": {"title": "MyAPI", "version": "0.1"},
+  "paths": {
+    "/pets/{id}": {
+      "get": {
+        "parameters": [
+          {"in": "path", "name": "id", "type": "string", "required": true},
+          {"in": "query", "name": "tag", "type": "array", "collectionFormat": "multi",
+           "items": {"type": "string", "enum": ["a", "b", "a,b"]}},
+          {"in": "query", "name": "sizes", "type": "array", "collectionFormat": "pipes", "maxItems": 2,
+           "items": {"type": "integer"}}
+        ],
+        "responses": {"200": {"description": "pet"}}
+      }
+    }
+  }
+}`)
+	for _, target := range []string{
+		"/pets/1?tag=a&tag=b&sizes=1|2",
+		"/pets/1?tag=a&sizes=1",
+		"/pets/1?tag=a,b",
+	} {
+		require.Empty(t, validateParameters(t, swagger, target, nil, "1"), target)
+	}
+
+	messages := validateParameters(t, swagger, "/pets/1?tag=a&tag=c&sizes=1|2|3", nil, "1")
+	require.Equal(t, []string{
+		"Parameter 'tag' in query has an error: Item 1 is invalid: Value c is not one of the allowed values [a b a,b]",
+		"Parameter 'sizes' in query has an error: Value must have at most 2 items",
+	}, messages)
+
+	messages = validateParameters(t, swagger, "/pets/1?sizes=1,2", nil, "1")
+	require.Equal(t, []string{
+		"Parameter 'sizes' in query has an error: Value '1,2' is not a valid integer",
+	}, messages)
+}
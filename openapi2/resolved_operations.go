@@ -0,0 +1,127 @@
+package openapi2
+
+import (
+	"fmt"
+
+	"github.com/mbilski/kin-openapi/openapi3"
+	"github.com/mbilski/kin-openapi/pathpattern"
+)
+
+// ResolvedOperation is an operation with its effective parameters.
+type ResolvedOperation struct {
+	Operation *Operation
+	// Parameters are the operation parameters and the path item parameters it doesn't override,
+	// with $refs resolved.
+	Parameters Parameters
+}
+
+// ResolvedOperations returns the operations of the path item by HTTP method, such as "GET",
+// with their effective parameters resolved against swagger.
+// It fails on the first parameter that can't be resolved, in method order.
+func (pathItem *PathItem) ResolvedOperations(swagger *Swagger) (map[string]*ResolvedOperation, error) {
+	result := make(map[string]*ResolvedOperation)
+	for _, method := range operationMethods {
+		operation := pathItem.GetOperation(method)
+		if operation == nil {
+			continue
+		}
+		for _, parameters := range []Parameters{pathItem.Parameters, operation.Parameters} {
+			for _, parameter := range parameters {
+				if _, err := swagger.resolveParameter(parameter); err != nil {
+					name := parameter.Name
+					if name == "" {
+						name = parameter.Ref
+					}
+					return nil, fmt.Errorf("Error while resolving parameter '%s' of operation %s: %v", name, method, err)
+				}
+			}
+		}
+		parameters, err := swagger.effectiveParameters(pathItem, operation)
+		if err != nil {
+			return nil, err
+		}
+		result[method] = &ResolvedOperation{Operation: operation, Parameters: parameters}
+	}
+	return result, nil
+}
+
+// ResolveParameters caches the effective parameters of every operation in its ResolvedParameters,
+// so that validating requests doesn't resolve them again.
+// It also caches the tree matching the paths, and the resolved copies of the definitions
+// and of the body parameter schemas, for ValidateRequest and ValidateNDJSONResponse.
+// A schema that can't be resolved isn't cached, and is reported by each validation using it.
+// The cache isn't updated when the document changes: call ResetResolved, then ResolveParameters again.
+// It fails on the first parameter that can't be resolved, in path order, leaving the cache unset.
+func (swagger *Swagger) ResolveParameters() error {
+	swagger.ResetResolved()
+	routes, err := swagger.buildRouteTree()
+	if err != nil {
+		return err
+	}
+	cache := &validationCache{
+		routes:  routes,
+		schemas: make(map[*openapi3.SchemaRef]*openapi3.SchemaRef),
+	}
+	cacheSchema := func(schemaRef *openapi3.SchemaRef) {
+		if _, ok := cache.schemas[schemaRef]; schemaRef == nil || ok {
+			return
+		}
+		if copied, err := swagger.compiledSchemaCopy(schemaRef); err == nil {
+			cache.schemas[schemaRef] = copied
+		}
+	}
+	for _, name := range sortedSchemaNames(swagger.Definitions) {
+		cacheSchema(swagger.Definitions[name])
+	}
+	resolved := make(map[*Operation]Parameters)
+	for _, path := range swagger.sortedPaths() {
+		pathItem := swagger.Paths[path]
+		if pathItem == nil {
+			continue
+		}
+		operations, err := pathItem.ResolvedOperations(swagger)
+		if err != nil {
+			return fmt.Errorf("%s: %v", pathPointer(path), err)
+		}
+		for _, operation := range operations {
+			// A non-nil cache tells an operation without parameters from an unresolved one
+			parameters := operation.Parameters
+			if parameters == nil {
+				parameters = Parameters{}
+			}
+			resolved[operation.Operation] = parameters
+			for _, parameter := range parameters {
+				if parameter.In == "body" {
+					cacheSchema(parameter.Schema)
+				}
+			}
+		}
+	}
+	for operation, parameters := range resolved {
+		operation.ResolvedParameters = parameters
+	}
+	swagger.validation = cache
+	return nil
+}
+
+// validationCache holds what ResolveParameters resolves for the validations.
+type validationCache struct {
+	// routes matches the paths of the document.
+	routes *pathpattern.Node
+	// schemas are the copies of the schemas of the document with their local references resolved
+	// and their patterns compiled, by schema of the document.
+	schemas map[*openapi3.SchemaRef]*openapi3.SchemaRef
+}
+
+// ResetResolved clears the parameters, the path tree and the schemas cached by ResolveParameters.
+func (swagger *Swagger) ResetResolved() {
+	swagger.validation = nil
+	swagger.walkOperations(func(_ string, _ string, operation *Operation) {
+		operation.ResetResolved()
+	})
+}
+
+// ResetResolved clears the parameters cached by Swagger.ResolveParameters.
+func (operation *Operation) ResetResolved() {
+	operation.ResolvedParameters = nil
+}
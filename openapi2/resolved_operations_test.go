@@ -0,0 +1,147 @@
+package openapi2_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mbilski/kin-openapi/openapi2"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolvedOperations(t *testing.T) {
+	swagger := loadSwagger(t, `
+{
+  "info": {"title": "MyAPI", "version": "0.1"},
+  "parameters": {
+    "limit": {"in": "query", "name": "limit", "type": "integer"}
+  },
+  "paths": {
+    "/pets/{id}": {
+      "parameters": [
+        {"in": "path", "name": "id", "type": "string", "required": true},
+        {"in": "query", "name": "verbose", "type": "boolean"}
+      ],
+      "get": {
+        "parameters": [
+          {"in": "path", "name": "id", "type": "integer", "required": true},
+          {"$ref": "#/parameters/limit"}
+        ],
+        "responses": {"200": {"description": "pet"}}
+      },
+      "delete": {
+        "responses": {"204": {"description": "deleted"}}
+      }
+    },
+    "/broken": {
+      "get": {
+        "parameters": [{"$ref": "#/parameters/missing"}],
+        "responses": {"200": {"description": "ok"}}
+      }
+    }
+  }
+}`)
+	pathItem := swagger.Paths["/pets/{id}"]
+	operations, err := pathItem.ResolvedOperations(swagger)
+	require.NoError(t, err)
+	require.Len(t, operations, 2)
+
+	get := operations["GET"]
+	require.True(t, pathItem.Get == get.Operation)
+	require.Len(t, get.Parameters, 3)
+	require.Equal(t, "id", get.Parameters[0].Name)
+	require.Equal(t, "integer", get.Parameters[0].Type)
+	require.Equal(t, "verbose", get.Parameters[1].Name)
+	require.True(t, swagger.Parameters["limit"] == get.Parameters[2])
+
+	remove := operations["DELETE"]
+	require.Len(t, remove.Parameters, 2)
+	require.Equal(t, "string", remove.Parameters[0].Type)
+
+	_, err = swagger.Paths["/broken"].ResolvedOperations(swagger)
+	require.EqualError(t, err, "Error while resolving parameter '#/parameters/missing' of operation GET: Failed to resolve ref: '#/parameters/missing'")
+}
+
+func TestResolveParameters(t *testing.T) {
+	swagger := loadSwagger(t, requestValidationSpec)
+	before, err := json.Marshal(swagger)
+	require.NoError(t, err)
+
+	require.NoError(t, swagger.ResolveParameters())
+	get := swagger.Paths["/pets/{id}"].Get
+	require.Len(t, get.ResolvedParameters, 5)
+	require.True(t, swagger.Parameters["count"] == get.ResolvedParameters[1])
+	after, err := json.Marshal(swagger)
+	require.NoError(t, err)
+	require.JSONEq(t, string(before), string(after))
+
+	// The validation uses the cache, even once the document changes
+	get.Parameters = nil
+	req := httptest.NewRequest(http.MethodGet, "/pets/1?verbose=maybe", nil)
+	err = openapi2.ValidateRequest(swagger, req)
+	require.EqualError(t, err, "Parameter 'verbose' in query has an error: Value 'maybe' is not a valid boolean")
+
+	swagger.ResetResolved()
+	require.Nil(t, get.ResolvedParameters)
+	require.NoError(t, openapi2.ValidateRequest(swagger, req))
+
+	swagger.Paths["/pets/{id}"].Get.Parameters = openapi2.Parameters{{Ref: "#/parameters/missing"}}
+	err = swagger.ResolveParameters()
+	require.EqualError(t, err, "/paths/~1pets~1{id}: Error while resolving parameter '#/parameters/missing' of operation GET: Failed to resolve ref: '#/parameters/missing'")
+	require.Nil(t, get.ResolvedParameters)
+}
+
+func benchmarkValidateParameters(b *testing.B, warm bool) {
+	swagger, err := openapi2.NewSwaggerLoader().LoadSwaggerFromData([]byte(requestValidationSpec))
+	require.NoError(b, err)
+	if warm {
+		require.NoError(b, swagger.ResolveParameters())
+	}
+	pathItem := swagger.Paths["/pets/{id}"]
+	req := httptest.NewRequest(http.MethodGet, "/pets/42?verbose=true&weight=1.5&ids=1,2,3", nil)
+	req.Header.Set("X-Count", "42")
+	pathParams := map[string]string{"id": "42"}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := swagger.ValidateParameters(req, pathItem, pathItem.Get, pathParams); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkValidateParametersCold(b *testing.B) {
+	benchmarkValidateParameters(b, false)
+}
+
+func BenchmarkValidateParametersWarm(b *testing.B) {
+	benchmarkValidateParameters(b, true)
+}
+
+func benchmarkValidateRequest(b *testing.B, warm bool) {
+	swagger, err := openapi2.NewSwaggerLoader().LoadSwaggerFromData([]byte(validateRequestSpec))
+	require.NoError(b, err)
+	if warm {
+		require.NoError(b, swagger.ResolveParameters())
+	}
+	body := `{"name": "Rex", "tags": ["good", "dog"]}`
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		req := httptest.NewRequest(http.MethodPost, "/v1/pets?dryRun=true", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		if err := openapi2.ValidateRequest(swagger, req); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkValidateRequestCold(b *testing.B) {
+	benchmarkValidateRequest(b, false)
+}
+
+func BenchmarkValidateRequestWarm(b *testing.B) {
+	benchmarkValidateRequest(b, true)
+}
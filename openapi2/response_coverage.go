@@ -0,0 +1,46 @@
+package openapi2
+
+import "strings"
+
+// responseCoverageRules returns the rules enabled by ValidationOptions.RequireDefaultResponse
+// and ValidationOptions.RequireErrorResponses.
+func (v *validator) responseCoverageRules() []namedRule {
+	var rules []namedRule
+	if v.opts.RequireDefaultResponse {
+		rules = append(rules, namedRule{"require-default-response", v.requireDefaultResponse})
+	}
+	if v.opts.RequireErrorResponses {
+		rules = append(rules, namedRule{"require-error-responses", v.requireErrorResponses})
+	}
+	return rules
+}
+
+func (v *validator) requireDefaultResponse(swagger *Swagger) []error {
+	return swagger.requireResponse("Operation has no default response", func(status string) bool {
+		return status == "default"
+	})
+}
+
+func (v *validator) requireErrorResponses(swagger *Swagger) []error {
+	return swagger.requireResponse("Operation has no 4xx response", func(status string) bool {
+		return len(status) == 3 && status[0] == '4'
+	})
+}
+
+// requireResponse reports the operations without a response whose status is accepted by match.
+// A $ref response only counts when it resolves.
+func (swagger *Swagger) requireResponse(reason string, match func(status string) bool) []error {
+	var errs []error
+	swagger.walkOperations(func(path string, method string, operation *Operation) {
+		for status, response := range operation.Responses {
+			if !match(strings.ToLower(status)) {
+				continue
+			}
+			if resolved, err := swagger.resolveResponse(response); err == nil && resolved != nil {
+				return
+			}
+		}
+		errs = append(errs, &LintError{Pointer: operationPointer(path, method) + "/responses", Reason: reason})
+	})
+	return errs
+}
@@ -0,0 +1,88 @@
+package openapi2_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mbilski/kin-openapi/openapi2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateResponseCoverage(t *testing.T) {
+	swagger := loadSwagger(t, `
+{
+  "info": {"title": "MyAPI", "version": "0.1"},
+  "responses": {
+    "NotFound": {"description": "not found"}
+  },
+  "paths": {
+    "/pets": {
+      "get": {
+        "responses": {"200": {"description": "pets"}}
+      },
+      "post": {
+        "responses": {
+          "201": {"description": "created"},
+          "400": {"$ref": "#/responses/Missing"},
+          "default": {"description": "error"}
+        }
+      }
+    },
+    "/pets/{id}": {
+      "get": {
+        "parameters": [{"in": "path", "name": "id", "type": "string", "required": true}],
+        "responses": {
+          "200": {"description": "pet"},
+          "404": {"$ref": "#/responses/NotFound"}
+        }
+      }
+    }
+  }
+}`)
+	ctx := context.Background()
+	require.Empty(t, swagger.ValidateIssues(ctx, openapi2.ValidationOptions{}))
+
+	issues := swagger.ValidateIssues(ctx, openapi2.ValidationOptions{RequireDefaultResponse: true})
+	require.Equal(t, []openapi2.Issue{
+		{
+			Severity: openapi2.SeverityWarning,
+			Pointer:  "/paths/~1pets/get/responses",
+			Rule:     "require-default-response",
+			Message:  "Operation has no default response",
+		},
+		{
+			Severity: openapi2.SeverityWarning,
+			Pointer:  "/paths/~1pets~1{id}/get/responses",
+			Rule:     "require-default-response",
+			Message:  "Operation has no default response",
+		},
+	}, issues)
+
+	// The 400 response of the post doesn't resolve
+	issues = swagger.ValidateIssues(ctx, openapi2.ValidationOptions{RequireErrorResponses: true})
+	require.Equal(t, []openapi2.Issue{
+		{
+			Severity: openapi2.SeverityWarning,
+			Pointer:  "/paths/~1pets/get/responses",
+			Rule:     "require-error-responses",
+			Message:  "Operation has no 4xx response",
+		},
+		{
+			Severity: openapi2.SeverityWarning,
+			Pointer:  "/paths/~1pets/post/responses",
+			Rule:     "require-error-responses",
+			Message:  "Operation has no 4xx response",
+		},
+	}, issues)
+
+	// Warnings don't make the document invalid, unless they are errors
+	opts := openapi2.ValidationOptions{RequireDefaultResponse: true, RequireErrorResponses: true}
+	require.NoError(t, swagger.ValidateWithOptions(ctx, opts))
+	opts.ResponseCoverageSeverity = openapi2.SeverityError
+	err := swagger.ValidateWithOptions(ctx, opts)
+	require.Error(t, err)
+	require.Len(t, err.(openapi2.MultiError), 4)
+	for _, issue := range swagger.ValidateIssues(ctx, opts) {
+		require.Equal(t, openapi2.SeverityError, issue.Severity)
+	}
+}
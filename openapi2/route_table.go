@@ -0,0 +1,58 @@
+package openapi2
+
+import (
+	"strings"
+	"text/tabwriter"
+)
+
+// RouteRow is a row of Swagger.RouteTableRows: an operation and the route it serves.
+type RouteRow struct {
+	// Method is an upper-case HTTP method such as "GET".
+	Method string
+	// Path is the path template prefixed with the base path, such as "/v1/pets/{id}".
+	Path        string
+	OperationID string
+	Summary     string
+}
+
+// RouteTableRows returns a row for every operation, sorted by path and then method.
+// The paths are prefixed with BasePath, normalized like ServerURLs does.
+func (swagger *Swagger) RouteTableRows() []RouteRow {
+	basePath := normalizeBasePath(swagger.BasePath)
+	var rows []RouteRow
+	swagger.walkOperations(func(path string, method string, operation *Operation) {
+		rows = append(rows, RouteRow{
+			Method:      method,
+			Path:        basePath + path,
+			OperationID: operation.OperationID,
+			Summary:     operation.Summary,
+		})
+	})
+	return rows
+}
+
+// RouteTable returns the rows of RouteTableRows as a table for humans, such as:
+//
+//	GET     /v1/pets       listPets    List the pets
+//	POST    /v1/pets       createPet   Create a pet
+//
+// Columns are separated by spaces and aligned, and each row ends with a newline.
+// Tabs and newlines of the summaries are replaced by spaces.
+func (swagger *Swagger) RouteTable() string {
+	var builder strings.Builder
+	w := tabwriter.NewWriter(&builder, 0, 4, 2, ' ', 0)
+	for _, row := range swagger.RouteTableRows() {
+		summary := strings.Join(strings.Fields(row.Summary), " ")
+		// Writing to a strings.Builder doesn't fail
+		_, _ = w.Write([]byte(row.Method + "\t" + row.Path + "\t" + row.OperationID + "\t" + summary + "\n"))
+	}
+	_ = w.Flush()
+	// Rows without summary keep the padding of the last column
+	lines := strings.SplitAfter(builder.String(), "\n")
+	for i, line := range lines {
+		if trimmed := strings.TrimRight(line, " \n"); trimmed != "" {
+			lines[i] = trimmed + "\n"
+		}
+	}
+	return strings.Join(lines, "")
+}
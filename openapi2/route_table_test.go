@@ -0,0 +1,36 @@
+package openapi2_test
+
+import (
+	"testing"
+
+	"github.com/mbilski/kin-openapi/openapi2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRouteTable(t *testing.T) {
+	swagger := loadSwagger(t, `
+{
+  "info": {"title": "MyAPI", "version": "0.1"},
+  "basePath": "/v1/",
+  "paths": {
+    "/pets/{id}": {
+      "delete": {"operationId": "deletePet", "responses": {"204": {"description": "deleted"}}},
+      "get": {"operationId": "getPet", "summary": "Get a pet", "responses": {"200": {"description": "pet"}}}
+    },
+    "/pets": {
+      "post": {"operationId": "createPet", "summary": "Create\na pet", "responses": {"201": {"description": "created"}}},
+      "get": {"operationId": "listPets", "summary": "List the pets", "responses": {"200": {"description": "pets"}}}
+    }
+  }
+}`)
+	rows := swagger.RouteTableRows()
+	require.Len(t, rows, 4)
+	require.Equal(t, openapi2.RouteRow{Method: "GET", Path: "/v1/pets", OperationID: "listPets", Summary: "List the pets"}, rows[0])
+	require.Equal(t, openapi2.RouteRow{Method: "DELETE", Path: "/v1/pets/{id}", OperationID: "deletePet"}, rows[2])
+
+	require.Equal(t, ""+
+		"GET     /v1/pets       listPets   List the pets\n"+
+		"POST    /v1/pets       createPet  Create a pet\n"+
+		"DELETE  /v1/pets/{id}  deletePet\n"+
+		"GET     /v1/pets/{id}  getPet     Get a pet\n", swagger.RouteTable())
+}
@@ -0,0 +1,53 @@
+package openapi2
+
+import (
+	"github.com/mbilski/kin-openapi/openapi3"
+)
+
+// ValidateSchemaExamples validates the examples of the definitions, and of the schemas nested in them
+// such as their properties, against the schema carrying them.
+// An example is given by "example", or else by the "x-example" extension.
+// It returns a MultiError of *LintError with the pointer of each invalid example, or nil.
+//
+// Local schema references are resolved on a copy, like ValidateRequest does.
+func (swagger *Swagger) ValidateSchemaExamples() error {
+	var errs MultiError
+	for _, name := range sortedSchemaNames(swagger.Definitions) {
+		walkSchemaRef("/definitions/"+escapePointerToken(name), swagger.Definitions[name], func(pointer string, schemaRef *openapi3.SchemaRef) {
+			if schemaRef.Ref != "" || schemaRef.Value == nil {
+				return
+			}
+			if err := swagger.validateSchemaExample(pointer, schemaRef); err != nil {
+				errs = append(errs, err)
+			}
+		})
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// validateSchemaExample validates the example of an inline schema, if any.
+func (swagger *Swagger) validateSchemaExample(pointer string, schemaRef *openapi3.SchemaRef) error {
+	example, key := schemaRef.Value.Example, "example"
+	if example == nil {
+		value, ok := schemaRef.Value.Extensions["x-example"]
+		if !ok {
+			return nil
+		}
+		key = "x-example"
+		if err := decodeExtension(value, &example); err != nil {
+			return &LintError{Pointer: pointer + "/" + key, Reason: "Example is not valid JSON: " + err.Error()}
+		}
+	}
+	pointer += "/" + key
+	resolved, err := swagger.resolvedSchemaCopy(schemaRef)
+	if err != nil {
+		return &LintError{Pointer: pointer, Reason: err.Error()}
+	}
+	if err := visitJSONSchema(resolved.Value, example); err != nil {
+		return &LintError{Pointer: pointer, Reason: "Example doesn't match the schema: " + err.Error()}
+	}
+	return nil
+}
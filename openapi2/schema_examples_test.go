@@ -0,0 +1,44 @@
+package openapi2_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mbilski/kin-openapi/openapi2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateSchemaExamples(t *testing.T) {
+	swagger := loadSwagger(t, `
+{
+  "info": {"title": "MyAPI", "version": "0.1"},
+  "paths": {},
+  "definitions": {
+    "Pet": {
+      "type": "object",
+      "properties": {
+        "name": {"type": "string", "maxLength": 3, "example": "Rex the dog"},
+        "tag": {"$ref": "#/definitions/Tag"}
+      },
+      "example": {"name": "Rex", "tag": "good"}
+    },
+    "Tag": {"type": "string", "minLength": 2, "x-example": "a"},
+    "Owner": {"type": "object", "properties": {"name": {"type": "string"}}}
+  }
+}`)
+	err := swagger.ValidateSchemaExamples()
+	require.IsType(t, openapi2.MultiError{}, err)
+	var messages []string
+	for _, err := range err.(openapi2.MultiError) {
+		// Schema errors go on with the schema and the value
+		messages = append(messages, strings.SplitN(err.Error(), "\n", 2)[0])
+	}
+	require.Equal(t, []string{
+		"/definitions/Pet/properties/name/example: Example doesn't match the schema: Maximum string length is 3",
+		"/definitions/Tag/x-example: Example doesn't match the schema: Minimum string length is 2",
+	}, messages)
+
+	swagger.Definitions["Pet"].Value.Properties["name"].Value.Example = "Rex"
+	swagger.Definitions["Tag"].Value.Extensions["x-example"] = "ab"
+	require.NoError(t, swagger.ValidateSchemaExamples())
+}
@@ -0,0 +1,95 @@
+package openapi2
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Security scheme types.
+const (
+	SecuritySchemeTypeBasic  = "basic"
+	SecuritySchemeTypeAPIKey = "apiKey"
+	SecuritySchemeTypeOAuth2 = "oauth2"
+)
+
+// OAuth2 flows of a security scheme.
+const (
+	OAuth2FlowImplicit    = "implicit"
+	OAuth2FlowPassword    = "password"
+	OAuth2FlowApplication = "application"
+	OAuth2FlowAccessCode  = "accessCode"
+)
+
+func (ss *SecurityScheme) IsBasic() bool {
+	return ss.Type == SecuritySchemeTypeBasic
+}
+
+func (ss *SecurityScheme) IsAPIKey() bool {
+	return ss.Type == SecuritySchemeTypeAPIKey
+}
+
+func (ss *SecurityScheme) IsOAuth2() bool {
+	return ss.Type == SecuritySchemeTypeOAuth2
+}
+
+// Resolve returns the scheme a security scheme refers to through its $ref, following
+// the references within "#/securityDefinitions", or the scheme itself without a $ref.
+//
+// OpenAPI 2 doesn't allow references in "securityDefinitions", but some documents alias
+// a scheme with one, such as {"$ref": "#/securityDefinitions/oauth"}, which validation follows.
+// It fails on a reference that doesn't resolve, including a reference cycle.
+func (ss *SecurityScheme) Resolve(swagger *Swagger) (*SecurityScheme, error) {
+	securityScheme := ss
+	for seen := 0; securityScheme != nil && securityScheme.Ref != ""; seen++ {
+		ref := securityScheme.Ref
+		if !strings.HasPrefix(ref, securityDefinitionsPrefix) || seen > len(swagger.SecurityDefinitions) {
+			return nil, failedToResolveRef(ref)
+		}
+		if securityScheme = swagger.SecurityDefinitions[ref[len(securityDefinitionsPrefix):]]; securityScheme == nil {
+			return nil, failedToResolveRef(ref)
+		}
+	}
+	return securityScheme, nil
+}
+
+// Validate checks that the scheme carries the fields its type and flow require.
+// A scheme with a $ref is valid, the scheme it resolves to is validated on its own.
+func (ss *SecurityScheme) Validate() error {
+	if ss.Ref != "" {
+		return nil
+	}
+	switch ss.Type {
+	case SecuritySchemeTypeBasic:
+	case SecuritySchemeTypeAPIKey:
+		if ss.Name == "" {
+			return fmt.Errorf("Security scheme of type '%s' must have a 'name'", ss.Type)
+		}
+		switch ss.In {
+		case "query", "header":
+		default:
+			return fmt.Errorf("Security scheme of type '%s' must be 'in' query or header, not '%s'", ss.Type, ss.In)
+		}
+	case SecuritySchemeTypeOAuth2:
+		var needsAuthorizationURL, needsTokenURL bool
+		switch ss.Flow {
+		case OAuth2FlowImplicit:
+			needsAuthorizationURL = true
+		case OAuth2FlowPassword, OAuth2FlowApplication:
+			needsTokenURL = true
+		case OAuth2FlowAccessCode:
+			needsAuthorizationURL = true
+			needsTokenURL = true
+		default:
+			return fmt.Errorf("Security scheme of type '%s' has unsupported flow '%s'", ss.Type, ss.Flow)
+		}
+		if needsAuthorizationURL && ss.AuthorizationURL == "" {
+			return fmt.Errorf("OAuth2 flow '%s' requires an 'authorizationUrl'", ss.Flow)
+		}
+		if needsTokenURL && ss.TokenURL == "" {
+			return fmt.Errorf("OAuth2 flow '%s' requires a 'tokenUrl'", ss.Flow)
+		}
+	default:
+		return fmt.Errorf("Security scheme has unsupported type '%s'", ss.Type)
+	}
+	return nil
+}
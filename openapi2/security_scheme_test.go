@@ -0,0 +1,109 @@
+package openapi2_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mbilski/kin-openapi/openapi2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSecuritySchemeKinds(t *testing.T) {
+	basic := &openapi2.SecurityScheme{Type: openapi2.SecuritySchemeTypeBasic}
+	require.True(t, basic.IsBasic())
+	require.False(t, basic.IsAPIKey())
+	require.False(t, basic.IsOAuth2())
+
+	oauth := &openapi2.SecurityScheme{Type: openapi2.SecuritySchemeTypeOAuth2}
+	require.True(t, oauth.IsOAuth2())
+	require.False(t, oauth.IsBasic())
+}
+
+func TestSecuritySchemeValidateFlows(t *testing.T) {
+	const (
+		authorizationURL = "https://auth.example.com/authorize"
+		tokenURL         = "https://auth.example.com/token"
+	)
+	tests := []struct {
+		flow             string
+		authorizationURL string
+		tokenURL         string
+		expectedError    string
+	}{
+		{openapi2.OAuth2FlowImplicit, authorizationURL, "", ""},
+		{openapi2.OAuth2FlowImplicit, "", tokenURL, "OAuth2 flow 'implicit' requires an 'authorizationUrl'"},
+		{openapi2.OAuth2FlowPassword, "", tokenURL, ""},
+		{openapi2.OAuth2FlowPassword, authorizationURL, "", "OAuth2 flow 'password' requires a 'tokenUrl'"},
+		{openapi2.OAuth2FlowApplication, "", tokenURL, ""},
+		{openapi2.OAuth2FlowApplication, "", "", "OAuth2 flow 'application' requires a 'tokenUrl'"},
+		{openapi2.OAuth2FlowAccessCode, authorizationURL, tokenURL, ""},
+		{openapi2.OAuth2FlowAccessCode, "", tokenURL, "OAuth2 flow 'accessCode' requires an 'authorizationUrl'"},
+		{openapi2.OAuth2FlowAccessCode, authorizationURL, "", "OAuth2 flow 'accessCode' requires a 'tokenUrl'"},
+		{"clientCredentials", "", tokenURL, "Security scheme of type 'oauth2' has unsupported flow 'clientCredentials'"},
+	}
+	for _, test := range tests {
+		t.Run(test.flow, func(t *testing.T) {
+			securityScheme := &openapi2.SecurityScheme{
+				Type:             openapi2.SecuritySchemeTypeOAuth2,
+				Flow:             test.flow,
+				AuthorizationURL: test.authorizationURL,
+				TokenURL:         test.tokenURL,
+			}
+			err := securityScheme.Validate()
+			if test.expectedError == "" {
+				require.NoError(t, err)
+			} else {
+				require.EqualError(t, err, test.expectedError)
+			}
+		})
+	}
+}
+
+func TestSecuritySchemeValidateAPIKey(t *testing.T) {
+	securityScheme := &openapi2.SecurityScheme{Type: openapi2.SecuritySchemeTypeAPIKey, In: "header"}
+	require.EqualError(t, securityScheme.Validate(), "Security scheme of type 'apiKey' must have a 'name'")
+	securityScheme.Name = "X-Key"
+	require.NoError(t, securityScheme.Validate())
+}
+
+func TestSecuritySchemeResolve(t *testing.T) {
+	swagger := loadSwagger(t, `
+{
+  "info": {"title": "MyAPI", "version": "0.1"},
+  "securityDefinitions": {
+    "oauth": {
+      "type": "oauth2",
+      "flow": "implicit",
+      "authorizationUrl": "https://auth.example.com/authorize",
+      "scopes": {"read": "read access"}
+    },
+    "legacy": {"$ref": "#/securityDefinitions/oauth"},
+    "missing": {"$ref": "#/securityDefinitions/Missing"}
+  },
+  "security": [{"legacy": ["read", "write"]}, {"missing": []}],
+  "paths": {}
+}`)
+	resolved, err := swagger.SecurityDefinitions["legacy"].Resolve(swagger)
+	require.NoError(t, err)
+	require.True(t, resolved == swagger.SecurityDefinitions["oauth"])
+
+	resolved, err = swagger.SecurityDefinitions["oauth"].Resolve(swagger)
+	require.NoError(t, err)
+	require.True(t, resolved == swagger.SecurityDefinitions["oauth"])
+
+	_, err = swagger.SecurityDefinitions["missing"].Resolve(swagger)
+	require.EqualError(t, err, "Failed to resolve ref: '#/securityDefinitions/Missing'")
+
+	// Requirements are checked against the resolved scheme
+	err = swagger.Validate(context.Background())
+	require.Equal(t, openapi2.MultiError{
+		&openapi2.LintError{
+			Pointer: "/securityDefinitions/missing",
+			Reason:  "Failed to resolve ref: '#/securityDefinitions/Missing'",
+		},
+		&openapi2.LintError{
+			Pointer: "/security/0/legacy",
+			Reason:  "Scope 'write' is not declared by security scheme 'legacy'",
+		},
+	}, err)
+}
@@ -0,0 +1,130 @@
+package openapi2
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// ServerURLs returns the base URLs described by Schemes, Host and BasePath.
+//
+// Host may carry a port ("api.example.com:8443") and may be a bracketed IPv6 literal ("[::1]:8080").
+// BasePath is normalized to have a leading slash and no trailing slash, so "v1/" becomes "/v1".
+// When no scheme is declared "https" is assumed.
+// It returns no URLs when Host is empty and an error when Host is malformed.
+func (swagger *Swagger) ServerURLs() ([]string, error) {
+	if swagger.Host == "" {
+		return nil, nil
+	}
+	host, err := normalizeHost(swagger.Host)
+	if err != nil {
+		return nil, err
+	}
+	basePath := normalizeBasePath(swagger.BasePath)
+	schemes := swagger.Schemes
+	if len(schemes) == 0 {
+		schemes = []string{"https"}
+	}
+	urls := make([]string, 0, len(schemes))
+	for _, scheme := range schemes {
+		u := url.URL{
+			Scheme: strings.ToLower(scheme),
+			Host:   host,
+			Path:   basePath,
+		}
+		urls = append(urls, u.String())
+	}
+	return urls, nil
+}
+
+func normalizeHost(host string) (string, error) {
+	if strings.ContainsAny(host, "/?#@") || strings.TrimSpace(host) != host {
+		return "", fmt.Errorf("Invalid host '%s': must be a host name or IP address with an optional port", host)
+	}
+	u, err := url.Parse("//" + host)
+	if err != nil || u.Host != host {
+		return "", fmt.Errorf("Invalid host '%s'", host)
+	}
+	hostname := u.Hostname()
+	if hostname == "" {
+		return "", fmt.Errorf("Invalid host '%s': missing host name", host)
+	}
+	if strings.HasPrefix(host, "[") {
+		if net.ParseIP(hostname) == nil {
+			return "", fmt.Errorf("Invalid host '%s': malformed IPv6 address", host)
+		}
+	} else if strings.Contains(hostname, ":") {
+		return "", fmt.Errorf("Invalid host '%s': IPv6 addresses must be enclosed in brackets", host)
+	}
+	if port := u.Port(); port != "" {
+		if n, err := strconv.ParseUint(port, 10, 16); err != nil || n == 0 {
+			return "", fmt.Errorf("Invalid host '%s': port must be between 1 and 65535", host)
+		}
+	} else if strings.HasSuffix(host, ":") {
+		return "", fmt.Errorf("Invalid host '%s': missing port", host)
+	}
+	return host, nil
+}
+
+func normalizeBasePath(basePath string) string {
+	basePath = strings.Trim(basePath, "/")
+	if basePath == "" {
+		return ""
+	}
+	return "/" + basePath
+}
+
+// validateHost checks that a host is empty or a host name or IP address with an optional port,
+// as ServerURLs requires.
+func validateHost(host string) error {
+	if host == "" {
+		return nil
+	}
+	if strings.Contains(host, "://") {
+		return fmt.Errorf("Invalid host '%s': must not include a scheme, which belongs to 'schemes'", host)
+	}
+	_, err := normalizeHost(host)
+	return err
+}
+
+// validateBasePath checks that a basePath is empty or an absolute URL path,
+// without a query or a fragment.
+func validateBasePath(basePath string) error {
+	if basePath == "" {
+		return nil
+	}
+	if !strings.HasPrefix(basePath, "/") {
+		return fmt.Errorf("Invalid basePath '%s': must start with '/'", basePath)
+	}
+	if strings.ContainsAny(basePath, "?#") {
+		return fmt.Errorf("Invalid basePath '%s': must not contain a query or a fragment", basePath)
+	}
+	for i := 0; i < len(basePath); i++ {
+		c := basePath[i]
+		switch {
+		case c == '%':
+			if i+2 >= len(basePath) || !isHexDigit(basePath[i+1]) || !isHexDigit(basePath[i+2]) {
+				return fmt.Errorf("Invalid basePath '%s': malformed percent-encoding", basePath)
+			}
+			i += 2
+		case !isPathByte(c):
+			return fmt.Errorf("Invalid basePath '%s': character '%c' must be percent-encoded", basePath, c)
+		}
+	}
+	return nil
+}
+
+// isPathByte reports whether a byte may appear as it is in the segments of a URL path (RFC 3986).
+func isPathByte(c byte) bool {
+	switch {
+	case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z', '0' <= c && c <= '9':
+		return true
+	}
+	return strings.IndexByte("-._~!$&'()*+,;=:@/", c) >= 0
+}
+
+func isHexDigit(c byte) bool {
+	return '0' <= c && c <= '9' || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F'
+}
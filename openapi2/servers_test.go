@@ -0,0 +1,61 @@
+package openapi2_test
+
+import (
+	"testing"
+
+	"github.com/mbilski/kin-openapi/openapi2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServerURLs(t *testing.T) {
+	tests := []struct {
+		name     string
+		swagger  openapi2.Swagger
+		expected []string
+	}{
+		{
+			"no host",
+			openapi2.Swagger{BasePath: "/v1"},
+			nil,
+		},
+		{
+			"IPv6 literal with port",
+			openapi2.Swagger{Host: "[::1]:8080", BasePath: "/v1", Schemes: []string{"http"}},
+			[]string{"http://[::1]:8080/v1"},
+		},
+		{
+			"base path without leading slash",
+			openapi2.Swagger{Host: "api.example.com:8443", BasePath: "v1/", Schemes: []string{"https", "http"}},
+			[]string{"https://api.example.com:8443/v1", "http://api.example.com:8443/v1"},
+		},
+		{
+			"empty base path and default scheme",
+			openapi2.Swagger{Host: "api.example.com"},
+			[]string{"https://api.example.com"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			urls, err := test.swagger.ServerURLs()
+			require.NoError(t, err)
+			require.Equal(t, test.expected, urls)
+		})
+	}
+}
+
+func TestServerURLsMalformedHost(t *testing.T) {
+	for _, host := range []string{
+		"https://api.example.com",
+		"api.example.com/v1",
+		"::1",
+		"[::1",
+		"api.example.com:port",
+		"api.example.com:70000",
+		"api.example.com:",
+		" api.example.com",
+	} {
+		swagger := openapi2.Swagger{Host: host}
+		_, err := swagger.ServerURLs()
+		require.Error(t, err, host)
+	}
+}
@@ -0,0 +1,55 @@
+package openapi2
+
+// SwaggerStats summarizes the size and documentation coverage of a document.
+type SwaggerStats struct {
+	Paths      int
+	Operations int
+	// OperationsByMethod counts operations by upper-case HTTP method.
+	OperationsByMethod map[string]int
+	// ParametersByIn counts path item and operation parameters by location.
+	// Parameter references are resolved; unresolvable ones are counted under "".
+	ParametersByIn map[string]int
+	Definitions    int
+	// Responses counts the responses declared by operations.
+	Responses                    int
+	OperationsWithoutDescription int
+	OperationsWithoutOperationID int
+}
+
+// Stats computes SwaggerStats in a single pass over the paths.
+func (swagger *Swagger) Stats() SwaggerStats {
+	stats := SwaggerStats{
+		Definitions:        len(swagger.Definitions),
+		OperationsByMethod: make(map[string]int),
+		ParametersByIn:     make(map[string]int),
+	}
+	countParameters := func(parameters Parameters) {
+		for _, parameter := range parameters {
+			in := ""
+			if resolved, err := swagger.resolveParameter(parameter); err == nil && resolved != nil {
+				in = resolved.In
+			}
+			stats.ParametersByIn[in]++
+		}
+	}
+	for _, pathItem := range swagger.Paths {
+		if pathItem == nil {
+			continue
+		}
+		stats.Paths++
+		countParameters(pathItem.Parameters)
+		for method, operation := range pathItem.Operations() {
+			stats.Operations++
+			stats.OperationsByMethod[method]++
+			stats.Responses += len(operation.Responses)
+			if operation.Description == "" {
+				stats.OperationsWithoutDescription++
+			}
+			if operation.OperationID == "" {
+				stats.OperationsWithoutOperationID++
+			}
+			countParameters(operation.Parameters)
+		}
+	}
+	return stats
+}
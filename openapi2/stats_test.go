@@ -0,0 +1,34 @@
+package openapi2_test
+
+import (
+	"testing"
+
+	"github.com/mbilski/kin-openapi/openapi2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStats(t *testing.T) {
+	swagger := loadSwaggerFile(t, "testdata/swagger.json")
+
+	require.Equal(t, openapi2.SwaggerStats{
+		Paths:      14,
+		Operations: 20,
+		OperationsByMethod: map[string]int{
+			"DELETE": 3,
+			"GET":    8,
+			"POST":   7,
+			"PUT":    2,
+		},
+		ParametersByIn: map[string]int{
+			"body":     7,
+			"formData": 4,
+			"header":   1,
+			"path":     9,
+			"query":    4,
+		},
+		Definitions:                  6,
+		Responses:                    36,
+		OperationsWithoutDescription: 11,
+		OperationsWithoutOperationID: 0,
+	}, swagger.Stats())
+}
@@ -0,0 +1,70 @@
+package openapi2
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// StatusCodes returns the keys of the operation responses whose $ref, if any, resolves,
+// such as ["200", "404", "4XX", "default"].
+// Status codes are sorted numerically, each range such as "4XX" follows the codes it covers,
+// and "default" comes last. Other keys come before "default", sorted as strings.
+func (operation *Operation) StatusCodes(swagger *Swagger) []string {
+	codes := make([]string, 0, len(operation.Responses))
+	for key, response := range operation.Responses {
+		if resolved, err := swagger.resolveResponse(response); err == nil && resolved != nil {
+			codes = append(codes, key)
+		}
+	}
+	sort.Slice(codes, func(i, j int) bool {
+		rankI, rankJ := statusCodeRank(codes[i]), statusCodeRank(codes[j])
+		if rankI != rankJ {
+			return rankI < rankJ
+		}
+		return codes[i] < codes[j]
+	})
+	return codes
+}
+
+// statusCodeRank orders the response keys for Operation.StatusCodes.
+func statusCodeRank(key string) int {
+	if code, err := strconv.Atoi(key); err == nil && len(key) == 3 {
+		return code * 2
+	}
+	if len(key) == 3 && key[0] >= '1' && key[0] <= '5' && strings.EqualFold(key[1:], "XX") {
+		// After the codes of the range
+		return (int(key[0]-'0')*100+99)*2 + 1
+	}
+	if key == "default" {
+		return 2000
+	}
+	return 1999
+}
+
+// ResponseFor returns the response of the operation for a status code: the response of the code,
+// or else of its range such as "4XX" for 404, or else the "default" response.
+// The key of a range is case-insensitive, and the $ref of the response is resolved.
+// It returns false when no response applies, or when the applying one doesn't resolve.
+func (operation *Operation) ResponseFor(status int, swagger *Swagger) (*Response, bool) {
+	response, ok := operation.Responses[strconv.Itoa(status)]
+	if !ok {
+		class := strconv.Itoa(status / 100)
+		for key, candidate := range operation.Responses {
+			if len(key) == 3 && key[:1] == class && strings.EqualFold(key[1:], "XX") {
+				response, ok = candidate, true
+				break
+			}
+		}
+	}
+	if !ok {
+		if response, ok = operation.Responses["default"]; !ok {
+			return nil, false
+		}
+	}
+	resolved, err := swagger.resolveResponse(response)
+	if err != nil || resolved == nil {
+		return nil, false
+	}
+	return resolved, true
+}
@@ -0,0 +1,61 @@
+package openapi2_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOperationResponseFor(t *testing.T) {
+	swagger := loadSwagger(t, `
+{
+  "info": {"title": "MyAPI", "version": "0.1"},
+  "responses": {
+    "NotFound": {"description": "not found"}
+  },
+  "paths": {
+    "/pets/{id}": {
+      "get": {
+        "parameters": [{"in": "path", "name": "id", "type": "string", "required": true}],
+        "responses": {
+          "default": {"description": "error"},
+          "4xx": {"description": "client error"},
+          "404": {"$ref": "#/responses/NotFound"},
+          "410": {"$ref": "#/responses/Missing"},
+          "200": {"description": "pet"},
+          "2XX": {"description": "success"},
+          "201": {"description": "created"}
+        }
+      }
+    }
+  }
+}`)
+	operation := swagger.Paths["/pets/{id}"].Get
+	require.Equal(t, []string{"200", "201", "2XX", "404", "4xx", "default"}, operation.StatusCodes(swagger))
+
+	for _, tc := range []struct {
+		status      int
+		description string
+	}{
+		// Exact match, through a $ref
+		{200, "pet"},
+		{404, "not found"},
+		// Range match
+		{204, "success"},
+		{400, "client error"},
+		// Default fallback
+		{500, "error"},
+	} {
+		response, ok := operation.ResponseFor(tc.status, swagger)
+		require.True(t, ok, tc.status)
+		require.Equal(t, tc.description, response.Description, tc.status)
+	}
+
+	// The response of the code doesn't resolve
+	_, ok := operation.ResponseFor(410, swagger)
+	require.False(t, ok)
+
+	delete(operation.Responses, "default")
+	_, ok = operation.ResponseFor(500, swagger)
+	require.False(t, ok)
+}
@@ -0,0 +1,123 @@
+package openapi2
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/mbilski/kin-openapi/openapi3"
+)
+
+// Subset returns a copy of the document with only the operations carrying the given tag.
+// Path items left without operations are removed, and the copy keeps only the definitions,
+// parameters and responses its paths reference, directly or transitively,
+// including from extensions like PruneUnused with FollowExtensionRefs.
+// Of the document tags, only the entry of the given tag is kept.
+func (swagger *Swagger) Subset(tag string) (*Swagger, error) {
+	return swagger.subset(func(operation *Operation) bool {
+		return hasTag(operation, tag)
+	}, func(name string) bool {
+		return name == tag
+	})
+}
+
+// SharedSplitKey is the key of the document SplitByTag returns for the operations
+// that don't have exactly one tag.
+const SharedSplitKey = "_shared"
+
+// SplitByTag splits the document into one document per tag, such as Subset returns,
+// each with the operations having that tag only.
+// The operations without a tag or with several tags go to the SharedSplitKey document,
+// which keeps the entries of their tags, and is omitted when there are no such operations.
+//
+// Each document keeps the definitions, parameters and responses it needs,
+// so a definition used by several of them is copied into each one.
+// A tag named like SharedSplitKey is an error.
+func (swagger *Swagger) SplitByTag() (map[string]*Swagger, error) {
+	tags := make(map[string]struct{})
+	shared := make(map[string]struct{})
+	hasShared := false
+	swagger.walkOperations(func(_ string, _ string, operation *Operation) {
+		if len(operation.Tags) == 1 {
+			tags[operation.Tags[0]] = struct{}{}
+			return
+		}
+		hasShared = true
+		for _, tag := range operation.Tags {
+			shared[tag] = struct{}{}
+		}
+	})
+	if _, ok := tags[SharedSplitKey]; ok {
+		return nil, fmt.Errorf("Tag '%s' is reserved for the operations without exactly one tag", SharedSplitKey)
+	}
+	result := make(map[string]*Swagger, len(tags)+1)
+	for tag := range tags {
+		tag := tag
+		subset, err := swagger.subset(func(operation *Operation) bool {
+			return len(operation.Tags) == 1 && operation.Tags[0] == tag
+		}, func(name string) bool {
+			return name == tag
+		})
+		if err != nil {
+			return nil, err
+		}
+		result[tag] = subset
+	}
+	if hasShared {
+		subset, err := swagger.subset(func(operation *Operation) bool {
+			return len(operation.Tags) != 1
+		}, func(name string) bool {
+			_, ok := shared[name]
+			return ok
+		})
+		if err != nil {
+			return nil, err
+		}
+		result[SharedSplitKey] = subset
+	}
+	return result, nil
+}
+
+// subset returns a copy of the document with only the operations accepted by keepOperation,
+// and the tag entries accepted by keepTag.
+func (swagger *Swagger) subset(keepOperation func(*Operation) bool, keepTag func(string) bool) (*Swagger, error) {
+	data, err := json.Marshal(swagger)
+	if err != nil {
+		return nil, err
+	}
+	subset := &Swagger{}
+	if err := json.Unmarshal(data, subset); err != nil {
+		return nil, err
+	}
+	for path, pathItem := range subset.Paths {
+		if pathItem == nil {
+			delete(subset.Paths, path)
+			continue
+		}
+		for method, operation := range pathItem.Operations() {
+			if !keepOperation(operation) {
+				pathItem.SetOperation(method, nil)
+			}
+		}
+		if len(pathItem.Operations()) == 0 {
+			delete(subset.Paths, path)
+		}
+	}
+	subset.pruneComponents(true)
+	var tags openapi3.Tags
+	for _, item := range subset.Tags {
+		if item != nil && keepTag(item.Name) {
+			tags = append(tags, item)
+		}
+	}
+	subset.Tags = tags
+	return subset, nil
+}
+
+func hasTag(operation *Operation, tag string) bool {
+	for _, item := range operation.Tags {
+		if item == tag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,164 @@
+package openapi2_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mbilski/kin-openapi/openapi2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubset(t *testing.T) {
+	swagger := loadSwagger(t, `
+{
+  "info": {"title": "MyAPI", "version": "0.1"},
+  "tags": [{"name": "pets", "description": "Pets"}, {"name": "users"}],
+  "paths": {
+    "/pets": {
+      "get": {
+        "tags": ["pets"],
+        "parameters": [{"$ref": "#/parameters/limit"}],
+        "responses": {
+          "200": {"description": "pets", "schema": {"type": "array", "items": {"$ref": "#/definitions/Pet"}}},
+          "default": {"$ref": "#/responses/Error"}
+        }
+      },
+      "post": {
+        "tags": ["admin"],
+        "parameters": [{"in": "body", "name": "pet", "schema": {"$ref": "#/definitions/NewPet"}}],
+        "responses": {"201": {"description": "created"}}
+      }
+    },
+    "/users": {
+      "get": {
+        "tags": ["users"],
+        "parameters": [{"$ref": "#/parameters/offset"}],
+        "responses": {"200": {"description": "users", "schema": {"$ref": "#/definitions/User"}}}
+      }
+    }
+  },
+  "parameters": {
+    "limit": {"in": "query", "name": "limit", "type": "integer"},
+    "offset": {"in": "query", "name": "offset", "type": "integer"}
+  },
+  "responses": {
+    "Error": {"description": "error", "schema": {"$ref": "#/definitions/Error"}},
+    "NotFound": {"description": "not found"}
+  },
+  "definitions": {
+    "Error": {"type": "object"},
+    "NewPet": {"type": "object"},
+    "Owner": {"type": "object"},
+    "Pet": {"type": "object", "properties": {"owner": {"$ref": "#/definitions/Owner"}}},
+    "User": {"type": "object"}
+  }
+}`)
+	subset, err := swagger.Subset("pets")
+	require.NoError(t, err)
+	require.Len(t, subset.Paths, 1)
+	require.NotNil(t, subset.Paths["/pets"].Get)
+	require.Nil(t, subset.Paths["/pets"].Post)
+	require.Len(t, subset.Definitions, 3)
+	require.Contains(t, subset.Definitions, "Error")
+	require.Contains(t, subset.Definitions, "Owner")
+	require.Contains(t, subset.Definitions, "Pet")
+	require.Len(t, subset.Parameters, 1)
+	require.Contains(t, subset.Parameters, "limit")
+	require.Len(t, subset.Responses, 1)
+	require.Contains(t, subset.Responses, "Error")
+	require.Len(t, subset.Tags, 1)
+	require.Equal(t, "Pets", subset.Tags[0].Description)
+
+	// The document itself is left untouched
+	require.NotNil(t, swagger.Paths["/pets"].Post)
+	require.Len(t, swagger.Definitions, 5)
+
+	subset, err = swagger.Subset("missing")
+	require.NoError(t, err)
+	require.Empty(t, subset.Paths)
+	require.Empty(t, subset.Definitions)
+	require.Empty(t, subset.Tags)
+}
+
+func TestSplitByTag(t *testing.T) {
+	swagger := loadSwagger(t, `
+{
+  "info": {"title": "MyAPI", "version": "0.1"},
+  "tags": [{"name": "pets"}, {"name": "users"}, {"name": "admin"}],
+  "paths": {
+    "/pets": {
+      "get": {
+        "tags": ["pets"],
+        "responses": {
+          "200": {"description": "pets", "schema": {"type": "array", "items": {"$ref": "#/definitions/Pet"}}},
+          "default": {"$ref": "#/responses/Error"}
+        }
+      },
+      "delete": {
+        "responses": {"204": {"description": "deleted"}}
+      }
+    },
+    "/users": {
+      "get": {
+        "tags": ["users"],
+        "responses": {
+          "200": {"description": "users", "schema": {"$ref": "#/definitions/User"}},
+          "default": {"$ref": "#/responses/Error"}
+        }
+      }
+    },
+    "/users/{id}/pets": {
+      "get": {
+        "tags": ["users", "pets"],
+        "parameters": [{"in": "path", "name": "id", "type": "string", "required": true}],
+        "responses": {"200": {"description": "pets", "schema": {"$ref": "#/definitions/Pet"}}}
+      }
+    }
+  },
+  "responses": {
+    "Error": {"description": "error", "schema": {"$ref": "#/definitions/Error"}}
+  },
+  "definitions": {
+    "Error": {"type": "object"},
+    "Pet": {"type": "object"},
+    "User": {"type": "object"}
+  }
+}`)
+	files, err := swagger.SplitByTag()
+	require.NoError(t, err)
+	require.Len(t, files, 3)
+	for name, file := range files {
+		require.NoError(t, file.Validate(context.Background()), name)
+	}
+
+	pets := files["pets"]
+	require.Len(t, pets.Paths, 1)
+	require.NotNil(t, pets.Paths["/pets"].Get)
+	require.Nil(t, pets.Paths["/pets"].Delete)
+	require.Len(t, pets.Tags, 1)
+	require.Equal(t, "pets", pets.Tags[0].Name)
+
+	users := files["users"]
+	require.Len(t, users.Paths, 1)
+	require.NotNil(t, users.Paths["/users"].Get)
+
+	shared := files[openapi2.SharedSplitKey]
+	require.Len(t, shared.Paths, 2)
+	require.NotNil(t, shared.Paths["/pets"].Delete)
+	require.Nil(t, shared.Paths["/pets"].Get)
+	require.NotNil(t, shared.Paths["/users/{id}/pets"].Get)
+	require.Len(t, shared.Tags, 2)
+
+	// Shared definitions are copied into each file using them
+	require.Contains(t, pets.Definitions, "Error")
+	require.Contains(t, users.Definitions, "Error")
+	require.Contains(t, pets.Definitions, "Pet")
+	require.Contains(t, shared.Definitions, "Pet")
+	require.NotContains(t, users.Definitions, "Pet")
+	require.NotContains(t, shared.Definitions, "Error")
+	require.True(t, pets.Definitions["Pet"] != shared.Definitions["Pet"])
+
+	swagger.Paths["/users"].Get.Tags = []string{openapi2.SharedSplitKey}
+	_, err = swagger.SplitByTag()
+	require.EqualError(t, err, "Tag '_shared' is reserved for the operations without exactly one tag")
+}
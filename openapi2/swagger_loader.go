@@ -0,0 +1,417 @@
+package openapi2
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/mbilski/kin-openapi/jsoninfo"
+	"github.com/mbilski/kin-openapi/openapi3"
+	yamlv2 "gopkg.in/yaml.v2"
+)
+
+// SwaggerLoader loads OpenAPI 2 documents from JSON or YAML.
+type SwaggerLoader struct {
+	// DetectDuplicateKeys makes loading fail when an object declares the same key twice,
+	// such as two "get" operations under one path.
+	// The standard decoders silently keep the last value.
+	DetectDuplicateKeys bool
+
+	// RefLoader, when set, makes loading resolve external $refs, as described by ResolveRefsIn.
+	// Relative references resolve against the loaded file, or the working directory
+	// when loading from data.
+	RefLoader RefLoader
+
+	// MaxBytes, when positive, makes loading fail once the document exceeds that many bytes.
+	// The limit is enforced while reading, so a larger input is never buffered in full.
+	// It doesn't apply to the documents loaded by the RefLoader.
+	MaxBytes int64
+
+	// PreserveComments makes loading a YAML document capture the comments of its
+	// mapping keys and sequence items into Swagger.Comments, so that
+	// Swagger.MarshalYAMLWithComments can restore them.
+	PreserveComments bool
+
+	// DecoderOptions apply to each object of the loaded document,
+	// such as DecoderOptions.PreserveFieldOrder recording the order of its keys.
+	// YAML documents are converted to JSON with sorted keys, so their order isn't recorded.
+	DecoderOptions jsoninfo.DecoderOptions
+}
+
+// DocumentTooLargeError is returned when a document exceeds SwaggerLoader.MaxBytes.
+type DocumentTooLargeError struct {
+	MaxBytes int64
+}
+
+func (err *DocumentTooLargeError) Error() string {
+	return fmt.Sprintf("Document exceeds the limit of %d bytes", err.MaxBytes)
+}
+
+func NewSwaggerLoader() *SwaggerLoader {
+	return &SwaggerLoader{}
+}
+
+func (swaggerLoader *SwaggerLoader) LoadSwaggerFromFile(path string) (*Swagger, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	ctx := context.Background()
+	data, err := swaggerLoader.read(ctx, f)
+	if err != nil {
+		return nil, err
+	}
+	return swaggerLoader.loadSwaggerFromData(ctx, data, &url.URL{Path: path})
+}
+
+func (swaggerLoader *SwaggerLoader) LoadSwaggerFromData(data []byte) (*Swagger, error) {
+	if max := swaggerLoader.MaxBytes; max > 0 && int64(len(data)) > max {
+		return nil, &DocumentTooLargeError{MaxBytes: max}
+	}
+	return swaggerLoader.loadSwaggerFromData(context.Background(), data, nil)
+}
+
+// LoadSwaggerFromReader loads a document from r, within the budget of ctx and MaxBytes.
+//
+// Loading is aborted with the error of ctx once it is done: the context is checked
+// between reads, between the decoding steps and before each external document is loaded.
+// Loading is aborted with a *DocumentTooLargeError as soon as more than MaxBytes are read.
+func (swaggerLoader *SwaggerLoader) LoadSwaggerFromReader(ctx context.Context, r io.Reader) (*Swagger, error) {
+	data, err := swaggerLoader.read(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+	return swaggerLoader.loadSwaggerFromData(ctx, data, nil)
+}
+
+// read reads r in full, failing once ctx is done or more than MaxBytes were read.
+func (swaggerLoader *SwaggerLoader) read(ctx context.Context, r io.Reader) ([]byte, error) {
+	max := swaggerLoader.MaxBytes
+	if max > 0 {
+		// One more byte tells a document of exactly MaxBytes from a larger one
+		r = io.LimitReader(r, max+1)
+	}
+	var buf bytes.Buffer
+	chunk := make([]byte, 32*1024)
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		n, err := r.Read(chunk)
+		buf.Write(chunk[:n])
+		if max > 0 && int64(buf.Len()) > max {
+			return nil, &DocumentTooLargeError{MaxBytes: max}
+		}
+		if err == io.EOF {
+			return buf.Bytes(), nil
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (swaggerLoader *SwaggerLoader) loadSwaggerFromData(ctx context.Context, data []byte, base *url.URL) (*Swagger, error) {
+	if swaggerLoader.DetectDuplicateKeys {
+		if err := detectDuplicateKeys(data); err != nil {
+			return nil, err
+		}
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+	}
+	jsonData := data
+	if trimmed := bytes.TrimSpace(data); len(trimmed) == 0 || trimmed[0] != '{' {
+		// Same as yaml.Unmarshal, which gives no type hints to a json.Unmarshaler such as Swagger
+		var err error
+		if jsonData, err = yaml.YAMLToJSON(data); err != nil {
+			return nil, fmt.Errorf("error converting YAML to JSON: %v", err)
+		}
+	}
+	swagger := &Swagger{}
+	if err := jsoninfo.UnmarshalWithOptions(jsonData, swagger, swaggerLoader.DecoderOptions); err != nil {
+		return nil, fmt.Errorf("error unmarshaling JSON: %v", err)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if swaggerLoader.PreserveComments {
+		if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] != '{' {
+			swagger.Comments = readYAMLComments(data)
+		}
+	}
+	if refLoader := swaggerLoader.RefLoader; refLoader != nil {
+		resolver := *swaggerLoader
+		resolver.RefLoader = RefLoaderFunc(func(location *url.URL) ([]byte, error) {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			return refLoader.LoadRef(location)
+		})
+		if err := resolver.ResolveRefsIn(swagger, base); err != nil {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return nil, ctxErr
+			}
+			return nil, err
+		}
+	}
+	return swagger, nil
+}
+
+// DuplicateKeyError is returned when a document declares the same key twice in an object.
+type DuplicateKeyError struct {
+	Key string
+	// Pointer is the JSON pointer of the object holding the key.
+	Pointer string
+	// Line is the 1-based line of the second declaration, or 0 when unknown.
+	Line int
+}
+
+func (err *DuplicateKeyError) Error() string {
+	pointer := err.Pointer
+	if pointer == "" {
+		pointer = "/"
+	}
+	if err.Line > 0 {
+		return fmt.Sprintf("Duplicate key '%s' in '%s' at line %d", err.Key, pointer, err.Line)
+	}
+	return fmt.Sprintf("Duplicate key '%s' in '%s'", err.Key, pointer)
+}
+
+func detectDuplicateKeys(data []byte) error {
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+		return detectDuplicateJSONKeys(data)
+	}
+	var document yamlv2.MapSlice
+	if err := yamlv2.Unmarshal(data, &document); err != nil {
+		return err
+	}
+	return detectDuplicateYAMLKeys("", document)
+}
+
+func detectDuplicateYAMLKeys(pointer string, value interface{}) error {
+	switch value := value.(type) {
+	case yamlv2.MapSlice:
+		seen := make(map[string]struct{}, len(value))
+		for _, item := range value {
+			key := fmt.Sprint(item.Key)
+			if _, ok := seen[key]; ok {
+				return &DuplicateKeyError{Key: key, Pointer: pointer}
+			}
+			seen[key] = struct{}{}
+			if err := detectDuplicateYAMLKeys(pointer+"/"+escapePointerToken(key), item.Value); err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		for i, item := range value {
+			if err := detectDuplicateYAMLKeys(pointer+"/"+strconv.Itoa(i), item); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func detectDuplicateJSONKeys(data []byte) error {
+	// The decoder reads a byte at a time, so that the bytes read locate the current token
+	reader := &countingReader{data: data}
+	decoder := json.NewDecoder(reader)
+	lineAt := func(key string) int {
+		read := data[:reader.offset]
+		if quoted, err := json.Marshal(key); err == nil {
+			if i := bytes.LastIndex(read, quoted); i >= 0 {
+				read = read[:i]
+			}
+		}
+		return bytes.Count(read, []byte("\n")) + 1
+	}
+	var walk func(pointer string) error
+	walk = func(pointer string) error {
+		token, err := decoder.Token()
+		if err != nil {
+			return err
+		}
+		switch token {
+		case json.Delim('{'):
+			seen := make(map[string]struct{})
+			for decoder.More() {
+				token, err := decoder.Token()
+				if err != nil {
+					return err
+				}
+				key := token.(string)
+				if _, ok := seen[key]; ok {
+					return &DuplicateKeyError{Key: key, Pointer: pointer, Line: lineAt(key)}
+				}
+				seen[key] = struct{}{}
+				if err := walk(pointer + "/" + escapePointerToken(key)); err != nil {
+					return err
+				}
+			}
+			_, err = decoder.Token()
+			return err
+		case json.Delim('['):
+			for i := 0; decoder.More(); i++ {
+				if err := walk(pointer + "/" + strconv.Itoa(i)); err != nil {
+					return err
+				}
+			}
+			_, err = decoder.Token()
+			return err
+		}
+		return nil
+	}
+	if err := walk(""); err != nil && err != io.EOF {
+		return err
+	}
+	return nil
+}
+
+// countingReader reads data a byte at a time, counting the bytes read.
+type countingReader struct {
+	data   []byte
+	offset int
+}
+
+func (reader *countingReader) Read(p []byte) (int, error) {
+	if reader.offset >= len(reader.data) {
+		return 0, io.EOF
+	}
+	if len(p) == 0 {
+		return 0, nil
+	}
+	p[0] = reader.data[reader.offset]
+	reader.offset++
+	return 1, nil
+}
+
+// LoadSwaggerPaths loads the paths accepted by pathFilter, and only the definitions,
+// parameters and responses they reference, directly or transitively.
+//
+// Everything else in "paths", "definitions", "parameters" and "responses" is skipped:
+// the input is still scanned in full, but skipped elements are never decoded into
+// Go values, which saves memory and time on very large documents.
+// A reference to a component is always followed, so a kept path never has a dangling local $ref.
+//
+// The exception is YAML input: it is converted to JSON first, which decodes the whole document.
+func LoadSwaggerPaths(data []byte, pathFilter func(string) bool) (*Swagger, error) {
+	if trimmed := bytes.TrimSpace(data); len(trimmed) == 0 || trimmed[0] != '{' {
+		var err error
+		if data, err = yaml.YAMLToJSON(data); err != nil {
+			return nil, err
+		}
+	}
+	var document map[string]json.RawMessage
+	if err := json.Unmarshal(data, &document); err != nil {
+		return nil, err
+	}
+	sections := make(map[string]map[string]json.RawMessage, 4)
+	for _, key := range []string{"paths", "definitions", "parameters", "responses"} {
+		if raw, ok := document[key]; ok {
+			var section map[string]json.RawMessage
+			if err := json.Unmarshal(raw, &section); err != nil {
+				return nil, fmt.Errorf("Error while unmarshalling property '%s': %v", key, err)
+			}
+			sections[key] = section
+			delete(document, key)
+		}
+	}
+	rest, err := json.Marshal(document)
+	if err != nil {
+		return nil, err
+	}
+	swagger := &Swagger{}
+	if err := json.Unmarshal(rest, swagger); err != nil {
+		return nil, err
+	}
+
+	var pending []string
+	queue := func(_ string, ref *string) {
+		pending = append(pending, *ref)
+	}
+	for path, raw := range sections["paths"] {
+		if !pathFilter(path) {
+			continue
+		}
+		pathItem := &PathItem{}
+		if err := json.Unmarshal(raw, pathItem); err != nil {
+			return nil, fmt.Errorf("Error while unmarshalling path '%s': %v", path, err)
+		}
+		if swagger.Paths == nil {
+			swagger.Paths = make(map[string]*PathItem)
+		}
+		swagger.Paths[path] = pathItem
+		walkPathItemRefs("", pathItem, queue)
+	}
+
+	for len(pending) > 0 {
+		ref := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+		if name, _, ok := definitionName(ref); ok {
+			// References may point inside a definition
+			if _, ok := swagger.Definitions[name]; ok {
+				continue
+			}
+			raw, ok := sections["definitions"][name]
+			if !ok {
+				continue
+			}
+			schemaRef := &openapi3.SchemaRef{}
+			if err := json.Unmarshal(raw, schemaRef); err != nil {
+				return nil, fmt.Errorf("Error while unmarshalling definition '%s': %v", name, err)
+			}
+			if swagger.Definitions == nil {
+				swagger.Definitions = make(map[string]*openapi3.SchemaRef)
+			}
+			swagger.Definitions[name] = schemaRef
+			walkSchemaRefRefs("", schemaRef, queue)
+		} else if strings.HasPrefix(ref, parametersPrefix) {
+			name := unescapePointerToken(strings.SplitN(ref[len(parametersPrefix):], "/", 2)[0])
+			if _, ok := swagger.Parameters[name]; ok {
+				continue
+			}
+			raw, ok := sections["parameters"][name]
+			if !ok {
+				continue
+			}
+			parameter := &Parameter{}
+			if err := json.Unmarshal(raw, parameter); err != nil {
+				return nil, fmt.Errorf("Error while unmarshalling parameter '%s': %v", name, err)
+			}
+			if swagger.Parameters == nil {
+				swagger.Parameters = make(map[string]*Parameter)
+			}
+			swagger.Parameters[name] = parameter
+			walkParameterRefs("", parameter, queue)
+		} else if strings.HasPrefix(ref, responsesPrefix) {
+			// Header references point inside a shared response
+			name := unescapePointerToken(strings.SplitN(ref[len(responsesPrefix):], "/", 2)[0])
+			if _, ok := swagger.Responses[name]; ok {
+				continue
+			}
+			raw, ok := sections["responses"][name]
+			if !ok {
+				continue
+			}
+			response := &Response{}
+			if err := json.Unmarshal(raw, response); err != nil {
+				return nil, fmt.Errorf("Error while unmarshalling response '%s': %v", name, err)
+			}
+			if swagger.Responses == nil {
+				swagger.Responses = make(map[string]*Response)
+			}
+			swagger.Responses[name] = response
+			walkResponseRefs("", response, queue)
+		}
+	}
+	return swagger, nil
+}
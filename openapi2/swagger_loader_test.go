@@ -0,0 +1,226 @@
+package openapi2_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/mbilski/kin-openapi/jsoninfo"
+	"github.com/mbilski/kin-openapi/openapi2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadSwaggerDuplicatePathKey(t *testing.T) {
+	spec := []byte(`
+swagger: "2.0"
+info:
+  title: MyAPI
+  version: "0.1"
+paths:
+  /pets:
+    get:
+      responses:
+        200: {description: list}
+  /pets:
+    post:
+      responses:
+        201: {description: created}
+`)
+	loader := openapi2.NewSwaggerLoader()
+	swagger, err := loader.LoadSwaggerFromData(spec)
+	require.NoError(t, err)
+	require.Nil(t, swagger.Paths["/pets"].Get)
+
+	loader.DetectDuplicateKeys = true
+	_, err = loader.LoadSwaggerFromData(spec)
+	require.EqualError(t, err, "Duplicate key '/pets' in '/paths'")
+}
+
+func TestLoadSwaggerDuplicateOperationKeyJSON(t *testing.T) {
+	spec := []byte(`{
+  "info": {"title": "MyAPI", "version": "0.1"},
+  "paths": {
+    "/pets": {
+      "get": {"responses": {"200": {"description": "list"}}},
+      "get": {"responses": {"200": {"description": "copy"}}}
+    }
+  }
+}`)
+	loader := &openapi2.SwaggerLoader{DetectDuplicateKeys: true}
+	_, err := loader.LoadSwaggerFromData(spec)
+	require.EqualError(t, err, "Duplicate key 'get' in '/paths/~1pets' at line 6")
+}
+
+func TestLoadSwaggerFromFile(t *testing.T) {
+	loader := &openapi2.SwaggerLoader{DetectDuplicateKeys: true}
+	swagger, err := loader.LoadSwaggerFromFile("testdata/swagger.json")
+	require.NoError(t, err)
+	require.Equal(t, "Swagger Petstore", swagger.Info.Title)
+}
+
+// countingReader counts the bytes read from an endless document.
+type countingReader struct {
+	read int64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	for i := range p {
+		p[i] = ' '
+	}
+	r.read += int64(len(p))
+	return len(p), nil
+}
+
+func TestLoadSwaggerFromReaderBudget(t *testing.T) {
+	spec := `{"info": {"title": "MyAPI", "version": "0.1"}}`
+	loader := &openapi2.SwaggerLoader{MaxBytes: 1 << 20}
+	swagger, err := loader.LoadSwaggerFromReader(context.Background(), strings.NewReader(spec))
+	require.NoError(t, err)
+	require.Equal(t, "MyAPI", swagger.Info.Title)
+
+	large := io.MultiReader(strings.NewReader(spec), bytes.NewReader(bytes.Repeat([]byte(" "), 10<<20)))
+	_, err = loader.LoadSwaggerFromReader(context.Background(), large)
+	require.EqualError(t, err, "Document exceeds the limit of 1048576 bytes")
+	require.IsType(t, &openapi2.DocumentTooLargeError{}, err)
+
+	r := &countingReader{}
+	_, err = loader.LoadSwaggerFromReader(context.Background(), r)
+	require.Error(t, err)
+	require.True(t, r.read <= 2<<20, "read %d bytes", r.read)
+
+	_, err = loader.LoadSwaggerFromData(bytes.Repeat([]byte(" "), 2<<20))
+	require.EqualError(t, err, "Document exceeds the limit of 1048576 bytes")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	loader = openapi2.NewSwaggerLoader()
+	_, err = loader.LoadSwaggerFromReader(ctx, &countingReader{})
+	require.Equal(t, context.Canceled, err)
+}
+
+func TestLoadSwaggerPaths(t *testing.T) {
+	spec := []byte(`
+info: {title: MyAPI, version: "0.1"}
+paths:
+  /pets:
+    get:
+      parameters:
+        - $ref: '#/parameters/limit'
+      responses:
+        200:
+          description: list
+          schema: {type: array, items: {$ref: '#/definitions/Pet'}}
+        default: {$ref: '#/responses/Error'}
+  /users:
+    get:
+      responses:
+        200:
+          description: list
+          schema: {$ref: '#/definitions/User'}
+parameters:
+  limit: {name: limit, in: query, type: integer}
+  offset: {name: offset, in: query, type: integer}
+responses:
+  Error:
+    description: error
+    schema: {$ref: '#/definitions/Error'}
+definitions:
+  Pet:
+    properties:
+      owner: {$ref: '#/definitions/Owner'}
+  Owner:
+    properties:
+      name: {type: string}
+  User:
+    properties:
+      name: {type: string}
+  Error:
+    properties:
+      message: {type: string}
+`)
+	swagger, err := openapi2.LoadSwaggerPaths(spec, func(path string) bool {
+		return path == "/pets"
+	})
+	require.NoError(t, err)
+	require.Equal(t, "MyAPI", swagger.Info.Title)
+	require.Len(t, swagger.Paths, 1)
+	require.NotNil(t, swagger.Paths["/pets"].Get)
+	require.Len(t, swagger.Parameters, 1)
+	require.NotNil(t, swagger.Parameters["limit"])
+	require.Len(t, swagger.Responses, 1)
+	require.NotNil(t, swagger.Responses["Error"])
+	require.Len(t, swagger.Definitions, 3)
+	for _, name := range []string{"Pet", "Owner", "Error"} {
+		require.Contains(t, swagger.Definitions, name)
+	}
+
+	swagger, err = openapi2.LoadSwaggerPaths(spec, func(string) bool { return false })
+	require.NoError(t, err)
+	require.Nil(t, swagger.Paths)
+	require.Nil(t, swagger.Definitions)
+}
+
+func TestLoadSwaggerPathsEscapedRefs(t *testing.T) {
+	spec := []byte(`
+{
+  "info": {"title": "MyAPI", "version": "0.1"},
+  "paths": {
+    "/pets": {
+      "get": {
+        "parameters": [{"$ref": "#/parameters/page~1size"}],
+        "responses": {
+          "200": {
+            "description": "list",
+            "schema": {
+              "type": "object",
+              "properties": {
+                "owner": {"$ref": "#/definitions/Foo~1Bar"},
+                "tag": {"$ref": "#/definitions/Pet/properties/tag"}
+              }
+            }
+          }
+        }
+      }
+    }
+  },
+  "parameters": {
+    "page/size": {"name": "size", "in": "query", "type": "integer"}
+  },
+  "definitions": {
+    "Foo/Bar": {"type": "string"},
+    "Pet": {"properties": {"tag": {"type": "string"}}},
+    "User": {"properties": {"name": {"type": "string"}}}
+  }
+}`)
+	swagger, err := openapi2.LoadSwaggerPaths(spec, func(string) bool { return true })
+	require.NoError(t, err)
+	require.Len(t, swagger.Parameters, 1)
+	require.NotNil(t, swagger.Parameters["page/size"])
+	require.Len(t, swagger.Definitions, 2)
+	require.NotNil(t, swagger.Definitions["Foo/Bar"])
+	require.NotNil(t, swagger.Definitions["Pet"])
+}
+
+func TestLoadSwaggerDecoderOptions(t *testing.T) {
+	spec := []byte(`{
+  "swagger": "2.0",
+  "info": {"version": "0.1", "title": "MyAPI"},
+  "paths": {"/pets": {"get": {"responses": {"200": {"description": "OK"}}, "summary": "List pets"}}}
+}`)
+	swagger, err := openapi2.NewSwaggerLoader().LoadSwaggerFromData(spec)
+	require.NoError(t, err)
+	require.Nil(t, swagger.Info.FieldOrder)
+
+	loader := &openapi2.SwaggerLoader{DecoderOptions: jsoninfo.DecoderOptions{PreserveFieldOrder: true}}
+	swagger, err = loader.LoadSwaggerFromData(spec)
+	require.NoError(t, err)
+	require.Equal(t, []string{"swagger", "info", "paths"}, swagger.FieldOrder)
+	require.Equal(t, []string{"version", "title"}, swagger.Info.FieldOrder)
+	require.Equal(t, []string{"responses", "summary"}, swagger.Paths["/pets"].Get.FieldOrder)
+
+	_, err = loader.LoadSwaggerFromData([]byte(`{"swagger": "2.0", "paths": []}`))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "error unmarshaling JSON")
+}
@@ -0,0 +1,476 @@
+package openapi2
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"mime"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/mbilski/kin-openapi/openapi3"
+)
+
+// MultiError holds all the problems found while validating a document.
+type MultiError []error
+
+func (errs MultiError) Error() string {
+	messages := make([]string, 0, len(errs))
+	for _, err := range errs {
+		messages = append(messages, err.Error())
+	}
+	return strings.Join(messages, " | ")
+}
+
+// ValidationOptions configures Swagger.ValidateWithOptions.
+type ValidationOptions struct {
+	// PatternOptions configures how parameter patterns are compiled.
+	PatternOptions PatternOptions
+	// RequireDescriptions warns about operations without a summary or description,
+	// and parameters and responses without a description. See Swagger.ValidateIssues.
+	RequireDescriptions bool
+	// CheckPatternFormats warns about string parameters, and parameter items, whose pattern
+	// can't match their "date", "date-time" or "uuid" format. See Swagger.ValidateIssues.
+	CheckPatternFormats bool
+	// RequireProduces warns about operations with response schemas that produce no media type,
+	// of their own or of the document, leaving clients to assume JSON. See Swagger.ValidateIssues.
+	RequireProduces bool
+	// CheckRequiredDefaults warns about required parameters with a default, which never applies
+	// since the client must send the parameter, or allowing an empty value, which may then be missing.
+	// See Swagger.ValidateIssues.
+	CheckRequiredDefaults bool
+	// RequireDefaultResponse reports operations without a "default" response.
+	RequireDefaultResponse bool
+	// RequireErrorResponses reports operations without a 4xx response.
+	RequireErrorResponses bool
+	// ResponseCoverageSeverity is the severity of the problems reported by RequireDefaultResponse
+	// and RequireErrorResponses. They are warnings, reported by Swagger.ValidateIssues, unless
+	// it is SeverityError, which makes them errors of ValidateWithOptions.
+	ResponseCoverageSeverity Severity
+	// StrictInteger rejects request values of "integer" parameters and body properties
+	// written with a fraction or an exponent, such as 42.0, which are accepted otherwise.
+	// A value that isn't a whole number, such as 42.5, is always rejected.
+	StrictInteger bool
+	// AllNDJSONLines makes ValidateNDJSONResponseWithOptions report every invalid line
+	// of the body as a MultiError of *LineError, instead of the first one.
+	AllNDJSONLines bool
+}
+
+// Validate checks that the document conforms to the OpenAPI 2 specification.
+// It returns a MultiError of *LintError holding every problem found, or nil.
+func (swagger *Swagger) Validate(c context.Context) error {
+	return swagger.ValidateWithOptions(c, ValidationOptions{})
+}
+
+// ValidateWithOptions is like Validate, configured by the given options.
+func (swagger *Swagger) ValidateWithOptions(c context.Context, opts ValidationOptions) error {
+	v := &validator{c: c, opts: opts}
+	if errs := swagger.Lint(v.rules()...); len(errs) > 0 {
+		return MultiError(errs)
+	}
+	return nil
+}
+
+// validator holds the state shared by the rules run by Swagger.Validate.
+type validator struct {
+	c    context.Context
+	opts ValidationOptions
+}
+
+// namedRule is a Rule run by Swagger.Validate, with the name reported in Issue.Rule.
+type namedRule struct {
+	name string
+	rule Rule
+}
+
+func (v *validator) namedRules() []namedRule {
+	rules := []namedRule{
+		{"info", v.validateInfo},
+		{"servers", v.validateServers},
+		{"ref-targets", v.validateRefTargets},
+		{"operations", v.validateOperations},
+		{"operation-parameters", v.validateOperationParameters},
+		{"media-types", v.validateMediaTypes},
+		{"parameters", v.validateParameters},
+		{"responses", v.validateResponses},
+		{"schemas", v.validateSchemas},
+		{"security-definitions", v.validateSecurityDefinitions},
+		{"security-requirements", v.validateSecurityRequirements},
+	}
+	if v.opts.ResponseCoverageSeverity == SeverityError {
+		rules = append(rules, v.responseCoverageRules()...)
+	}
+	return rules
+}
+
+func (v *validator) rules() []Rule {
+	namedRules := v.namedRules()
+	rules := make([]Rule, 0, len(namedRules))
+	for _, namedRule := range namedRules {
+		rules = append(rules, namedRule.rule)
+	}
+	return rules
+}
+
+func (v *validator) validateInfo(swagger *Swagger) []error {
+	if err := swagger.Info.Validate(v.c); err != nil {
+		return []error{&LintError{Pointer: "/info", Reason: err.Error()}}
+	}
+	return nil
+}
+
+// validateServers checks the host and the basePath that ServerURLs builds the base URLs from.
+func (v *validator) validateServers(swagger *Swagger) []error {
+	var errs []error
+	if err := validateHost(swagger.Host); err != nil {
+		errs = append(errs, &LintError{Pointer: "/host", Reason: err.Error()})
+	}
+	if err := validateBasePath(swagger.BasePath); err != nil {
+		errs = append(errs, &LintError{Pointer: "/basePath", Reason: err.Error()})
+	}
+	return errs
+}
+
+func (v *validator) validateOperations(swagger *Swagger) []error {
+	var errs []error
+	swagger.walkOperations(func(path string, method string, operation *Operation) {
+		if len(operation.Responses) == 0 {
+			errs = append(errs, &LintError{
+				Pointer: operationPointer(path, method) + "/responses",
+				Reason:  "Operation must declare at least one response",
+			})
+		}
+	})
+	return errs
+}
+
+// validateOperationParameters checks that an operation takes its input either from one "body"
+// parameter or from "formData" parameters, including those of its path item, and that
+// it consumes a form media type when it takes form data.
+// Parameters that can't be resolved are skipped.
+func (v *validator) validateOperationParameters(swagger *Swagger) []error {
+	var errs []error
+	swagger.walkOperations(func(path string, method string, operation *Operation) {
+		pointer := operationPointer(path, method)
+		parameters, _ := swagger.effectiveParameters(swagger.Paths[path], operation)
+		var bodies, forms, files int
+		for _, parameter := range parameters {
+			switch parameter.In {
+			case "body":
+				bodies++
+			case "formData":
+				forms++
+				if parameter.Type == "file" {
+					files++
+				}
+			}
+		}
+		if bodies > 1 {
+			errs = append(errs, &LintError{Pointer: pointer + "/parameters", Reason: "Operation must not have more than one body parameter"})
+		}
+		if bodies > 0 && forms > 0 {
+			errs = append(errs, &LintError{Pointer: pointer + "/parameters", Reason: "Operation must not have both body and formData parameters"})
+		}
+		if forms == 0 {
+			return
+		}
+		consumes := operation.Consumes
+		if len(consumes) == 0 {
+			consumes = swagger.Consumes
+		}
+		if files > 0 {
+			if !consumesMediaType(consumes, "multipart/form-data") {
+				errs = append(errs, &LintError{
+					Pointer: pointer + "/consumes",
+					Reason:  "Operation with file parameters must consume 'multipart/form-data'",
+				})
+			}
+		} else if !consumesMediaType(consumes, "application/x-www-form-urlencoded") && !consumesMediaType(consumes, "multipart/form-data") {
+			errs = append(errs, &LintError{
+				Pointer: pointer + "/consumes",
+				Reason:  "Operation with formData parameters must consume 'application/x-www-form-urlencoded' or 'multipart/form-data'",
+			})
+		}
+	})
+	return errs
+}
+
+// validateMediaTypes validates the document and operation "consumes" and "produces" lists.
+func (v *validator) validateMediaTypes(swagger *Swagger) []error {
+	var errs []error
+	check := func(pointer string, mediaTypes []string) {
+		for i, mediaType := range mediaTypes {
+			if err := validateMediaType(mediaType); err != nil {
+				errs = append(errs, &LintError{Pointer: pointer + "/" + strconv.Itoa(i), Reason: err.Error()})
+			}
+		}
+	}
+	check("/consumes", swagger.Consumes)
+	check("/produces", swagger.Produces)
+	swagger.walkOperations(func(path string, method string, operation *Operation) {
+		pointer := operationPointer(path, method)
+		check(pointer+"/consumes", operation.Consumes)
+		check(pointer+"/produces", operation.Produces)
+	})
+	return errs
+}
+
+func validateMediaType(mediaType string) error {
+	parsed, _, err := mime.ParseMediaType(mediaType)
+	if err == nil && !strings.Contains(parsed, "/") {
+		// A bare token such as "json" parses, but isn't a media type
+		err = errors.New("missing subtype")
+	}
+	if err != nil {
+		return fmt.Errorf("Invalid media type '%s': %v", mediaType, strings.TrimPrefix(err.Error(), "mime: "))
+	}
+	return nil
+}
+
+func (v *validator) validateParameters(swagger *Swagger) []error {
+	var errs []error
+	swagger.walkParameters(func(pointer string, parameter *Parameter) {
+		if parameter.Ref != "" {
+			return
+		}
+		for _, err := range v.validateParameter(parameter) {
+			errs = append(errs, &LintError{Pointer: pointer, Reason: err.Error()})
+		}
+	})
+	return errs
+}
+
+func (v *validator) validateParameter(parameter *Parameter) []error {
+	var errs []error
+	if parameter.Name == "" {
+		errs = append(errs, fmt.Errorf("Parameter must have a name"))
+	}
+	switch parameter.In {
+	case "body":
+		if parameter.Schema == nil {
+			errs = append(errs, fmt.Errorf("Body parameter '%s' must have a schema", parameter.Name))
+		}
+		return errs
+	case "path":
+		if !parameter.Required {
+			errs = append(errs, fmt.Errorf("Path parameter '%s' must be required", parameter.Name))
+		}
+		if parameter.Default != nil {
+			errs = append(errs, fmt.Errorf("Path parameter '%s' must not have a default", parameter.Name))
+		}
+	case "query", "header", "formData":
+	default:
+		errs = append(errs, fmt.Errorf("Parameter '%s' has unsupported location '%s'", parameter.Name, parameter.In))
+		return errs
+	}
+	switch parameter.Type {
+	case "string", "number", "integer", "boolean":
+	case "array":
+		if parameter.Items == nil {
+			errs = append(errs, fmt.Errorf("Array parameter '%s' must have items", parameter.Name))
+		} else if items := parameter.Items.Value; items != nil {
+			errs = append(errs, v.validateItems(parameter.Name, items)...)
+		}
+	case "file":
+		if parameter.In != "formData" {
+			errs = append(errs, fmt.Errorf("File parameter '%s' must be in formData", parameter.Name))
+		}
+	case "":
+		errs = append(errs, fmt.Errorf("Parameter '%s' must have a type", parameter.Name))
+	default:
+		errs = append(errs, fmt.Errorf("Parameter '%s' has unsupported type '%s'", parameter.Name, parameter.Type))
+	}
+	if pattern := parameter.Pattern; pattern != "" {
+		if _, err := CompilePattern(pattern, v.opts.PatternOptions); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if parameter.Enum != nil && len(parameter.Enum) == 0 {
+		// A present but empty enum accepts no value
+		errs = append(errs, fmt.Errorf("Parameter '%s' has an empty enum", parameter.Name))
+	}
+	if _, _, err := parameter.enumNames(); err != nil {
+		errs = append(errs, fmt.Errorf("Parameter '%s' has invalid enum names: %v", parameter.Name, err))
+	}
+	// A default is only checked against a well-formed parameter,
+	// so that a broken pattern or type isn't reported twice.
+	if parameter.Default != nil && len(errs) == 0 {
+		if err := parameter.validateValue(parameter.Default, v.opts.PatternOptions); err != nil {
+			errs = append(errs, fmt.Errorf("Default value of parameter '%s' is invalid: %v", parameter.Name, err))
+		}
+	}
+	return errs
+}
+
+// validateItems validates the "items" of an array parameter, and those of nested arrays.
+func (v *validator) validateItems(name string, items *openapi3.Schema) []error {
+	var errs []error
+	switch items.Type {
+	case "string", "number", "integer", "boolean":
+	case "array":
+		if items.Items == nil {
+			errs = append(errs, fmt.Errorf("Nested array items of parameter '%s' must have items", name))
+		} else if nested := items.Items.Value; nested != nil {
+			errs = append(errs, v.validateItems(name, nested)...)
+		}
+	case "":
+		errs = append(errs, fmt.Errorf("Items of parameter '%s' must have a type", name))
+	default:
+		errs = append(errs, fmt.Errorf("Items of parameter '%s' have unsupported type '%s'", name, items.Type))
+	}
+	if pattern := items.Pattern; pattern != "" {
+		if _, err := CompilePattern(pattern, v.opts.PatternOptions); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if items.Enum != nil && len(items.Enum) == 0 {
+		errs = append(errs, fmt.Errorf("Items of parameter '%s' have an empty enum", name))
+	}
+	if len(errs) == 0 {
+		for _, value := range items.Enum {
+			if err := schemaParameter(items).validateValue(value, v.opts.PatternOptions); err != nil {
+				errs = append(errs, fmt.Errorf("Enum value of items of parameter '%s' is invalid: %v", name, err))
+			}
+		}
+	}
+	return errs
+}
+
+func (v *validator) validateResponses(swagger *Swagger) []error {
+	var errs []error
+	swagger.walkResponses(func(pointer string, response *Response) {
+		if response.Ref != "" {
+			return
+		}
+		for _, name := range sortedHeaderNames(response.Headers) {
+			header := response.Headers[name]
+			if header == nil {
+				continue
+			}
+			headerPointer := pointer + "/headers/" + escapePointerToken(name)
+			if err := v.validateHeader(swagger, name, header); err != nil {
+				errs = append(errs, &LintError{Pointer: headerPointer, Reason: err.Error()})
+			}
+		}
+	})
+	return errs
+}
+
+// validateSchemas validates the definitions, body parameter schemas and response schemas,
+// and the schemas nested in them.
+func (v *validator) validateSchemas(swagger *Swagger) []error {
+	var errs []error
+	check := func(pointer string, schemaRef *openapi3.SchemaRef) {
+		walkSchemaRef(pointer, schemaRef, func(pointer string, schemaRef *openapi3.SchemaRef) {
+			if schemaRef.Ref != "" || schemaRef.Value == nil {
+				return
+			}
+			if enum := schemaRef.Value.Enum; enum != nil && len(enum) == 0 {
+				errs = append(errs, &LintError{Pointer: pointer, Reason: "Schema has an empty enum"})
+			}
+		})
+	}
+	for _, name := range sortedSchemaNames(swagger.Definitions) {
+		check("/definitions/"+escapePointerToken(name), swagger.Definitions[name])
+	}
+	swagger.walkParameters(func(pointer string, parameter *Parameter) {
+		if parameter.Ref == "" {
+			check(pointer+"/schema", parameter.Schema)
+		}
+	})
+	swagger.walkResponses(func(pointer string, response *Response) {
+		if response.Ref == "" {
+			check(pointer+"/schema", response.Schema)
+		}
+	})
+	return errs
+}
+
+// validateHeader validates the header a response header resolves to.
+func (v *validator) validateHeader(swagger *Swagger, name string, header *Header) error {
+	resolved, err := header.Resolve(swagger)
+	if err != nil {
+		return err
+	}
+	switch resolved.Type {
+	case "string", "number", "integer", "boolean", "array":
+		return nil
+	case "":
+		return fmt.Errorf("Header '%s' must have a type", name)
+	default:
+		return fmt.Errorf("Header '%s' has unsupported type '%s'", name, resolved.Type)
+	}
+}
+
+func (v *validator) validateSecurityDefinitions(swagger *Swagger) []error {
+	var errs []error
+	for _, name := range sortedSecuritySchemeNames(swagger.SecurityDefinitions) {
+		if securityScheme := swagger.SecurityDefinitions[name]; securityScheme != nil {
+			err := securityScheme.Validate()
+			if securityScheme.Ref != "" {
+				_, err = securityScheme.Resolve(swagger)
+			}
+			if err != nil {
+				errs = append(errs, &LintError{
+					Pointer: "/securityDefinitions/" + escapePointerToken(name),
+					Reason:  err.Error(),
+				})
+			}
+		}
+	}
+	return errs
+}
+
+// validateSecurityRequirements checks that document and operation security requirements
+// name declared schemes, and only list scopes declared by OAuth2 schemes.
+func (v *validator) validateSecurityRequirements(swagger *Swagger) []error {
+	var errs []error
+	check := func(pointer string, requirements SecurityRequirements) {
+		for i, requirement := range requirements {
+			names := make([]string, 0, len(requirement))
+			for name := range requirement {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			for _, name := range names {
+				requirementPointer := pointer + "/" + strconv.Itoa(i) + "/" + escapePointerToken(name)
+				for _, err := range validateSecurityRequirement(swagger, name, requirement[name]) {
+					errs = append(errs, &LintError{Pointer: requirementPointer, Reason: err.Error()})
+				}
+			}
+		}
+	}
+	check("/security", swagger.Security)
+	swagger.walkOperations(func(path string, method string, operation *Operation) {
+		if operation.Security != nil {
+			check(operationPointer(path, method)+"/security", *operation.Security)
+		}
+	})
+	return errs
+}
+
+func validateSecurityRequirement(swagger *Swagger, name string, scopes []string) []error {
+	securityScheme := swagger.SecurityDefinitions[name]
+	if securityScheme == nil {
+		return []error{fmt.Errorf("Security scheme '%s' is not defined", name)}
+	}
+	securityScheme, err := securityScheme.Resolve(swagger)
+	if err != nil {
+		// Reported under "securityDefinitions"
+		return nil
+	}
+	if !securityScheme.IsOAuth2() {
+		if len(scopes) > 0 {
+			return []error{fmt.Errorf("Security scheme '%s' of type '%s' must have an empty scope list", name, securityScheme.Type)}
+		}
+		return nil
+	}
+	var errs []error
+	for _, scope := range scopes {
+		if _, ok := securityScheme.Scopes[scope]; !ok {
+			errs = append(errs, fmt.Errorf("Scope '%s' is not declared by security scheme '%s'", scope, name))
+		}
+	}
+	return errs
+}
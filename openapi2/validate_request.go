@@ -0,0 +1,566 @@
+package openapi2
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/mbilski/kin-openapi/openapi3"
+	"github.com/mbilski/kin-openapi/pathpattern"
+)
+
+// RouteError reports a request that matches no operation of the document.
+type RouteError struct {
+	Method string
+	Path   string
+	Reason string
+}
+
+func (err *RouteError) Error() string {
+	return fmt.Sprintf("%s %s: %s", err.Method, err.Path, err.Reason)
+}
+
+// ValidateRequest routes a request to its operation and validates it.
+//
+// The request path, without the document base path, is matched against the path templates,
+// and the path parameters are URL-decoded.
+// A request matching no operation fails with a *RouteError.
+// Otherwise the path, query and header parameters are validated like Swagger.ValidateParameters,
+// and a JSON body is decoded and validated against the schema of the "body" parameter,
+// reporting which of its "allOf" schemas fail, and how many of its "oneOf" schemas match.
+// An "application/x-www-form-urlencoded" body is decoded and its fields are validated
+// like query parameters against the "formData" parameters; multipart bodies aren't validated.
+// The body content type must be one of the operation "consumes", when it declares any.
+// The validation failures are returned as a MultiError of *RequestError.
+//
+// Local schema references are resolved on a copy of the schemas, leaving the document unchanged,
+// so that a document can validate concurrent requests.
+// Each request builds the tree matching the path templates and the copy of its body schema,
+// unless Swagger.ResolveParameters cached them: call it once the document is loaded.
+func ValidateRequest(swagger *Swagger, req *http.Request) error {
+	return ValidateRequestWithOptions(swagger, req, ValidationOptions{})
+}
+
+// ValidateRequestWithOptions is like ValidateRequest, configured by the given options.
+func ValidateRequestWithOptions(swagger *Swagger, req *http.Request, opts ValidationOptions) error {
+	pathItem, operation, pathParams, err := swagger.findRoute(req)
+	if err != nil {
+		return err
+	}
+	var errs MultiError
+	if err := swagger.ValidateParametersWithOptions(req, pathItem, operation, pathParams, opts); err != nil {
+		multiErr, ok := err.(MultiError)
+		if !ok {
+			return err
+		}
+		errs = append(errs, multiErr...)
+	}
+	if err := swagger.validateRequestBody(req, pathItem, operation, opts); err != nil {
+		if _, ok := err.(*RequestError); !ok {
+			return err
+		}
+		errs = append(errs, err)
+	}
+	if err := swagger.validateFormData(req, pathItem, operation, opts); err != nil {
+		multiErr, ok := err.(MultiError)
+		if !ok {
+			return err
+		}
+		errs = append(errs, multiErr...)
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// findRoute returns the path item and operation a request is routed to,
+// with the values of the path parameters.
+func (swagger *Swagger) findRoute(req *http.Request) (*PathItem, *Operation, map[string]string, error) {
+	method := strings.ToUpper(req.Method)
+	routeErr := func(reason string) error {
+		return &RouteError{Method: method, Path: req.URL.Path, Reason: reason}
+	}
+	// Matching the escaped path keeps an encoded "/" inside its segment
+	remaining := req.URL.EscapedPath()
+	if basePath := normalizeBasePath(swagger.BasePath); basePath != "" {
+		if remaining != basePath && !strings.HasPrefix(remaining, basePath+"/") {
+			return nil, nil, nil, routeErr("Path was not found")
+		}
+		remaining = remaining[len(basePath):]
+	}
+	if remaining == "" {
+		remaining = "/"
+	}
+	root, err := swagger.routeTree()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	node, values := root.Match(remaining)
+	if node == nil {
+		return nil, nil, nil, routeErr("Path was not found")
+	}
+	pathItem := swagger.Paths[node.Value.(string)]
+	var operation *Operation
+	for _, supported := range operationMethods {
+		if method == supported {
+			operation = pathItem.GetOperation(method)
+		}
+	}
+	if operation == nil {
+		return nil, nil, nil, routeErr("Path doesn't support the HTTP method")
+	}
+	pathParams := make(map[string]string, len(values))
+	for i, value := range values {
+		if unescaped, err := url.PathUnescape(value); err == nil {
+			value = unescaped
+		}
+		pathParams[strings.TrimSuffix(node.VariableNames[i], "*")] = value
+	}
+	return pathItem, operation, pathParams, nil
+}
+
+// routeTree returns the tree matching the paths of the document cached by ResolveParameters,
+// or else builds one.
+func (swagger *Swagger) routeTree() (*pathpattern.Node, error) {
+	if cache := swagger.validation; cache != nil {
+		return cache.routes, nil
+	}
+	return swagger.buildRouteTree()
+}
+
+// buildRouteTree returns a new tree matching the paths of the document.
+func (swagger *Swagger) buildRouteTree() (*pathpattern.Node, error) {
+	root := &pathpattern.Node{}
+	for _, path := range swagger.sortedPaths() {
+		if swagger.Paths[path] == nil {
+			continue
+		}
+		if err := root.Add(path, path, nil); err != nil {
+			return nil, err
+		}
+	}
+	return root, nil
+}
+
+// validateRequestBody validates the body of a request against the "body" parameter of its operation,
+// which may be inherited from its path item.
+// Only JSON bodies are decoded and checked against the schema.
+// The body is read, and replaced so that it can be read again.
+func (swagger *Swagger) validateRequestBody(req *http.Request, pathItem *PathItem, operation *Operation, opts ValidationOptions) error {
+	parameters, err := swagger.effectiveParameters(pathItem, operation)
+	if err != nil {
+		return err
+	}
+	var body *Parameter
+	for _, parameter := range parameters {
+		if parameter.In == "body" {
+			body = parameter
+		}
+	}
+	if body == nil {
+		return nil
+	}
+	data, err := readRequestBody(req)
+	if err != nil {
+		return &RequestError{Parameter: body, Reason: "Reading failed", Err: err}
+	}
+	if len(data) == 0 {
+		if body.Required {
+			return &RequestError{Parameter: body, Reason: "Value is required"}
+		}
+		return nil
+	}
+	mediaType, reqErr := swagger.requestMediaType(req, operation, "application/json")
+	if reqErr != nil {
+		reqErr.Parameter = body
+		return reqErr
+	}
+	if !isJSONMediaType(mediaType) || body.Schema == nil {
+		return nil
+	}
+
+	value, err := decodeJSONValue(data)
+	if err != nil {
+		return &RequestError{Parameter: body, Reason: "Value is not valid JSON", Err: err}
+	}
+	schemaRef, err := swagger.validationSchema(body.Schema)
+	if err != nil {
+		return err
+	}
+	if schemaRef.Value == nil {
+		return nil
+	}
+	if opts.StrictInteger {
+		if err := checkStrictIntegers(schemaRef.Value, value); err != nil {
+			return &RequestError{Parameter: body, Err: err}
+		}
+	}
+	if err := visitJSONSchema(schemaRef.Value, jsonNumbersToFloats(value)); err != nil {
+		return &RequestError{Parameter: body, Err: err}
+	}
+	return nil
+}
+
+// decodeJSONValue decodes data holding a single JSON value.
+// Numbers are decoded as written, so that strict integers can be told apart.
+func decodeJSONValue(data []byte) (interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return nil, errors.New("unexpected data after the value")
+	}
+	return value, nil
+}
+
+// visitJSONSchema validates a decoded JSON value against a schema, like openapi3.Schema.VisitJSON,
+// but reports which "allOf" schemas the value doesn't match, how many "anyOf" schemas
+// there are when it matches none, and how many "oneOf" schemas it matches when it doesn't
+// match exactly one. The schemas of allOf, anyOf and oneOf are validated the same way,
+// while those nested in properties or items are validated by openapi3.Schema.VisitJSON.
+// References must be resolved, like resolvedSchemaCopy does.
+func visitJSONSchema(schema *openapi3.Schema, value interface{}) error {
+	return visitJSONSchemaVisiting(schema, value, make(map[*openapi3.Schema]struct{}))
+}
+
+// visitJSONSchemaVisiting is visitJSONSchema, with the schemas being validated,
+// which are validated by openapi3.Schema.VisitJSON when they are met again.
+func visitJSONSchemaVisiting(schema *openapi3.Schema, value interface{}, visiting map[*openapi3.Schema]struct{}) error {
+	if _, ok := visiting[schema]; ok || (len(schema.AllOf) == 0 && len(schema.AnyOf) == 0 && len(schema.OneOf) == 0) {
+		return schema.VisitJSON(value)
+	}
+	visiting[schema] = struct{}{}
+	defer delete(visiting, schema)
+
+	var failed []string
+	var firstErr error
+	for i, member := range schema.AllOf {
+		if err := visitJSONSchemaMember(member, value, visiting); err != nil {
+			failed = append(failed, strconv.Itoa(i))
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+	switch len(failed) {
+	case 0:
+	case 1:
+		return fmt.Errorf("Value doesn't match allOf schema %s: %v", failed[0], firstErr)
+	default:
+		return fmt.Errorf("Value doesn't match allOf schemas %s: %v", strings.Join(failed, ", "), firstErr)
+	}
+	if len(schema.AnyOf) > 0 {
+		matched := false
+		for _, member := range schema.AnyOf {
+			if visitJSONSchemaMember(member, value, visiting) == nil {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return fmt.Errorf("Value doesn't match any of the %d anyOf schemas", len(schema.AnyOf))
+		}
+	}
+	if len(schema.OneOf) > 0 {
+		matches := 0
+		for _, member := range schema.OneOf {
+			if visitJSONSchemaMember(member, value, visiting) == nil {
+				matches++
+			}
+		}
+		if matches != 1 {
+			return fmt.Errorf("Value matches %d of the %d oneOf schemas, but must match exactly one", matches, len(schema.OneOf))
+		}
+	}
+	// The other constraints of the schema
+	rest := *schema
+	rest.AllOf, rest.AnyOf, rest.OneOf = nil, nil, nil
+	return rest.VisitJSON(value)
+}
+
+func visitJSONSchemaMember(member *openapi3.SchemaRef, value interface{}, visiting map[*openapi3.Schema]struct{}) error {
+	if member == nil || member.Value == nil {
+		ref := ""
+		if member != nil {
+			ref = member.Ref
+		}
+		return failedToResolveRef(ref)
+	}
+	return visitJSONSchemaVisiting(member.Value, value, visiting)
+}
+
+// validateFormData validates the "formData" parameters of an operation, including those
+// inherited from its path item, against an "application/x-www-form-urlencoded" body.
+// It returns a MultiError of *RequestError, or nil.
+func (swagger *Swagger) validateFormData(req *http.Request, pathItem *PathItem, operation *Operation, opts ValidationOptions) error {
+	parameters, err := swagger.effectiveParameters(pathItem, operation)
+	if err != nil {
+		return err
+	}
+	var fields Parameters
+	for _, parameter := range parameters {
+		if parameter.In == "formData" {
+			fields = append(fields, parameter)
+		}
+	}
+	if len(fields) == 0 {
+		return nil
+	}
+	data, err := readRequestBody(req)
+	if err != nil {
+		return MultiError{&RequestError{Reason: "Reading failed", Err: err}}
+	}
+	var form url.Values
+	if len(data) > 0 {
+		mediaType, reqErr := swagger.requestMediaType(req, operation, "application/x-www-form-urlencoded")
+		if reqErr != nil {
+			return MultiError{reqErr}
+		}
+		if mediaType != "application/x-www-form-urlencoded" {
+			return nil
+		}
+		if form, err = url.ParseQuery(string(data)); err != nil {
+			return MultiError{&RequestError{Reason: "Form data is invalid", Err: err}}
+		}
+	}
+	var errs MultiError
+	for _, parameter := range fields {
+		if err := parameter.validateRequestValues(form[parameter.Name], opts); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// readRequestBody reads the body of a request, and replaces it so that it can be read again.
+func readRequestBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+	data, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		return nil, err
+	}
+	req.Body.Close()
+	req.Body = ioutil.NopCloser(bytes.NewReader(data))
+	return data, nil
+}
+
+// requestMediaType returns the media type of a request body, or defaultMediaType without a Content-Type.
+// It must be one of the operation "consumes", or of the document ones, when any are declared.
+func (swagger *Swagger) requestMediaType(req *http.Request, operation *Operation, defaultMediaType string) (string, *RequestError) {
+	consumes := operation.Consumes
+	if len(consumes) == 0 {
+		consumes = swagger.Consumes
+	}
+	contentType := req.Header.Get("Content-Type")
+	mediaType := defaultMediaType
+	if contentType != "" {
+		parsed, _, err := mime.ParseMediaType(contentType)
+		if err != nil {
+			return "", &RequestError{Reason: fmt.Sprintf("Invalid content type '%s'", contentType)}
+		}
+		mediaType = parsed
+	}
+	if len(consumes) > 0 && !consumesMediaType(consumes, mediaType) {
+		return "", &RequestError{Reason: fmt.Sprintf("Content type '%s' is not consumed by the operation", mediaType)}
+	}
+	return mediaType, nil
+}
+
+func consumesMediaType(consumes []string, mediaType string) bool {
+	for _, item := range consumes {
+		if parsed, _, err := mime.ParseMediaType(item); err == nil && parsed == mediaType {
+			return true
+		}
+	}
+	return false
+}
+
+// isJSONMediaType reports whether a parsed media type is JSON, such as "application/json"
+// or "application/merge-patch+json".
+func isJSONMediaType(mediaType string) bool {
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+// checkStrictIntegers rejects the numbers written with a fraction or an exponent
+// where the schema expects an integer.
+// It follows the properties, additional properties, items and allOf of the schema.
+func checkStrictIntegers(schema *openapi3.Schema, value interface{}) error {
+	if schema == nil {
+		return nil
+	}
+	for _, schemaRef := range schema.AllOf {
+		if schemaRef != nil {
+			if err := checkStrictIntegers(schemaRef.Value, value); err != nil {
+				return err
+			}
+		}
+	}
+	switch value := value.(type) {
+	case json.Number:
+		if schema.Type == "integer" && !isIntegerLiteral(value.String()) {
+			return fmt.Errorf("Value %s must be an integer", value)
+		}
+	case map[string]interface{}:
+		for name, item := range value {
+			itemSchema := schema.AdditionalProperties
+			if property, ok := schema.Properties[name]; ok {
+				itemSchema = property
+			}
+			if itemSchema != nil {
+				if err := checkStrictIntegers(itemSchema.Value, item); err != nil {
+					return fmt.Errorf("Property '%s': %v", name, err)
+				}
+			}
+		}
+	case []interface{}:
+		if schema.Items != nil {
+			for i, item := range value {
+				if err := checkStrictIntegers(schema.Items.Value, item); err != nil {
+					return fmt.Errorf("Item %d: %v", i, err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// jsonNumbersToFloats replaces the json.Number values of a decoded JSON value by float64,
+// as expected by openapi3.Schema.VisitJSON.
+func jsonNumbersToFloats(value interface{}) interface{} {
+	switch value := value.(type) {
+	case json.Number:
+		// Out of range numbers become infinite
+		f, _ := value.Float64()
+		return f
+	case map[string]interface{}:
+		for name, item := range value {
+			value[name] = jsonNumbersToFloats(item)
+		}
+	case []interface{}:
+		for i, item := range value {
+			value[i] = jsonNumbersToFloats(item)
+		}
+	}
+	return value
+}
+
+// resolvedSchemaCopy returns a copy of a schema, and of the schemas under it,
+// with the local references resolved. The document is left unchanged,
+// so that concurrent validations can share it.
+// The returned SchemaRef keeps the $ref of schemaRef and carries the resolved value.
+func (swagger *Swagger) resolvedSchemaCopy(schemaRef *openapi3.SchemaRef) (*openapi3.SchemaRef, error) {
+	copier := &schemaCopier{swagger: swagger, copies: make(map[*openapi3.Schema]*openapi3.Schema)}
+	return copier.copySchemaRef(schemaRef)
+}
+
+// validationSchema returns the copy of a schema cached by ResolveParameters, or else a fresh one
+// from resolvedSchemaCopy.
+func (swagger *Swagger) validationSchema(schemaRef *openapi3.SchemaRef) (*openapi3.SchemaRef, error) {
+	if cache := swagger.validation; cache != nil {
+		if resolved, ok := cache.schemas[schemaRef]; ok {
+			return resolved, nil
+		}
+	}
+	return swagger.resolvedSchemaCopy(schemaRef)
+}
+
+// compiledSchemaCopy is like resolvedSchemaCopy, and also compiles the patterns of the copied schemas,
+// so that concurrent validations against the copy don't write to it.
+func (swagger *Swagger) compiledSchemaCopy(schemaRef *openapi3.SchemaRef) (*openapi3.SchemaRef, error) {
+	copier := &schemaCopier{swagger: swagger, copies: make(map[*openapi3.Schema]*openapi3.Schema)}
+	resolved, err := copier.copySchemaRef(schemaRef)
+	if err != nil {
+		return nil, err
+	}
+	for _, copied := range copier.copies {
+		if err := copied.CompilePatterns(); err != nil {
+			return nil, err
+		}
+	}
+	return resolved, nil
+}
+
+// schemaCopier holds the state of Swagger.resolvedSchemaCopy: the copy of each schema met,
+// which is reused when it is met again, such as through a recursive reference.
+type schemaCopier struct {
+	swagger *Swagger
+	copies  map[*openapi3.Schema]*openapi3.Schema
+}
+
+func (copier *schemaCopier) copySchemaRef(schemaRef *openapi3.SchemaRef) (*openapi3.SchemaRef, error) {
+	if schemaRef == nil {
+		return nil, nil
+	}
+	resolved, err := copier.swagger.resolveSchemaRef(schemaRef)
+	if err != nil || resolved.Value == nil {
+		return resolved, err
+	}
+	value, err := copier.copySchema(resolved.Value)
+	if err != nil {
+		return nil, err
+	}
+	return &openapi3.SchemaRef{Ref: resolved.Ref, Value: value}, nil
+}
+
+// copySchema copies the schema, and the slices and maps holding the schemas under it.
+// Other values, such as the enum, are shared with the document.
+func (copier *schemaCopier) copySchema(schema *openapi3.Schema) (*openapi3.Schema, error) {
+	if copied, ok := copier.copies[schema]; ok {
+		return copied, nil
+	}
+	copied := *schema
+	copier.copies[schema] = &copied
+	var err error
+	copySchemaRefs := func(schemaRefs []*openapi3.SchemaRef) []*openapi3.SchemaRef {
+		if schemaRefs == nil {
+			return nil
+		}
+		result := make([]*openapi3.SchemaRef, len(schemaRefs))
+		for i, schemaRef := range schemaRefs {
+			if err == nil {
+				result[i], err = copier.copySchemaRef(schemaRef)
+			}
+		}
+		return result
+	}
+	copySchemaRef := func(schemaRef *openapi3.SchemaRef) *openapi3.SchemaRef {
+		var result *openapi3.SchemaRef
+		if err == nil {
+			result, err = copier.copySchemaRef(schemaRef)
+		}
+		return result
+	}
+	copied.AllOf = copySchemaRefs(schema.AllOf)
+	copied.AnyOf = copySchemaRefs(schema.AnyOf)
+	copied.OneOf = copySchemaRefs(schema.OneOf)
+	copied.Not = copySchemaRef(schema.Not)
+	copied.Items = copySchemaRef(schema.Items)
+	copied.AdditionalProperties = copySchemaRef(schema.AdditionalProperties)
+	if schema.Properties != nil {
+		copied.Properties = make(map[string]*openapi3.SchemaRef, len(schema.Properties))
+		for name, property := range schema.Properties {
+			copied.Properties[name] = copySchemaRef(property)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &copied, nil
+}
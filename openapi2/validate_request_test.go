@@ -0,0 +1,515 @@
+package openapi2_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/mbilski/kin-openapi/openapi2"
+	"github.com/stretchr/testify/require"
+)
+
+const validateRequestSpec = `
+{
+  "info": {"title": "MyAPI", "version": "0.1"},
+  "basePath": "/v1",
+  "consumes": ["application/json"],
+  "paths": {
+    "/pets": {
+      "post": {
+        "parameters": [
+          {"in": "query", "name": "dryRun", "type": "boolean"},
+          {"in": "body", "name": "pet", "required": true, "schema": {"$ref": "#/definitions/NewPet"}}
+        ],
+        "responses": {"201": {"description": "created"}}
+      }
+    },
+    "/pets/{id}": {
+      "get": {
+        "parameters": [{"in": "path", "name": "id", "type": "integer", "required": true}],
+        "responses": {"200": {"description": "pet"}}
+      }
+    }
+  },
+  "definitions": {
+    "NewPet": {
+      "type": "object",
+      "required": ["name"],
+      "properties": {
+        "name": {"type": "string"},
+        "tags": {"type": "array", "items": {"$ref": "#/definitions/Tag"}}
+      }
+    },
+    "Tag": {"type": "string", "minLength": 2}
+  }
+}`
+
+func validateRequest(t *testing.T, swagger *openapi2.Swagger, method string, target string, contentType string, body string) error {
+	var req *http.Request
+	if body == "" {
+		req = httptest.NewRequest(method, target, nil)
+	} else {
+		req = httptest.NewRequest(method, target, strings.NewReader(body))
+		// The handler can still read the body
+		defer func() {
+			data, err := ioutil.ReadAll(req.Body)
+			require.NoError(t, err)
+			require.Equal(t, body, string(data))
+		}()
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	return openapi2.ValidateRequest(swagger, req)
+}
+
+func TestValidateRequest(t *testing.T) {
+	swagger := loadSwagger(t, validateRequestSpec)
+
+	err := validateRequest(t, swagger, http.MethodPost, "/v1/pets?dryRun=true", "application/json",
+		`{"name": "Rex", "tags": ["good", "dog"]}`)
+	require.NoError(t, err)
+	require.NoError(t, validateRequest(t, swagger, http.MethodGet, "/v1/pets/42", "", ""))
+
+	err = validateRequest(t, swagger, http.MethodPost, "/v1/pets?dryRun=maybe", "application/json",
+		`{"tags": ["good"]}`)
+	require.IsType(t, openapi2.MultiError{}, err)
+	errs := err.(openapi2.MultiError)
+	require.Len(t, errs, 2)
+	require.EqualError(t, errs[0], "Parameter 'dryRun' in query has an error: Value 'maybe' is not a valid boolean")
+	require.IsType(t, &openapi2.RequestError{}, errs[1])
+	require.Equal(t, "pet", errs[1].(*openapi2.RequestError).Parameter.Name)
+	require.Contains(t, errs[1].Error(), `Property 'name' is missing`)
+
+	// Nested references are resolved
+	err = validateRequest(t, swagger, http.MethodPost, "/v1/pets", "application/json", `{"name": "Rex", "tags": ["x"]}`)
+	require.IsType(t, openapi2.MultiError{}, err)
+	require.Contains(t, err.Error(), "Minimum string length is 2")
+
+	err = validateRequest(t, swagger, http.MethodPost, "/v1/pets", "text/plain", `Rex`)
+	require.EqualError(t, err, "Parameter 'pet' in body has an error: Content type 'text/plain' is not consumed by the operation")
+
+	err = validateRequest(t, swagger, http.MethodPost, "/v1/pets", "application/json", "")
+	require.EqualError(t, err, "Parameter 'pet' in body has an error: Value is required")
+
+	err = validateRequest(t, swagger, http.MethodGet, "/v1/pets/abc", "", "")
+	require.EqualError(t, err, "Parameter 'id' in path has an error: Value 'abc' is not a valid integer")
+}
+
+func TestValidateRequestRouteError(t *testing.T) {
+	swagger := loadSwagger(t, validateRequestSpec)
+	for _, tc := range []struct {
+		method string
+		target string
+		reason string
+	}{
+		{http.MethodGet, "/pets/42", "Path was not found"},
+		{http.MethodGet, "/v1/owners", "Path was not found"},
+		{http.MethodDelete, "/v1/pets/42", "Path doesn't support the HTTP method"},
+		{http.MethodTrace, "/v1/pets/42", "Path doesn't support the HTTP method"},
+	} {
+		err := validateRequest(t, swagger, tc.method, tc.target, "", "")
+		require.IsType(t, &openapi2.RouteError{}, err, tc.target)
+		require.Equal(t, tc.reason, err.(*openapi2.RouteError).Reason)
+	}
+	err := validateRequest(t, swagger, http.MethodDelete, "/v1/pets/42", "", "")
+	require.EqualError(t, err, "DELETE /v1/pets/42: Path doesn't support the HTTP method")
+
+	// The paths are matched through a tree cached by ResolveParameters until ResetResolved
+	require.NoError(t, swagger.ResolveParameters())
+	swagger.Paths["/owners"] = &openapi2.PathItem{Get: &openapi2.Operation{}}
+	err = validateRequest(t, swagger, http.MethodGet, "/v1/owners", "", "")
+	require.EqualError(t, err, "GET /v1/owners: Path was not found")
+	swagger.ResetResolved()
+	require.NoError(t, validateRequest(t, swagger, http.MethodGet, "/v1/owners", "", ""))
+}
+
+func TestValidateRequestPathParameters(t *testing.T) {
+	swagger := loadSwagger(t, `
+{
+  "info": {"title": "MyAPI", "version": "0.1"},
+  "paths": {
+    "/users/{userId}/posts": {
+      "get": {
+        "parameters": [{"in": "path", "name": "userId", "type": "string", "required": true, "pattern": "^[a-z]+(/[a-z]+)?$"}],
+        "responses": {"200": {"description": "posts"}}
+      }
+    },
+    "/pets/{kind}": {
+      "get": {
+        "parameters": [{"in": "path", "name": "kind", "type": "string", "required": true, "enum": ["cat", "dog"]}],
+        "responses": {"200": {"description": "pets"}}
+      }
+    }
+  }
+}`)
+	require.NoError(t, validateRequest(t, swagger, http.MethodGet, "/users/alice/posts", "", ""))
+	// The segment is decoded before validation
+	require.NoError(t, validateRequest(t, swagger, http.MethodGet, "/users/team%2Falice/posts", "", ""))
+
+	err := validateRequest(t, swagger, http.MethodGet, "/users/Alice42/posts", "", "")
+	require.EqualError(t, err, "Parameter 'userId' in path has an error: Value 'Alice42' doesn't match pattern '^[a-z]+(/[a-z]+)?$'")
+	err = validateRequest(t, swagger, http.MethodGet, "/users//posts", "", "")
+	require.EqualError(t, err, "Parameter 'userId' in path has an error: Value is required")
+
+	require.NoError(t, validateRequest(t, swagger, http.MethodGet, "/pets/cat", "", ""))
+	err = validateRequest(t, swagger, http.MethodGet, "/pets/bird", "", "")
+	require.EqualError(t, err, "Parameter 'kind' in path has an error: Value bird is not one of the allowed values [cat dog]")
+}
+
+func TestValidateRequestStrictInteger(t *testing.T) {
+	swagger := loadSwagger(t, `
+{
+  "info": {"title": "MyAPI", "version": "0.1"},
+  "paths": {
+    "/orders": {
+      "post": {
+        "parameters": [
+          {"in": "query", "name": "count", "type": "integer", "multipleOf": 2},
+          {"in": "body", "name": "order", "schema": {
+            "type": "object",
+            "properties": {"quantities": {"type": "array", "items": {"type": "integer", "multipleOf": 2}}}
+          }}
+        ],
+        "responses": {"201": {"description": "created"}}
+      }
+    }
+  }
+}`)
+	for _, tc := range []struct {
+		value  string
+		strict bool
+		errs   []string
+	}{
+		{"42", false, nil},
+		{"42", true, nil},
+		{"42.0", false, nil},
+		{"42.0", true, []string{
+			"Parameter 'count' in query has an error: Value '42.0' is not a valid integer",
+			"Parameter 'order' in body has an error: Property 'quantities': Item 0: Value 42.0 must be an integer",
+		}},
+		{"42.5", false, []string{
+			"Parameter 'count' in query has an error: Value 42.5 must be an integer",
+			`Parameter 'order' in body has an error: Error at "/quantities/0":Value must be an integer`,
+		}},
+		{"42.5", true, []string{
+			"Parameter 'count' in query has an error: Value '42.5' is not a valid integer",
+			"Parameter 'order' in body has an error: Property 'quantities': Item 0: Value 42.5 must be an integer",
+		}},
+		{"43", true, []string{
+			"Parameter 'count' in query has an error: Value 43 must be a multiple of 2",
+			`Parameter 'order' in body has an error: Error at "/quantities/0":Doesn't match schema "multipleOf"`,
+		}},
+	} {
+		req := httptest.NewRequest(http.MethodPost, "/orders?count="+tc.value, strings.NewReader(`{"quantities": [`+tc.value+`]}`))
+		err := openapi2.ValidateRequestWithOptions(swagger, req, openapi2.ValidationOptions{StrictInteger: tc.strict})
+		if tc.errs == nil {
+			require.NoError(t, err, tc.value)
+			continue
+		}
+		require.IsType(t, openapi2.MultiError{}, err, tc.value)
+		var messages []string
+		for _, err := range err.(openapi2.MultiError) {
+			// Schema errors go on with the schema and the value
+			messages = append(messages, strings.SplitN(err.Error(), "\n", 2)[0])
+		}
+		require.Equal(t, tc.errs, messages)
+	}
+}
+
+func TestValidateRequestMultipleOfDecimal(t *testing.T) {
+	swagger := loadSwagger(t, `
+{
+  "info": {"title": "MyAPI", "version": "0.1"},
+  "paths": {
+    "/prices": {
+      "get": {
+        "parameters": [{"in": "query", "name": "price", "type": "number", "multipleOf": 0.1}],
+        "responses": {"200": {"description": "list"}}
+      }
+    }
+  }
+}`)
+	for _, value := range []string{"0.3", "0.7", "1.1", "-2.3", "2e-1"} {
+		require.NoError(t, validateRequest(t, swagger, http.MethodGet, "/prices?price="+value, "", ""), value)
+	}
+	err := validateRequest(t, swagger, http.MethodGet, "/prices?price=0.35", "", "")
+	require.EqualError(t, err, "Parameter 'price' in query has an error: Value 0.35 must be a multiple of 0.1")
+}
+
+func TestValidateRequestCombinators(t *testing.T) {
+	swagger := loadSwagger(t, `
+{
+  "info": {"title": "MyAPI", "version": "0.1"},
+  "paths": {
+    "/all": {
+      "post": {
+        "parameters": [{"in": "body", "name": "pet", "schema": {
+          "allOf": [{"$ref": "#/definitions/Named"}, {"$ref": "#/definitions/Aged"}]
+        }}],
+        "responses": {"201": {"description": "created"}}
+      }
+    },
+    "/any": {
+      "post": {
+        "parameters": [{"in": "body", "name": "id", "schema": {
+          "anyOf": [{"type": "string"}, {"type": "integer"}]
+        }}],
+        "responses": {"201": {"description": "created"}}
+      }
+    },
+    "/one": {
+      "post": {
+        "parameters": [{"in": "body", "name": "amount", "schema": {
+          "oneOf": [{"type": "integer"}, {"type": "number", "maximum": 10}]
+        }}],
+        "responses": {"201": {"description": "created"}}
+      }
+    }
+  },
+  "definitions": {
+    "Named": {"type": "object", "required": ["name"], "properties": {"name": {"type": "string"}}},
+    "Aged": {"type": "object", "required": ["age"], "properties": {"age": {"type": "integer"}}}
+  }
+}`)
+	for _, tc := range []struct {
+		path string
+		body string
+		err  string
+	}{
+		{"/all", `{"name": "Rex", "age": 3}`, ""},
+		{"/all", `{"name": "Rex"}`, `Parameter 'pet' in body has an error: Value doesn't match allOf schema 1: Property 'age' is missing`},
+		{"/all", `{}`, `Parameter 'pet' in body has an error: Value doesn't match allOf schemas 0, 1: Property 'name' is missing`},
+		{"/any", `"abc"`, ""},
+		{"/any", `42`, ""},
+		{"/any", `true`, "Parameter 'id' in body has an error: Value doesn't match any of the 2 anyOf schemas"},
+		{"/one", `12`, ""},
+		{"/one", `2.5`, ""},
+		{"/one", `2`, "Parameter 'amount' in body has an error: Value matches 2 of the 2 oneOf schemas, but must match exactly one"},
+		{"/one", `12.5`, "Parameter 'amount' in body has an error: Value matches 0 of the 2 oneOf schemas, but must match exactly one"},
+	} {
+		err := validateRequest(t, swagger, http.MethodPost, tc.path, "application/json", tc.body)
+		if tc.err == "" {
+			require.NoError(t, err, tc.body)
+			continue
+		}
+		require.IsType(t, openapi2.MultiError{}, err, tc.body)
+		// Schema errors go on with the schema and the value
+		require.Equal(t, tc.err, strings.SplitN(err.Error(), "\n", 2)[0], tc.body)
+	}
+}
+
+func TestValidateRequestAdditionalProperties(t *testing.T) {
+	swagger := loadSwagger(t, `
+{
+  "info": {"title": "MyAPI", "version": "0.1"},
+  "paths": {
+    "/pets": {
+      "post": {
+        "parameters": [{"in": "body", "name": "pet", "schema": {
+          "type": "object",
+          "additionalProperties": false,
+          "properties": {
+            "name": {"type": "string"},
+            "labels": {"type": "object", "additionalProperties": {"$ref": "#/definitions/Label"}},
+            "extra": {"type": "object"}
+          }
+        }}],
+        "responses": {"201": {"description": "created"}}
+      }
+    }
+  },
+  "definitions": {
+    "Label": {"type": "string", "maxLength": 5}
+  }
+}`)
+	for _, tc := range []struct {
+		body string
+		err  string
+	}{
+		{`{"name": "Rex", "labels": {"color": "brown"}, "extra": {"anything": 1}}`, ""},
+		{`{"name": "Rex", "age": 3}`, `Parameter 'pet' in body has an error: Property 'age' is unsupported`},
+		{`{"labels": {"color": "light brown"}}`, `Parameter 'pet' in body has an error: Error at "/labels/color":Maximum string length is 5`},
+		{`{"labels": {"color": 42}}`, `Parameter 'pet' in body has an error: Error at "/labels/color":Field must be set to string or not be present`},
+	} {
+		err := validateRequest(t, swagger, http.MethodPost, "/pets", "application/json", tc.body)
+		if tc.err == "" {
+			require.NoError(t, err, tc.body)
+			continue
+		}
+		require.IsType(t, openapi2.MultiError{}, err, tc.body)
+		// Schema errors go on with the schema and the value
+		require.Equal(t, tc.err, strings.SplitN(err.Error(), "\n", 2)[0], tc.body)
+	}
+}
+
+func TestValidateRequestFormData(t *testing.T) {
+	swagger := loadSwagger(t, `
+{
+  "info": {"title": "MyAPI", "version": "0.1"},
+  "paths": {
+    "/pets/{id}": {
+      "post": {
+        "consumes": ["application/x-www-form-urlencoded", "multipart/form-data"],
+        "parameters": [
+          {"in": "path", "name": "id", "type": "integer", "required": true},
+          {"in": "formData", "name": "name", "type": "string", "required": true, "minLength": 2},
+          {"in": "formData", "name": "age", "type": "integer", "maximum": 30},
+          {"in": "formData", "name": "tags", "type": "array", "collectionFormat": "multi",
+           "items": {"type": "string", "enum": ["good", "dog"]}},
+          {"in": "formData", "name": "sizes", "type": "array", "items": {"type": "integer"}}
+        ],
+        "responses": {"200": {"description": "updated"}}
+      }
+    }
+  }
+}`)
+	const form = "application/x-www-form-urlencoded"
+	err := validateRequest(t, swagger, http.MethodPost, "/pets/1", form, "name=Rex&age=3&tags=good&tags=dog&sizes=1,2")
+	require.NoError(t, err)
+	err = validateRequest(t, swagger, http.MethodPost, "/pets/1", "", "name=Rex")
+	require.NoError(t, err)
+	// Multipart bodies aren't validated
+	err = validateRequest(t, swagger, http.MethodPost, "/pets/1", "multipart/form-data; boundary=x", "--x--")
+	require.NoError(t, err)
+
+	err = validateRequest(t, swagger, http.MethodPost, "/pets/1", form, "age=31&tags=good&tags=cat&sizes=1,x")
+	require.IsType(t, openapi2.MultiError{}, err)
+	var messages []string
+	for _, err := range err.(openapi2.MultiError) {
+		require.IsType(t, &openapi2.RequestError{}, err)
+		messages = append(messages, err.Error())
+	}
+	require.Equal(t, []string{
+		"Parameter 'name' in formData has an error: Value is required",
+		"Parameter 'age' in formData has an error: Value 31 must be at most 30",
+		"Parameter 'tags' in formData has an error: Item 1 is invalid: Value cat is not one of the allowed values [good dog]",
+		"Parameter 'sizes' in formData has an error: Value 'x' is not a valid integer",
+	}, messages)
+
+	err = validateRequest(t, swagger, http.MethodPost, "/pets/1", "", "")
+	require.EqualError(t, err, "Parameter 'name' in formData has an error: Value is required")
+
+	err = validateRequest(t, swagger, http.MethodPost, "/pets/1", "application/json", `{"name": "Rex"}`)
+	require.EqualError(t, err, "Content type 'application/json' is not consumed by the operation")
+}
+
+func TestValidateRequestStringLength(t *testing.T) {
+	swagger := loadSwagger(t, `
+{
+  "info": {"title": "MyAPI", "version": "0.1"},
+  "paths": {
+    "/pets": {
+      "post": {
+        "parameters": [
+          {"in": "query", "name": "nick", "type": "string", "minLength": 2, "maxLength": 3},
+          {"in": "body", "name": "pet", "schema": {
+            "type": "object",
+            "properties": {"name": {"type": "string", "minLength": 2, "maxLength": 3}}
+          }}
+        ],
+        "responses": {"201": {"description": "created"}}
+      }
+    }
+  }
+}`)
+	// Lengths are counted in code points, an emoji being 4 bytes
+	err := validateRequest(t, swagger, http.MethodPost, "/pets?nick=%F0%9F%90%B6%F0%9F%90%B1%F0%9F%90%AD",
+		"application/json", `{"name": "🐶🐱🐭"}`)
+	require.NoError(t, err)
+	err = validateRequest(t, swagger, http.MethodPost, "/pets?nick=%C3%A9%C3%A9", "application/json", `{"name": "éé"}`)
+	require.NoError(t, err)
+
+	err = validateRequest(t, swagger, http.MethodPost, "/pets?nick=%F0%9F%90%B6", "application/json", `{"name": "🐶🐱🐭🐹"}`)
+	require.IsType(t, openapi2.MultiError{}, err)
+	errs := err.(openapi2.MultiError)
+	require.Len(t, errs, 2)
+	require.EqualError(t, errs[0], "Parameter 'nick' in query has an error: Value '🐶' must be at least 2 characters long")
+	require.Contains(t, errs[1].Error(), "Maximum string length is 3")
+}
+
+func TestValidateRequestConcurrent(t *testing.T) {
+	swagger := loadSwagger(t, validateRequestSpec)
+
+	var wg sync.WaitGroup
+	errs := make([]error, 8)
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			req := httptest.NewRequest(http.MethodPost, "/v1/pets", strings.NewReader(`{"name": "Rex", "tags": ["x"]}`))
+			req.Header.Set("Content-Type", "application/json")
+			errs[i] = openapi2.ValidateRequest(swagger, req)
+		}(i)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		require.Error(t, err)
+	}
+	// The references of the document are left unresolved
+	require.Nil(t, swagger.Definitions["NewPet"].Value.Properties["tags"].Value.Items.Value)
+}
+
+func TestValidateRequestResolved(t *testing.T) {
+	swagger := loadSwagger(t, validateRequestSpec)
+	swagger.Definitions["Tag"].Value.Pattern = "^[a-z]+$"
+	require.NoError(t, swagger.ResolveParameters())
+
+	// The cache is only read, so the document can be copied and written meanwhile
+	var wg sync.WaitGroup
+	errs := make([]error, 8)
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			switch i % 3 {
+			case 0:
+				errs[i] = swagger.WriteJSON(ioutil.Discard)
+			case 1:
+				errs[i] = openapi2.ValidateNDJSONResponse(strings.NewReader(`"good"`), swagger.Definitions["Tag"], swagger)
+			default:
+				req := httptest.NewRequest(http.MethodPost, "/v1/pets", strings.NewReader(`{"name": "Rex", "tags": ["good"]}`))
+				req.Header.Set("Content-Type", "application/json")
+				errs[i] = openapi2.ValidateRequest(swagger, req)
+			}
+		}(i)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		require.NoError(t, err)
+	}
+
+	// The body schema is resolved once, and kept until ResetResolved
+	swagger.Definitions["Tag"].Value.Pattern = "^[0-9]+$"
+	require.NoError(t, validateRequest(t, swagger, http.MethodPost, "/v1/pets", "application/json", `{"name": "Rex", "tags": ["good"]}`))
+	swagger.ResetResolved()
+	err := validateRequest(t, swagger, http.MethodPost, "/v1/pets", "application/json", `{"name": "Rex", "tags": ["good"]}`)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "doesn't match the regular expression")
+}
+
+func TestValidateRequestPathItemBody(t *testing.T) {
+	swagger := loadSwagger(t, `
+{
+  "info": {"title": "MyAPI", "version": "0.1"},
+  "paths": {
+    "/pets": {
+      "parameters": [
+        {"in": "body", "name": "pet", "required": true, "schema": {"type": "object", "required": ["name"]}}
+      ],
+      "put": {"responses": {"204": {"description": "replaced"}}}
+    }
+  }
+}`)
+	require.NoError(t, validateRequest(t, swagger, http.MethodPut, "/pets", "application/json", `{"name": "Rex"}`))
+
+	err := validateRequest(t, swagger, http.MethodPut, "/pets", "application/json", `{}`)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "Parameter 'pet' in body has an error")
+
+	err = validateRequest(t, swagger, http.MethodPut, "/pets", "", "")
+	require.EqualError(t, err, "Parameter 'pet' in body has an error: Value is required")
+}
@@ -0,0 +1,412 @@
+package openapi2_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mbilski/kin-openapi/openapi2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidatePetstore(t *testing.T) {
+	swagger := loadSwaggerFile(t, "testdata/swagger.json")
+	require.NoError(t, swagger.Validate(context.Background()))
+}
+
+func TestValidate(t *testing.T) {
+	swagger := loadSwagger(t, `
+{
+  "info": {"title": "MyAPI"},
+  "paths": {
+    "/pets/{id}": {
+      "parameters": [{"in": "path", "name": "id", "type": "string"}],
+      "get": {
+        "parameters": [
+          {"in": "query", "name": "tags", "type": "array"},
+          {"in": "query", "name": "code", "type": "string", "pattern": "^(a)\\1$"},
+          {"in": "body", "name": "body"},
+          {"in": "cookie", "name": "session", "type": "string"}
+        ]
+      }
+    }
+  }
+}`)
+	err := swagger.Validate(context.Background())
+	require.IsType(t, openapi2.MultiError{}, err)
+	var messages []string
+	for _, err := range err.(openapi2.MultiError) {
+		messages = append(messages, err.Error())
+	}
+	require.Equal(t, []string{
+		"/info: Variable 'version' must be a non-empty JSON string",
+		"/paths/~1pets~1{id}/get/responses: Operation must declare at least one response",
+		"/paths/~1pets~1{id}/parameters/0: Path parameter 'id' must be required",
+		"/paths/~1pets~1{id}/get/parameters/0: Array parameter 'tags' must have items",
+		`/paths/~1pets~1{id}/get/parameters/1: Pattern '^(a)\1$' uses a backreference ('\1'), which is not supported by Go regular expressions (RE2)`,
+		"/paths/~1pets~1{id}/get/parameters/2: Body parameter 'body' must have a schema",
+		"/paths/~1pets~1{id}/get/parameters/3: Parameter 'session' has unsupported location 'cookie'",
+	}, messages)
+}
+
+func TestValidateParameterDefaults(t *testing.T) {
+	swagger := loadSwagger(t, `
+{
+  "info": {"title": "MyAPI", "version": "0.1"},
+  "paths": {
+    "/pets": {
+      "get": {
+        "parameters": [
+          {"in": "query", "name": "limit", "type": "integer", "minimum": 1, "maximum": 100, "default": 20},
+          {"in": "query", "name": "sort", "type": "string", "enum": ["asc", "desc"], "default": "asc"},
+          {"in": "query", "name": "code", "type": "string", "pattern": "^[a-z]+$", "default": "abc"},
+          {"in": "query", "name": "verbose", "type": "boolean", "default": false},
+          {"in": "query", "name": "tags", "type": "array", "items": {"type": "string"}, "default": ["a"]}
+        ],
+        "responses": {"200": {"description": "list"}}
+      }
+    }
+  }
+}`)
+	require.NoError(t, swagger.Validate(context.Background()))
+}
+
+func TestValidateParameterDefaultsInvalid(t *testing.T) {
+	swagger := loadSwagger(t, `
+{
+  "info": {"title": "MyAPI", "version": "0.1"},
+  "paths": {
+    "/pets": {
+      "get": {
+        "parameters": [
+          {"in": "query", "name": "page", "type": "integer", "default": "abc"},
+          {"in": "query", "name": "limit", "type": "integer", "maximum": 100, "default": 200},
+          {"in": "query", "name": "offset", "type": "integer", "default": 1.5},
+          {"in": "query", "name": "sort", "type": "string", "enum": ["asc", "desc"], "default": "up"},
+          {"in": "query", "name": "code", "type": "string", "pattern": "^[a-z]+$", "default": "ABC"},
+          {"in": "query", "name": "name", "type": "string", "minLength": 2, "default": "a"}
+        ],
+        "responses": {"200": {"description": "list"}}
+      }
+    }
+  }
+}`)
+	err := swagger.Validate(context.Background())
+	require.IsType(t, openapi2.MultiError{}, err)
+	var messages []string
+	for _, err := range err.(openapi2.MultiError) {
+		messages = append(messages, err.Error())
+	}
+	require.Equal(t, []string{
+		"/paths/~1pets/get/parameters/0: Default value of parameter 'page' is invalid: Value abc must be a number",
+		"/paths/~1pets/get/parameters/1: Default value of parameter 'limit' is invalid: Value 200 must be at most 100",
+		"/paths/~1pets/get/parameters/2: Default value of parameter 'offset' is invalid: Value 1.5 must be an integer",
+		"/paths/~1pets/get/parameters/3: Default value of parameter 'sort' is invalid: Value up is not one of the allowed values [asc desc]",
+		"/paths/~1pets/get/parameters/4: Default value of parameter 'code' is invalid: Value 'ABC' doesn't match pattern '^[a-z]+$'",
+		"/paths/~1pets/get/parameters/5: Default value of parameter 'name' is invalid: Value 'a' must be at least 2 characters long",
+	}, messages)
+}
+
+func TestValidateMediaTypes(t *testing.T) {
+	swagger := loadSwagger(t, `
+{
+  "info": {"title": "MyAPI", "version": "0.1"},
+  "consumes": ["application/json", "json"],
+  "produces": ["application/json; charset=utf-8"],
+  "paths": {
+    "/pets": {
+      "post": {
+        "consumes": ["multipart/form-data"],
+        "produces": ["application/ json"],
+        "responses": {"201": {"description": "created"}}
+      }
+    }
+  }
+}`)
+	err := swagger.Validate(context.Background())
+	require.IsType(t, openapi2.MultiError{}, err)
+	var messages []string
+	for _, err := range err.(openapi2.MultiError) {
+		messages = append(messages, err.Error())
+	}
+	require.Equal(t, []string{
+		"/consumes/1: Invalid media type 'json': missing subtype",
+		"/paths/~1pets/post/produces/0: Invalid media type 'application/ json': expected token after slash",
+	}, messages)
+}
+
+func TestValidateSecurityRequirements(t *testing.T) {
+	swagger := loadSwagger(t, `
+{
+  "info": {"title": "MyAPI", "version": "0.1"},
+  "securityDefinitions": {
+    "oauth": {
+      "type": "oauth2",
+      "flow": "implicit",
+      "authorizationUrl": "https://example.com/oauth/authorize",
+      "scopes": {"read": "Read access"}
+    },
+    "key": {"type": "apiKey", "name": "X-API-Key", "in": "header"}
+  },
+  "security": [{"oauth": ["read"]}, {"key": []}],
+  "paths": {
+    "/pets": {
+      "post": {
+        "security": [{"oauth": ["read", "write"], "key": ["admin"]}, {"session": []}],
+        "responses": {"201": {"description": "created"}}
+      }
+    }
+  }
+}`)
+	err := swagger.Validate(context.Background())
+	require.IsType(t, openapi2.MultiError{}, err)
+	var messages []string
+	for _, err := range err.(openapi2.MultiError) {
+		messages = append(messages, err.Error())
+	}
+	require.Equal(t, []string{
+		"/paths/~1pets/post/security/0/key: Security scheme 'key' of type 'apiKey' must have an empty scope list",
+		"/paths/~1pets/post/security/0/oauth: Scope 'write' is not declared by security scheme 'oauth'",
+		"/paths/~1pets/post/security/1/session: Security scheme 'session' is not defined",
+	}, messages)
+}
+
+func TestValidateParameterItems(t *testing.T) {
+	swagger := loadSwagger(t, `
+{
+  "info": {"title": "MyAPI", "version": "0.1"},
+  "paths": {
+    "/pets": {
+      "get": {
+        "parameters": [
+          {"in": "query", "name": "tags", "type": "array", "items": {"type": "object"}},
+          {"in": "query", "name": "grid", "type": "array", "items": {"type": "array"}},
+          {"in": "query", "name": "sizes", "type": "array", "items": {"type": "integer", "enum": [1, "two"]}},
+          {"in": "query", "name": "status", "type": "array", "default": ["sold", "lost"],
+           "items": {"type": "string", "enum": ["available", "sold"]}}
+        ],
+        "responses": {"200": {"description": "pets"}}
+      }
+    }
+  }
+}`)
+	err := swagger.Validate(context.Background())
+	require.IsType(t, openapi2.MultiError{}, err)
+	var messages []string
+	for _, err := range err.(openapi2.MultiError) {
+		messages = append(messages, err.Error())
+	}
+	require.Equal(t, []string{
+		"/paths/~1pets/get/parameters/0: Items of parameter 'tags' have unsupported type 'object'",
+		"/paths/~1pets/get/parameters/1: Nested array items of parameter 'grid' must have items",
+		"/paths/~1pets/get/parameters/2: Enum value of items of parameter 'sizes' is invalid: Value two must be a number",
+		"/paths/~1pets/get/parameters/3: Default value of parameter 'status' is invalid: Item 1 is invalid: Value lost is not one of the allowed values [available sold]",
+	}, messages)
+}
+
+func TestValidateOperationParameters(t *testing.T) {
+	swagger := loadSwagger(t, `
+{
+  "info": {"title": "MyAPI", "version": "0.1"},
+  "consumes": ["application/json"],
+  "parameters": {
+    "name": {"in": "formData", "name": "name", "type": "string"}
+  },
+  "paths": {
+    "/pets": {
+      "post": {
+        "parameters": [{"in": "body", "name": "pet", "schema": {"type": "object"}}],
+        "responses": {"201": {"description": "created"}}
+      },
+      "put": {
+        "consumes": ["application/x-www-form-urlencoded"],
+        "parameters": [{"$ref": "#/parameters/name"}],
+        "responses": {"200": {"description": "updated"}}
+      },
+      "patch": {
+        "consumes": ["application/x-www-form-urlencoded"],
+        "parameters": [
+          {"in": "body", "name": "pet", "schema": {"type": "object"}},
+          {"$ref": "#/parameters/name"}
+        ],
+        "responses": {"200": {"description": "updated"}}
+      }
+    },
+    "/pets/{id}": {
+      "parameters": [
+        {"in": "path", "name": "id", "type": "string", "required": true},
+        {"in": "formData", "name": "note", "type": "string"}
+      ],
+      "post": {
+        "responses": {"200": {"description": "updated"}}
+      },
+      "put": {
+        "consumes": ["multipart/form-data; boundary=x"],
+        "responses": {"200": {"description": "updated"}}
+      }
+    },
+    "/pets/{id}/photo": {
+      "parameters": [{"in": "path", "name": "id", "type": "string", "required": true}],
+      "post": {
+        "consumes": ["application/x-www-form-urlencoded"],
+        "parameters": [{"in": "formData", "name": "photo", "type": "file"}],
+        "responses": {"200": {"description": "uploaded"}}
+      },
+      "put": {
+        "consumes": ["multipart/form-data"],
+        "parameters": [{"in": "formData", "name": "photo", "type": "file"}],
+        "responses": {"200": {"description": "uploaded"}}
+      },
+      "delete": {
+        "parameters": [
+          {"in": "body", "name": "a", "schema": {"type": "object"}},
+          {"in": "body", "name": "b", "schema": {"type": "object"}}
+        ],
+        "responses": {"204": {"description": "deleted"}}
+      }
+    }
+  }
+}`)
+	err := swagger.Validate(context.Background())
+	require.IsType(t, openapi2.MultiError{}, err)
+	var messages []string
+	for _, err := range err.(openapi2.MultiError) {
+		messages = append(messages, err.Error())
+	}
+	require.Equal(t, []string{
+		"/paths/~1pets/patch/parameters: Operation must not have both body and formData parameters",
+		"/paths/~1pets~1{id}/post/consumes: Operation with formData parameters must consume 'application/x-www-form-urlencoded' or 'multipart/form-data'",
+		"/paths/~1pets~1{id}~1photo/delete/parameters: Operation must not have more than one body parameter",
+		"/paths/~1pets~1{id}~1photo/post/consumes: Operation with file parameters must consume 'multipart/form-data'",
+	}, messages)
+}
+
+func TestValidateEmptyEnum(t *testing.T) {
+	swagger := loadSwagger(t, `
+{
+  "info": {"title": "MyAPI", "version": "0.1"},
+  "paths": {
+    "/pets": {
+      "get": {
+        "parameters": [
+          {"in": "query", "name": "status", "type": "string", "enum": []},
+          {"in": "query", "name": "sort", "type": "string", "enum": ["asc", "desc"]},
+          {"in": "query", "name": "tags", "type": "array", "items": {"type": "string", "enum": []}},
+          {"in": "query", "name": "name", "type": "string"}
+        ],
+        "responses": {
+          "200": {"description": "pets", "schema": {"type": "array", "items": {"$ref": "#/definitions/Pet"}}},
+          "default": {"description": "error", "schema": {"type": "string", "enum": []}}
+        }
+      }
+    }
+  },
+  "definitions": {
+    "Pet": {
+      "type": "object",
+      "properties": {
+        "kind": {"type": "string", "enum": []},
+        "size": {"type": "string", "enum": ["small", "large"]}
+      }
+    }
+  }
+}`)
+	err := swagger.Validate(context.Background())
+	require.IsType(t, openapi2.MultiError{}, err)
+	var messages []string
+	for _, err := range err.(openapi2.MultiError) {
+		messages = append(messages, err.Error())
+	}
+	require.Equal(t, []string{
+		"/paths/~1pets/get/parameters/0: Parameter 'status' has an empty enum",
+		"/paths/~1pets/get/parameters/2: Items of parameter 'tags' have an empty enum",
+		"/definitions/Pet/properties/kind: Schema has an empty enum",
+		"/paths/~1pets/get/responses/default/schema: Schema has an empty enum",
+	}, messages)
+}
+
+func TestValidateServers(t *testing.T) {
+	swagger := loadSwagger(t, `
+{
+  "info": {"title": "MyAPI", "version": "0.1"},
+  "host": "https://api.example.com",
+  "basePath": "v1",
+  "paths": {}
+}`)
+	ctx := context.Background()
+	err := swagger.Validate(ctx)
+	require.Equal(t, openapi2.MultiError{
+		&openapi2.LintError{
+			Pointer: "/host",
+			Reason:  "Invalid host 'https://api.example.com': must not include a scheme, which belongs to 'schemes'",
+		},
+		&openapi2.LintError{
+			Pointer: "/basePath",
+			Reason:  "Invalid basePath 'v1': must start with '/'",
+		},
+	}, err)
+
+	swagger.Host = "api.example.com:8443"
+	for basePath, expected := range map[string]string{
+		"":              "",
+		"/v1/pets%20v2": "",
+		"/v1?debug=1":   "/basePath: Invalid basePath '/v1?debug=1': must not contain a query or a fragment",
+		"/v1#top":       "/basePath: Invalid basePath '/v1#top': must not contain a query or a fragment",
+		"/v 1":          "/basePath: Invalid basePath '/v 1': character ' ' must be percent-encoded",
+		"/v1%2":         "/basePath: Invalid basePath '/v1%2': malformed percent-encoding",
+	} {
+		swagger.BasePath = basePath
+		err := swagger.Validate(ctx)
+		if expected == "" {
+			require.NoError(t, err, basePath)
+		} else {
+			require.EqualError(t, err, expected, basePath)
+		}
+	}
+}
+
+func TestValidateRefTargets(t *testing.T) {
+	swagger := loadSwagger(t, `
+{
+  "info": {"title": "MyAPI", "version": "0.1"},
+  "definitions": {
+    "Foo": {"type": "object"},
+    "Bar": {"type": "array", "items": {"$ref": "#/responses/Foo"}}
+  },
+  "parameters": {
+    "Limit": {"in": "query", "name": "limit", "type": "integer"}
+  },
+  "responses": {
+    "Foo": {"description": "foo", "schema": {"$ref": "#/definitions/Foo"}}
+  },
+  "paths": {
+    "/foos": {
+      "get": {
+        "parameters": [
+          {"$ref": "#/parameters/Limit"},
+          {"$ref": "#/definitions/Foo"}
+        ],
+        "responses": {
+          "200": {"$ref": "#/responses/Foo"},
+          "404": {"$ref": "#/definitions/Foo"},
+          "default": {"$ref": "external.json#/definitions/Error"}
+        }
+      }
+    }
+  }
+}`)
+	err := swagger.Validate(context.Background())
+	require.Equal(t, openapi2.MultiError{
+		&openapi2.LintError{
+			Pointer: "/definitions/Bar/items",
+			Reason:  "Schema $ref '#/responses/Foo' points into '#/responses', but must point into '#/definitions'",
+		},
+		&openapi2.LintError{
+			Pointer: "/paths/~1foos/get/parameters/1",
+			Reason:  "Parameter $ref '#/definitions/Foo' points into '#/definitions', but must point into '#/parameters'",
+		},
+		&openapi2.LintError{
+			Pointer: "/paths/~1foos/get/responses/404",
+			Reason:  "Response $ref '#/definitions/Foo' points into '#/definitions', but must point into '#/responses'",
+		},
+	}, err)
+}
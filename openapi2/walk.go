@@ -0,0 +1,312 @@
+package openapi2
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/mbilski/kin-openapi/openapi3"
+)
+
+const definitionsPrefix = "#/definitions/"
+
+// escapePointerToken escapes a JSON pointer token as described in RFC 6901.
+func escapePointerToken(token string) string {
+	token = strings.Replace(token, "~", "~0", -1)
+	return strings.Replace(token, "/", "~1", -1)
+}
+
+// unescapePointerToken reverses escapePointerToken.
+func unescapePointerToken(token string) string {
+	token = strings.Replace(token, "~1", "/", -1)
+	return strings.Replace(token, "~0", "~", -1)
+}
+
+// definitionName returns the unescaped name of the definition a $ref points at, such as "Foo/Bar"
+// for "#/definitions/Foo~1Bar". A $ref pointing inside the definition also returns the rest
+// of its pointer, such as "/properties/tag" for "#/definitions/Pet/properties/tag".
+func definitionName(ref string) (name string, rest string, ok bool) {
+	if !strings.HasPrefix(ref, definitionsPrefix) {
+		return "", "", false
+	}
+	name = ref[len(definitionsPrefix):]
+	if i := strings.IndexByte(name, '/'); i >= 0 {
+		name, rest = name[:i], name[i:]
+	}
+	return unescapePointerToken(name), rest, true
+}
+
+// walkSchemaRef calls visit for schemaRef and every schema reference nested in it.
+// The walk doesn't follow $ref: a reference is visited, but what it points at isn't entered.
+func walkSchemaRef(pointer string, schemaRef *openapi3.SchemaRef, visit func(pointer string, schemaRef *openapi3.SchemaRef)) {
+	walkSchemaRefVisited(pointer, schemaRef, visit, make(map[*openapi3.Schema]struct{}))
+}
+
+func walkSchemaRefVisited(pointer string, schemaRef *openapi3.SchemaRef, visit func(string, *openapi3.SchemaRef), visited map[*openapi3.Schema]struct{}) {
+	if schemaRef == nil {
+		return
+	}
+	visit(pointer, schemaRef)
+	if schemaRef.Ref != "" {
+		return
+	}
+	schema := schemaRef.Value
+	if schema == nil {
+		return
+	}
+	// Schemas built in code may point back at themselves
+	if _, ok := visited[schema]; ok {
+		return
+	}
+	visited[schema] = struct{}{}
+	for i, v := range schema.AllOf {
+		walkSchemaRefVisited(pointer+"/allOf/"+strconv.Itoa(i), v, visit, visited)
+	}
+	for i, v := range schema.AnyOf {
+		walkSchemaRefVisited(pointer+"/anyOf/"+strconv.Itoa(i), v, visit, visited)
+	}
+	for i, v := range schema.OneOf {
+		walkSchemaRefVisited(pointer+"/oneOf/"+strconv.Itoa(i), v, visit, visited)
+	}
+	walkSchemaRefVisited(pointer+"/not", schema.Not, visit, visited)
+	walkSchemaRefVisited(pointer+"/items", schema.Items, visit, visited)
+	for _, name := range sortedSchemaNames(schema.Properties) {
+		walkSchemaRefVisited(pointer+"/properties/"+escapePointerToken(name), schema.Properties[name], visit, visited)
+	}
+	walkSchemaRefVisited(pointer+"/additionalProperties", schema.AdditionalProperties, visit, visited)
+}
+
+// definitionDependencies returns the names of the definitions referenced by the given schema,
+// in the order they were found and without duplicates.
+func definitionDependencies(schemaRef *openapi3.SchemaRef) []string {
+	var names []string
+	seen := make(map[string]struct{})
+	walkSchemaRef("", schemaRef, func(_ string, schemaRef *openapi3.SchemaRef) {
+		name, _, ok := definitionName(schemaRef.Ref)
+		if !ok {
+			return
+		}
+		if _, ok := seen[name]; ok {
+			return
+		}
+		seen[name] = struct{}{}
+		names = append(names, name)
+	})
+	return names
+}
+
+func sortedSchemaNames(schemas map[string]*openapi3.SchemaRef) []string {
+	names := make([]string, 0, len(schemas))
+	for name := range schemas {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// operationMethods lists HTTP methods in the order operations are walked.
+var operationMethods = []string{"DELETE", "GET", "HEAD", "OPTIONS", "PATCH", "POST", "PUT"}
+
+// pathPointer returns the JSON pointer of a path item.
+func pathPointer(path string) string {
+	return "/paths/" + escapePointerToken(path)
+}
+
+// operationPointer returns the JSON pointer of an operation.
+func operationPointer(path string, method string) string {
+	return pathPointer(path) + "/" + strings.ToLower(method)
+}
+
+func (swagger *Swagger) sortedPaths() []string {
+	paths := make([]string, 0, len(swagger.Paths))
+	for path := range swagger.Paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// walkOperations calls fn for every operation, sorted by path and then method.
+func (swagger *Swagger) walkOperations(fn func(path string, method string, operation *Operation)) {
+	for _, path := range swagger.sortedPaths() {
+		pathItem := swagger.Paths[path]
+		if pathItem == nil {
+			continue
+		}
+		for _, method := range operationMethods {
+			if operation := pathItem.GetOperation(method); operation != nil {
+				fn(path, method, operation)
+			}
+		}
+	}
+}
+
+func sortedResponseKeys(responses map[string]*Response) []string {
+	keys := make([]string, 0, len(responses))
+	for key := range responses {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// walkParameters calls fn for every parameter of the document: shared parameters,
+// then path item and operation parameters sorted by path and method.
+// References are not resolved.
+func (swagger *Swagger) walkParameters(fn func(pointer string, parameter *Parameter)) {
+	for _, name := range sortedParameterNames(swagger.Parameters) {
+		if parameter := swagger.Parameters[name]; parameter != nil {
+			fn("/parameters/"+escapePointerToken(name), parameter)
+		}
+	}
+	for _, path := range swagger.sortedPaths() {
+		pathItem := swagger.Paths[path]
+		if pathItem == nil {
+			continue
+		}
+		for i, parameter := range pathItem.Parameters {
+			if parameter != nil {
+				fn(pathPointer(path)+"/parameters/"+strconv.Itoa(i), parameter)
+			}
+		}
+		for _, method := range operationMethods {
+			operation := pathItem.GetOperation(method)
+			if operation == nil {
+				continue
+			}
+			for i, parameter := range operation.Parameters {
+				if parameter != nil {
+					fn(operationPointer(path, method)+"/parameters/"+strconv.Itoa(i), parameter)
+				}
+			}
+		}
+	}
+}
+
+// walkResponses calls fn for every response of the document: shared responses,
+// then operation responses sorted by path, method and status.
+// References are not resolved.
+func (swagger *Swagger) walkResponses(fn func(pointer string, response *Response)) {
+	for _, name := range sortedResponseKeys(swagger.Responses) {
+		if response := swagger.Responses[name]; response != nil {
+			fn("/responses/"+escapePointerToken(name), response)
+		}
+	}
+	swagger.walkOperations(func(path string, method string, operation *Operation) {
+		for _, status := range sortedResponseKeys(operation.Responses) {
+			if response := operation.Responses[status]; response != nil {
+				fn(operationPointer(path, method)+"/responses/"+escapePointerToken(status), response)
+			}
+		}
+	})
+}
+
+func sortedHeaderNames(headers map[string]*Header) []string {
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func sortedSecuritySchemeNames(securitySchemes map[string]*SecurityScheme) []string {
+	names := make([]string, 0, len(securitySchemes))
+	for name := range securitySchemes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// refVisitor is called with the JSON pointer of an element holding a $ref and the $ref itself,
+// which it may rewrite.
+type refVisitor func(pointer string, ref *string)
+
+func walkSchemaRefRefs(pointer string, schemaRef *openapi3.SchemaRef, fn refVisitor) {
+	walkSchemaRef(pointer, schemaRef, func(pointer string, schemaRef *openapi3.SchemaRef) {
+		if schemaRef.Ref != "" {
+			fn(pointer, &schemaRef.Ref)
+		}
+	})
+}
+
+func walkParameterRefs(pointer string, parameter *Parameter, fn refVisitor) {
+	if parameter == nil {
+		return
+	}
+	if parameter.Ref != "" {
+		fn(pointer, &parameter.Ref)
+		return
+	}
+	walkSchemaRefRefs(pointer+"/schema", parameter.Schema, fn)
+	walkSchemaRefRefs(pointer+"/items", parameter.Items, fn)
+}
+
+func walkResponseRefs(pointer string, response *Response, fn refVisitor) {
+	if response == nil {
+		return
+	}
+	if response.Ref != "" {
+		fn(pointer, &response.Ref)
+		return
+	}
+	walkSchemaRefRefs(pointer+"/schema", response.Schema, fn)
+	for _, name := range sortedHeaderNames(response.Headers) {
+		if header := response.Headers[name]; header != nil && header.Ref != "" {
+			fn(pointer+"/headers/"+escapePointerToken(name), &header.Ref)
+		}
+	}
+}
+
+func walkOperationRefs(pointer string, operation *Operation, fn refVisitor) {
+	for i, parameter := range operation.Parameters {
+		walkParameterRefs(pointer+"/parameters/"+strconv.Itoa(i), parameter, fn)
+	}
+	for _, status := range sortedResponseKeys(operation.Responses) {
+		walkResponseRefs(pointer+"/responses/"+escapePointerToken(status), operation.Responses[status], fn)
+	}
+}
+
+func walkPathItemRefs(pointer string, pathItem *PathItem, fn refVisitor) {
+	if pathItem == nil {
+		return
+	}
+	if pathItem.Ref != "" {
+		fn(pointer, &pathItem.Ref)
+	}
+	for i, parameter := range pathItem.Parameters {
+		walkParameterRefs(pointer+"/parameters/"+strconv.Itoa(i), parameter, fn)
+	}
+	for _, method := range operationMethods {
+		if operation := pathItem.GetOperation(method); operation != nil {
+			walkOperationRefs(pointer+"/"+strings.ToLower(method), operation, fn)
+		}
+	}
+}
+
+// walkRefs calls fn for every $ref of the document, in a stable order:
+// paths, then definitions, shared parameters and shared responses.
+func (swagger *Swagger) walkRefs(fn refVisitor) {
+	for _, path := range swagger.sortedPaths() {
+		walkPathItemRefs(pathPointer(path), swagger.Paths[path], fn)
+	}
+	for _, name := range sortedSchemaNames(swagger.Definitions) {
+		walkSchemaRefRefs("/definitions/"+escapePointerToken(name), swagger.Definitions[name], fn)
+	}
+	for _, name := range sortedParameterNames(swagger.Parameters) {
+		walkParameterRefs("/parameters/"+escapePointerToken(name), swagger.Parameters[name], fn)
+	}
+	for _, name := range sortedResponseKeys(swagger.Responses) {
+		walkResponseRefs("/responses/"+escapePointerToken(name), swagger.Responses[name], fn)
+	}
+}
+
+func sortedParameterNames(parameters map[string]*Parameter) []string {
+	names := make([]string, 0, len(parameters))
+	for name := range parameters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
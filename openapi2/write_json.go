@@ -0,0 +1,89 @@
+package openapi2
+
+import (
+	"encoding/json"
+	"io"
+	"sort"
+
+	"github.com/mbilski/kin-openapi/jsoninfo"
+)
+
+// WriteJSON writes the document to w as MarshalJSON would, without building the whole encoding in memory.
+//
+// Paths, definitions, parameters and responses are encoded and written one entry at a time,
+// so the memory used is bounded by the largest of these elements rather than by the document.
+// Writes to w are small: wrap it in a bufio.Writer when that matters.
+func (swagger *Swagger) WriteJSON(w io.Writer) error {
+	// The other fields, written by MarshalJSON
+	shallow := &Swagger{
+		ExtensionProps:      swagger.ExtensionProps,
+		Info:                swagger.Info,
+		ExternalDocs:        swagger.ExternalDocs,
+		Schemes:             swagger.Schemes,
+		Host:                swagger.Host,
+		BasePath:            swagger.BasePath,
+		Consumes:            swagger.Consumes,
+		Produces:            swagger.Produces,
+		SecurityDefinitions: swagger.SecurityDefinitions,
+		Security:            swagger.Security,
+		Tags:                swagger.Tags,
+	}
+	encoder := jsoninfo.NewObjectEncoder()
+	if err := shallow.EncodeWith(encoder, shallow); err != nil {
+		return err
+	}
+	if m := swagger.Paths; len(m) > 0 {
+		keys := make([]string, 0, len(m))
+		for key := range m {
+			keys = append(keys, key)
+		}
+		encoder.EncodeStream("paths", writeJSONObject(keys, func(key string) interface{} { return m[key] }))
+	}
+	if m := swagger.Definitions; len(m) > 0 {
+		encoder.EncodeStream("definitions", writeJSONObject(sortedSchemaNames(m), func(key string) interface{} { return m[key] }))
+	}
+	if m := swagger.Parameters; len(m) > 0 {
+		encoder.EncodeStream("parameters", writeJSONObject(sortedParameterNames(m), func(key string) interface{} { return m[key] }))
+	}
+	if m := swagger.Responses; len(m) > 0 {
+		encoder.EncodeStream("responses", writeJSONObject(sortedResponseKeys(m), func(key string) interface{} { return m[key] }))
+	}
+	return encoder.Write(w)
+}
+
+// writeJSONObject returns a function writing a JSON object with the given keys, sorted,
+// encoding each value separately.
+func writeJSONObject(keys []string, value func(key string) interface{}) func(w io.Writer) error {
+	sort.Strings(keys)
+	return func(w io.Writer) error {
+		if _, err := io.WriteString(w, "{"); err != nil {
+			return err
+		}
+		for i, key := range keys {
+			if i > 0 {
+				if _, err := io.WriteString(w, ","); err != nil {
+					return err
+				}
+			}
+			keyData, err := json.Marshal(key)
+			if err != nil {
+				return err
+			}
+			valueData, err := json.Marshal(value(key))
+			if err != nil {
+				return err
+			}
+			if _, err := w.Write(keyData); err != nil {
+				return err
+			}
+			if _, err := io.WriteString(w, ":"); err != nil {
+				return err
+			}
+			if _, err := w.Write(valueData); err != nil {
+				return err
+			}
+		}
+		_, err := io.WriteString(w, "}")
+		return err
+	}
+}
@@ -0,0 +1,73 @@
+package openapi2_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"testing"
+
+	"github.com/mbilski/kin-openapi/openapi2"
+	"github.com/mbilski/kin-openapi/openapi3"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteJSON(t *testing.T) {
+	swagger := loadSwaggerFile(t, "testdata/swagger.json")
+	swagger.Extensions["x-html"] = json.RawMessage(`"<b>&</b>"`)
+	// Every field of the document is written
+	swagger.Consumes = []string{"application/json"}
+	swagger.Produces = []string{"application/json"}
+	swagger.Security = openapi2.SecurityRequirements{{"petstore_auth": {"read:pets"}}}
+	expected, err := json.Marshal(swagger)
+	require.NoError(t, err)
+	var buf bytes.Buffer
+	require.NoError(t, swagger.WriteJSON(&buf))
+	require.Equal(t, string(expected), buf.String())
+}
+
+func largeSwagger() *openapi2.Swagger {
+	swagger := &openapi2.Swagger{
+		Info:        openapi3.Info{Title: "MyAPI", Version: "0.1"},
+		Definitions: make(map[string]*openapi3.SchemaRef),
+	}
+	for i := 0; i < 2000; i++ {
+		name := fmt.Sprintf("Model%d", i)
+		swagger.Definitions[name] = &openapi3.SchemaRef{Value: openapi3.NewObjectSchema().
+			WithProperty("id", openapi3.NewInt64Schema()).
+			WithProperty("name", openapi3.NewStringSchema())}
+		swagger.AddOperation("/models/"+name, "GET", &openapi2.Operation{
+			OperationID: "get" + name,
+			Responses: map[string]*openapi2.Response{
+				"200": {Description: "ok", Schema: &openapi3.SchemaRef{Ref: "#/definitions/" + name}},
+			},
+		})
+	}
+	return swagger
+}
+
+func BenchmarkMarshalJSON(b *testing.B) {
+	swagger := largeSwagger()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		data, err := json.Marshal(swagger)
+		if err != nil {
+			b.Fatal(err)
+		}
+		if _, err := ioutil.Discard.Write(data); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkWriteJSON(b *testing.B) {
+	swagger := largeSwagger()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := swagger.WriteJSON(ioutil.Discard); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package openapi2
+
+import (
+	"bytes"
+	"strconv"
+
+	"github.com/ghodss/yaml"
+	yamlv3 "gopkg.in/yaml.v3"
+)
+
+// Comment holds the YAML comments attached to a mapping key or a sequence item.
+type Comment struct {
+	// Head holds the comment lines written above the key, with their '#'.
+	Head string `json:"head,omitempty"`
+	// Line is the comment written after the key on the same line, with its '#'.
+	Line string `json:"line,omitempty"`
+}
+
+// Comments maps the JSON pointers of the elements of a document, such as
+// "/paths/~1pets/get" or "/paths/~1pets/get/parameters/0", to their YAML comments.
+// The pointer "" holds the comment of the document, separated from its first key by a blank line.
+type Comments map[string]*Comment
+
+// MarshalYAMLWithComments returns the document as YAML, restoring swagger.Comments,
+// such as those captured by SwaggerLoader.PreserveComments.
+//
+// A comment is written back at the element with the same JSON pointer,
+// so comments of elements that no longer exist are dropped.
+// Whitespace and the order of keys of the original document aren't preserved.
+func (swagger *Swagger) MarshalYAMLWithComments() ([]byte, error) {
+	data, err := yaml.Marshal(swagger)
+	if err != nil {
+		return nil, err
+	}
+	if len(swagger.Comments) == 0 {
+		return data, nil
+	}
+	var document yamlv3.Node
+	if err := yamlv3.Unmarshal(data, &document); err != nil {
+		return nil, err
+	}
+	walkYAMLComments(&document, "", func(pointer string, head *string, line *string) {
+		if comment := swagger.Comments[pointer]; comment != nil {
+			*head, *line = comment.Head, comment.Line
+		}
+	})
+	var buf bytes.Buffer
+	encoder := yamlv3.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&document); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// readYAMLComments returns the comments attached to the mapping keys and sequence items of a YAML document,
+// and to the document itself with the pointer "".
+// Comments written after the last element of a block aren't captured.
+func readYAMLComments(data []byte) Comments {
+	var document yamlv3.Node
+	if err := yamlv3.Unmarshal(data, &document); err != nil {
+		return nil
+	}
+	comments := make(Comments)
+	walkYAMLComments(&document, "", func(pointer string, head *string, line *string) {
+		if *head != "" || *line != "" {
+			comments[pointer] = &Comment{Head: *head, Line: *line}
+		}
+	})
+	return comments
+}
+
+// walkYAMLComments calls fn with the JSON pointer of node and of each element nested in it,
+// and with the head and line comments where yaml.v3 holds them for that element.
+func walkYAMLComments(node *yamlv3.Node, pointer string, fn func(pointer string, head *string, line *string)) {
+	walkYAMLNodeComments(node, pointer, false, fn)
+}
+
+// walkYAMLNodeComments is walkYAMLComments for a node whose first key may start on the line
+// of a sequence dash, in which case the line comment belongs to the sequence item only.
+func walkYAMLNodeComments(node *yamlv3.Node, pointer string, itemLine bool, fn func(pointer string, head *string, line *string)) {
+	switch node.Kind {
+	case yamlv3.DocumentNode:
+		fn(pointer, &node.HeadComment, &node.LineComment)
+		for _, child := range node.Content {
+			walkYAMLNodeComments(child, pointer, false, fn)
+		}
+	case yamlv3.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			keyPointer := pointer + "/" + escapePointerToken(key.Value)
+			line := yamlLineComment(key, value)
+			if i == 0 && itemLine {
+				line = new(string)
+			}
+			fn(keyPointer, &key.HeadComment, line)
+			walkYAMLNodeComments(value, keyPointer, false, fn)
+		}
+	case yamlv3.SequenceNode:
+		for i, item := range node.Content {
+			itemPointer := pointer + "/" + strconv.Itoa(i)
+			line := &item.LineComment
+			blockMapping := item.Kind == yamlv3.MappingNode && len(item.Content) > 1 && item.Style&yamlv3.FlowStyle == 0
+			if blockMapping {
+				line = yamlLineComment(item.Content[0], item.Content[1])
+			}
+			fn(itemPointer, &item.HeadComment, line)
+			walkYAMLNodeComments(item, itemPointer, blockMapping, fn)
+		}
+	}
+}
+
+// yamlLineComment returns the line comment of a mapping key:
+// yaml.v3 holds it in the value when the value is a scalar on the same line, or in the key otherwise.
+func yamlLineComment(key *yamlv3.Node, value *yamlv3.Node) *string {
+	if key.LineComment == "" && (value.Kind == yamlv3.ScalarNode || value.Kind == yamlv3.AliasNode || value.Style&yamlv3.FlowStyle != 0) {
+		return &value.LineComment
+	}
+	return &key.LineComment
+}
@@ -0,0 +1,70 @@
+package openapi2_test
+
+import (
+	"testing"
+
+	"github.com/mbilski/kin-openapi/openapi2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPreserveComments(t *testing.T) {
+	spec := []byte(`# Generated, do not edit
+
+# The pet store
+swagger: "2.0"
+info:
+  title: MyAPI
+  version: "0.1"
+  description: |
+    # Not a comment
+    Lists pets.
+paths:
+  /pets:
+    # Lists every pet.
+    # Paginated.
+    get:
+      parameters:
+      # Page size
+      - name: limit # at most 100
+        in: query
+        type: integer
+      responses:
+        200: {description: list}
+`)
+	loader := openapi2.NewSwaggerLoader()
+	loader.PreserveComments = true
+	swagger, err := loader.LoadSwaggerFromData(spec)
+	require.NoError(t, err)
+	require.Equal(t, openapi2.Comments{
+		"":                               {Head: "# Generated, do not edit"},
+		"/swagger":                       {Head: "# The pet store"},
+		"/paths/~1pets/get":              {Head: "# Lists every pet.\n# Paginated."},
+		"/paths/~1pets/get/parameters/0": {Head: "# Page size", Line: "# at most 100"},
+	}, swagger.Comments)
+	require.Equal(t, "# Not a comment\nLists pets.\n", swagger.Info.Description)
+
+	data, err := swagger.MarshalYAMLWithComments()
+	require.NoError(t, err)
+	require.Contains(t, string(data), `
+paths:
+  /pets:
+    # Lists every pet.
+    # Paginated.
+    get:
+      parameters:
+        # Page size
+        - in: query # at most 100
+          name: limit
+`)
+
+	reloaded, err := loader.LoadSwaggerFromData(data)
+	require.NoError(t, err)
+	require.Equal(t, swagger.Comments, reloaded.Comments)
+	reloaded.Comments, swagger.Comments = nil, nil
+	require.Equal(t, swagger, reloaded)
+
+	// Comments are only captured when asked for
+	swagger, err = openapi2.NewSwaggerLoader().LoadSwaggerFromData(spec)
+	require.NoError(t, err)
+	require.Nil(t, swagger.Comments)
+}
@@ -0,0 +1,80 @@
+//go:build go1.16
+// +build go1.16
+
+package openapi2conv
+
+import (
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/mbilski/kin-openapi/openapi2"
+	"github.com/mbilski/kin-openapi/openapi3"
+)
+
+// MultiError holds the errors of a batch conversion, keyed by file name.
+type MultiError map[string]error
+
+func (errs MultiError) Error() string {
+	names := make([]string, 0, len(errs))
+	for name := range errs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	messages := make([]string, 0, len(names))
+	for _, name := range names {
+		messages = append(messages, name+": "+errs[name].Error())
+	}
+	return strings.Join(messages, " | ")
+}
+
+// ConvertDir converts every OpenAPI 2 document of fsys to OpenAPI 3 and passes the result to dst.
+//
+// Files with a ".json", ".yaml" or ".yml" extension are converted in lexical order, walking
+// subdirectories. A file that fails to load or convert, or for which dst fails, doesn't stop
+// the walk: the error is recorded in the returned MultiError under the file name.
+func ConvertDir(fsys fs.FS, dst func(name string, doc *openapi3.Swagger) error) error {
+	errs := make(MultiError)
+	loader := openapi2.NewSwaggerLoader()
+	walkErr := fs.WalkDir(fsys, ".", func(name string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			errs[name] = err
+			return nil
+		}
+		if entry.IsDir() {
+			return nil
+		}
+		switch strings.ToLower(path.Ext(name)) {
+		case ".json", ".yaml", ".yml":
+		default:
+			return nil
+		}
+		data, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			errs[name] = err
+			return nil
+		}
+		swagger, err := loader.LoadSwaggerFromData(data)
+		if err != nil {
+			errs[name] = err
+			return nil
+		}
+		doc, err := ToV3Swagger(swagger)
+		if err != nil {
+			errs[name] = err
+			return nil
+		}
+		if err := dst(name, doc); err != nil {
+			errs[name] = err
+		}
+		return nil
+	})
+	if walkErr != nil {
+		return walkErr
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
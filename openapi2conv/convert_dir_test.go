@@ -0,0 +1,47 @@
+//go:build go1.16
+// +build go1.16
+
+package openapi2conv_test
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/mbilski/kin-openapi/openapi2conv"
+	"github.com/mbilski/kin-openapi/openapi3"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConvertDir(t *testing.T) {
+	fsys := fstest.MapFS{
+		"specs/pets.yaml": {Data: []byte(`
+swagger: "2.0"
+info: {title: Pets, version: "1.0"}
+host: pets.example.com
+paths:
+  /pets:
+    get:
+      responses:
+        200: {description: list}
+`)},
+		"specs/broken.json": {Data: []byte(`{"info": {"title": "Broken"`)},
+		"specs/README.md":   {Data: []byte(`# Specs`)},
+	}
+	converted := make(map[string]*openapi3.Swagger)
+	err := openapi2conv.ConvertDir(fsys, func(name string, doc *openapi3.Swagger) error {
+		converted[name] = doc
+		return nil
+	})
+	require.Error(t, err)
+	require.IsType(t, openapi2conv.MultiError{}, err)
+	errs := err.(openapi2conv.MultiError)
+	require.Len(t, errs, 1)
+	require.Contains(t, errs, "specs/broken.json")
+
+	require.Len(t, converted, 1)
+	doc := converted["specs/pets.yaml"]
+	require.NotNil(t, doc)
+	require.Equal(t, "Pets", doc.Info.Title)
+	require.Equal(t, "https://pets.example.com", doc.Servers[0].URL)
+	require.NotNil(t, doc.Paths["/pets"].Get)
+}
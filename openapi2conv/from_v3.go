@@ -0,0 +1,43 @@
+package openapi2conv
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/mbilski/kin-openapi/openapi2"
+	"github.com/mbilski/kin-openapi/openapi3"
+)
+
+// FromV3 converts an OpenAPI 3 document into OpenAPI 2, like FromV3Swagger,
+// and reports the v3-only features that can't be represented in the result.
+//
+// When the conversion fails the result is nil and the returned list holds the failure.
+// Otherwise each returned error is a lossy-conversion warning, such as a dropped callback
+// or a request content type other than the one kept for the body parameter.
+func FromV3(swagger *openapi3.Swagger) (*openapi2.Swagger, []error) {
+	warnings := &lossyWarnings{}
+	result, err := fromV3Swagger(swagger, warnings)
+	if err != nil {
+		return nil, []error{err}
+	}
+	sortErrors(warnings.errs)
+	return result, warnings.errs
+}
+
+// lossyWarnings collects the lossy-conversion warnings of FromV3 as the document is converted.
+// A nil one discards them, for the conversion functions that don't report warnings.
+type lossyWarnings struct {
+	errs []error
+}
+
+func (warnings *lossyWarnings) warn(format string, args ...interface{}) {
+	if warnings != nil {
+		warnings.errs = append(warnings.errs, fmt.Errorf(format, args...))
+	}
+}
+
+func sortErrors(errs []error) {
+	sort.SliceStable(errs, func(i, j int) bool {
+		return errs[i].Error() < errs[j].Error()
+	})
+}
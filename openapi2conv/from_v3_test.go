@@ -0,0 +1,151 @@
+package openapi2conv_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/mbilski/kin-openapi/openapi2conv"
+	"github.com/mbilski/kin-openapi/openapi3"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFromV3(t *testing.T) {
+	var swagger3 openapi3.Swagger
+	err := json.Unmarshal([]byte(`
+{
+  "openapi": "3.0.2",
+  "info": {"title": "MyAPI", "version": "0.1"},
+  "servers": [{"url": "https://api.example.com/v1"}, {"url": "http://api.example.com/v1"}],
+  "paths": {
+    "/pets": {
+      "post": {
+        "requestBody": {
+          "content": {
+            "application/json": {"schema": {"$ref": "#/components/schemas/Pet"}},
+            "application/xml": {"schema": {"$ref": "#/components/schemas/Pet"}}
+          }
+        },
+        "callbacks": {
+          "onCreated": {"{$request.body#/callbackUrl}": {"post": {"responses": {"200": {"description": "ok"}}}}}
+        },
+        "responses": {"200": {"description": "ok"}}
+      }
+    },
+    "/pets/{id}/photo": {
+      "put": {
+        "requestBody": {
+          "content": {
+            "multipart/form-data": {
+              "schema": {
+                "type": "object",
+                "required": ["file"],
+                "properties": {
+                  "file": {"type": "string", "format": "binary"},
+                  "caption": {"type": "string", "maxLength": 100}
+                }
+              }
+            }
+          }
+        },
+        "responses": {"204": {"description": "stored"}}
+      }
+    }
+  },
+  "components": {
+    "schemas": {
+      "Pet": {"type": "object", "properties": {"name": {"type": "string"}}}
+    },
+    "securitySchemes": {
+      "oauth": {
+        "type": "oauth2",
+        "flows": {
+          "authorizationCode": {
+            "authorizationUrl": "https://auth.example.com/authorize",
+            "tokenUrl": "https://auth.example.com/token",
+            "scopes": {"read": "Read access"}
+          }
+        }
+      }
+    }
+  }
+}`), &swagger3)
+	require.NoError(t, err)
+
+	swagger2, warnings := openapi2conv.FromV3(&swagger3)
+	require.NotNil(t, swagger2)
+	var messages []string
+	for _, warning := range warnings {
+		messages = append(messages, warning.Error())
+	}
+	require.Equal(t, []string{
+		"Operation POST /pets: callbacks are not supported and were dropped",
+		"Operation POST /pets: request body has 2 content types, only one can be kept",
+	}, messages)
+
+	require.Equal(t, "api.example.com", swagger2.Host)
+	require.Equal(t, "/v1", swagger2.BasePath)
+	require.Equal(t, []string{"https", "http"}, swagger2.Schemes)
+
+	body := swagger2.Paths["/pets"].Post.Parameters
+	require.Len(t, body, 1)
+	require.Equal(t, "body", body[0].In)
+	require.Equal(t, "#/definitions/Pet", body[0].Schema.Ref)
+
+	upload := swagger2.Paths["/pets/{id}/photo"].Put
+	require.Equal(t, []string{"multipart/form-data"}, upload.Consumes)
+	require.Len(t, upload.Parameters, 2)
+	require.Equal(t, "caption", upload.Parameters[0].Name)
+	require.Equal(t, "formData", upload.Parameters[0].In)
+	require.Equal(t, "string", upload.Parameters[0].Type)
+	require.Equal(t, uint64(100), *upload.Parameters[0].MaxLength)
+	require.False(t, upload.Parameters[0].Required)
+	require.Equal(t, "file", upload.Parameters[1].Name)
+	require.Equal(t, "file", upload.Parameters[1].Type)
+	require.True(t, upload.Parameters[1].Required)
+
+	oauth := swagger2.SecurityDefinitions["oauth"]
+	require.Equal(t, "oauth2", oauth.Type)
+	require.Equal(t, "accessCode", oauth.Flow)
+	require.Equal(t, "https://auth.example.com/authorize", oauth.AuthorizationURL)
+	require.Equal(t, "https://auth.example.com/token", oauth.TokenURL)
+	require.Equal(t, map[string]string{"read": "Read access"}, oauth.Scopes)
+}
+
+func TestFromV3DroppedContent(t *testing.T) {
+	var swagger3 openapi3.Swagger
+	err := json.Unmarshal([]byte(`
+{
+  "openapi": "3.0.2",
+  "info": {"title": "MyAPI", "version": "0.1"},
+  "paths": {
+    "/pets": {
+      "put": {
+        "requestBody": {"content": {"application/xml": {"schema": {"type": "object"}}}},
+        "responses": {"204": {"$ref": "#/components/responses/Stored"}}
+      },
+      "post": {
+        "requestBody": {"content": {"application/x-www-form-urlencoded": {"schema": {"type": "object"}}}},
+        "responses": {"204": {"$ref": "#/components/responses/Stored"}}
+      }
+    }
+  },
+  "components": {
+    "responses": {
+      "Stored": {"description": "stored", "content": {"text/plain": {"schema": {"type": "string"}}}}
+    }
+  }
+}`), &swagger3)
+	require.NoError(t, err)
+
+	swagger2, warnings := openapi2conv.FromV3(&swagger3)
+	require.NotNil(t, swagger2)
+	var messages []string
+	for _, warning := range warnings {
+		messages = append(messages, warning.Error())
+	}
+	require.Equal(t, []string{
+		"Components: content type 'text/plain' of response 'Stored' was dropped",
+		"Operation PUT /pets: content type 'application/xml' of the request body was dropped",
+	}, messages)
+	require.Nil(t, swagger2.Paths["/pets"].Put.Parameters[0].Schema)
+}
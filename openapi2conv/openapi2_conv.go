@@ -2,46 +2,86 @@
 package openapi2conv
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/url"
+	"sort"
 	"strings"
 
 	"github.com/mbilski/kin-openapi/openapi2"
 	"github.com/mbilski/kin-openapi/openapi3"
 )
 
+// ConvertOptions configures ToV3SwaggerWithOptions.
+type ConvertOptions struct {
+	// DeriveSchemaTitles sets the title of the schema generated for a non-body parameter
+	// to the parameter name. Body parameter schemas are left untouched.
+	DeriveSchemaTitles bool
+	// DedupeOperationIDs renames the operations that repeat the operationId of an earlier one,
+	// by path then method, with a numeric suffix such as "getUser_2".
+	// ToV3WithOptions reports each renaming as a WarningOperationID.
+	DedupeOperationIDs bool
+	// OptionalAsNullable marks the properties of a schema that aren't in its "required" list
+	// as "nullable", which some client generators need to tell absent values apart.
+	// Properties required by the schema, or by the schema it's an inline "allOf" member of
+	// or another such member, are left alone. So are $ref properties, which can't carry
+	// "nullable" in OpenAPI 3.0: the referenced definition is converted on its own.
+	OptionalAsNullable bool
+	// StrictRefs makes conversion fail with a *RefError on the first local $ref, by pointer,
+	// that doesn't resolve within the OpenAPI 2 document, such as "#/definitions/Missing".
+	// Without it, such references are converted as they are, and ToV3WithOptions
+	// reports each of them as a WarningDanglingRef.
+	// References to other documents aren't checked.
+	StrictRefs bool
+	// TargetVersion is the OpenAPI 3 minor version to convert to, "3.0" (the default) or "3.1".
+	// The openapi3 types only describe OpenAPI 3.0, so converting to "3.1" is done by ToV3JSON,
+	// which writes nullable schemas with a "null" type, and exclusive bounds as numbers.
+	TargetVersion string
+}
+
 func ToV3Swagger(swagger *openapi2.Swagger) (*openapi3.Swagger, error) {
+	return ToV3SwaggerWithOptions(swagger, ConvertOptions{})
+}
+
+// ToV3SwaggerWithOptions is like ToV3Swagger, configured by the given options.
+// The OpenAPI 2 document isn't modified: the schemas are converted on copies.
+func ToV3SwaggerWithOptions(swagger *openapi2.Swagger, opts ConvertOptions) (*openapi3.Swagger, error) {
+	switch opts.TargetVersion {
+	case "", TargetVersion30:
+	case TargetVersion31:
+		return nil, fmt.Errorf("Target version '%s' can't be represented by openapi3.Swagger, use ToV3JSON", opts.TargetVersion)
+	default:
+		return nil, unsupportedTargetVersion(opts.TargetVersion)
+	}
+	if opts.StrictRefs {
+		refErrs, err := danglingRefs(swagger)
+		if err != nil {
+			return nil, err
+		}
+		if len(refErrs) > 0 {
+			return nil, refErrs[0]
+		}
+	}
 	result := &openapi3.Swagger{
 		OpenAPI:    "3.0.2",
 		Info:       &swagger.Info,
 		Components: openapi3.Components{},
 		Tags:       swagger.Tags,
 	}
-	host := swagger.Host
-	if len(host) > 0 {
-		schemes := swagger.Schemes
-		if len(schemes) == 0 {
-			schemes = []string{
-				"https://",
-			}
-		}
-		basePath := swagger.BasePath
-		for _, scheme := range schemes {
-			u := url.URL{
-				Scheme: scheme,
-				Host:   host,
-				Path:   basePath,
-			}
-			result.AddServer(&openapi3.Server{
-				URL: u.String(),
-			})
-		}
+	serverURLs, err := swagger.ServerURLs()
+	if err != nil {
+		return nil, err
+	}
+	for _, serverURL := range serverURLs {
+		result.AddServer(&openapi3.Server{
+			URL: serverURL,
+		})
 	}
 	if paths := swagger.Paths; paths != nil {
 		resultPaths := make(map[string]*openapi3.PathItem, len(paths))
 		for path, pathItem := range paths {
-			r, err := ToV3PathItem(swagger, pathItem)
+			r, err := toV3PathItem(swagger, pathItem, opts)
 			if err != nil {
 				return nil, err
 			}
@@ -53,7 +93,7 @@ func ToV3Swagger(swagger *openapi2.Swagger) (*openapi3.Swagger, error) {
 		result.Components.Parameters = make(map[string]*openapi3.ParameterRef)
 		result.Components.RequestBodies = make(map[string]*openapi3.RequestBodyRef)
 		for k, parameter := range parameters {
-			resultParameter, resultRequestBody, err := ToV3Parameter(parameter)
+			resultParameter, resultRequestBody, err := toV3Parameter(parameter, opts)
 			if err != nil {
 				return nil, err
 			}
@@ -68,18 +108,24 @@ func ToV3Swagger(swagger *openapi2.Swagger) (*openapi3.Swagger, error) {
 	if responses := swagger.Responses; responses != nil {
 		result.Components.Responses = make(map[string]*openapi3.ResponseRef, len(responses))
 		for k, response := range responses {
-			r, err := ToV3Response(response)
+			r, err := toV3Response(response, swagger.Produces, opts)
 			if err != nil {
 				return nil, err
 			}
 			result.Components.Responses[k] = r
 		}
 	}
-	result.Components.Schemas = ToV3Schemas(swagger.Definitions)
+	result.Components.Schemas = toV3Schemas(swagger.Definitions, opts)
 	if m := swagger.SecurityDefinitions; m != nil {
 		resultSecuritySchemes := make(map[string]*openapi3.SecuritySchemeRef)
-		for k, v := range m {
-			r, err := ToV3SecurityScheme(v)
+		// Converting in name order makes the reported error reproducible
+		names := make([]string, 0, len(m))
+		for name := range m {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, k := range names {
+			r, err := ToV3SecurityScheme(m[k])
 			if err != nil {
 				return nil, err
 			}
@@ -88,20 +134,29 @@ func ToV3Swagger(swagger *openapi2.Swagger) (*openapi3.Swagger, error) {
 		result.Components.SecuritySchemes = resultSecuritySchemes
 	}
 	result.Security = ToV3SecurityRequirements(swagger.Security)
+	if opts.DedupeOperationIDs {
+		for _, renaming := range operationIDRenamings(swagger) {
+			result.Paths[renaming.path].GetOperation(renaming.method).OperationID = renaming.id
+		}
+	}
 	return result, nil
 }
 
 func ToV3PathItem(swagger *openapi2.Swagger, pathItem *openapi2.PathItem) (*openapi3.PathItem, error) {
+	return toV3PathItem(swagger, pathItem, ConvertOptions{})
+}
+
+func toV3PathItem(swagger *openapi2.Swagger, pathItem *openapi2.PathItem, opts ConvertOptions) (*openapi3.PathItem, error) {
 	result := &openapi3.PathItem{}
 	for method, operation := range pathItem.Operations() {
-		resultOperation, err := ToV3Operation(swagger, pathItem, operation)
+		resultOperation, err := toV3Operation(swagger, pathItem, operation, opts)
 		if err != nil {
 			return nil, err
 		}
 		result.SetOperation(method, resultOperation)
 	}
 	for _, parameter := range pathItem.Parameters {
-		v3Parameter, v3RequestBody, err := ToV3Parameter(parameter)
+		v3Parameter, v3RequestBody, err := toV3Parameter(parameter, opts)
 		if err != nil {
 			return nil, err
 		}
@@ -113,7 +168,15 @@ func ToV3PathItem(swagger *openapi2.Swagger, pathItem *openapi2.PathItem) (*open
 	return result, nil
 }
 
+// ToV3Operation converts an operation.
+// Its "x-servers" extension, or else its "x-basePath" extension, becomes the servers of the operation,
+// overriding those of the document. The servers of "x-servers" are OpenAPI 3 server objects or URLs,
+// and those of "x-basePath" have the schemes and host of the document.
 func ToV3Operation(swagger *openapi2.Swagger, pathItem *openapi2.PathItem, operation *openapi2.Operation) (*openapi3.Operation, error) {
+	return toV3Operation(swagger, pathItem, operation, ConvertOptions{})
+}
+
+func toV3Operation(swagger *openapi2.Swagger, pathItem *openapi2.PathItem, operation *openapi2.Operation, opts ConvertOptions) (*openapi3.Operation, error) {
 	if operation == nil {
 		return nil, nil
 	}
@@ -127,8 +190,13 @@ func ToV3Operation(swagger *openapi2.Swagger, pathItem *openapi2.PathItem, opera
 		resultSecurity := ToV3SecurityRequirements(*v)
 		result.Security = &resultSecurity
 	}
+	servers, err := toV3OperationServers(swagger, operation)
+	if err != nil {
+		return nil, err
+	}
+	result.Servers = servers
 	for _, parameter := range operation.Parameters {
-		v3Parameter, v3RequestBody, err := ToV3Parameter(parameter)
+		v3Parameter, v3RequestBody, err := toV3Parameter(parameter, opts)
 		if err != nil {
 			return nil, err
 		}
@@ -139,9 +207,13 @@ func ToV3Operation(swagger *openapi2.Swagger, pathItem *openapi2.PathItem, opera
 		}
 	}
 	if responses := operation.Responses; responses != nil {
+		produces := operation.Produces
+		if len(produces) == 0 {
+			produces = swagger.Produces
+		}
 		resultResponses := make(openapi3.Responses, len(responses))
 		for k, response := range responses {
-			result, err := ToV3Response(response)
+			result, err := toV3Response(response, produces, opts)
 			if err != nil {
 				return nil, err
 			}
@@ -152,29 +224,40 @@ func ToV3Operation(swagger *openapi2.Swagger, pathItem *openapi2.PathItem, opera
 	return result, nil
 }
 
+// ToV3Parameter converts a parameter, or a "body" parameter into a request body.
+// The extensions of the parameter, such as "x-codegen-request-body-name" which names
+// the body for code generators, are copied onto the resulting parameter or request body,
+// but for the "x-examples" or "x-example" of a non-body parameter, which become its examples.
 func ToV3Parameter(parameter *openapi2.Parameter) (*openapi3.ParameterRef, *openapi3.RequestBodyRef, error) {
+	return toV3Parameter(parameter, ConvertOptions{})
+}
+
+func toV3Parameter(parameter *openapi2.Parameter, opts ConvertOptions) (*openapi3.ParameterRef, *openapi3.RequestBodyRef, error) {
 	if parameter == nil {
 		return nil, nil, nil
 	}
 	in := parameter.In
 	if in == "body" {
 		result := &openapi3.RequestBody{
-			Description: parameter.Description,
-			Required:    parameter.Required,
+			ExtensionProps: openapi3.ExtensionProps{Extensions: copyExtensions(parameter.Extensions)},
+			Description:    parameter.Description,
+			Required:       parameter.Required,
 		}
 		if schemaRef := parameter.Schema; schemaRef != nil {
 			// Assume it's JSON
-			result.WithJSONSchemaRef(ToV3SchemaRef(schemaRef))
+			result.WithJSONSchemaRef(toV3SchemaRef(schemaRef, opts, nil))
 		}
 		return nil, &openapi3.RequestBodyRef{
 			Value: result,
 		}, nil
 	}
 	result := &openapi3.Parameter{
-		In:          in,
-		Name:        parameter.Name,
-		Description: parameter.Description,
-		Required:    parameter.Required,
+		ExtensionProps:  openapi3.ExtensionProps{Extensions: copyExtensions(parameter.Extensions)},
+		In:              in,
+		Name:            parameter.Name,
+		Description:     parameter.Description,
+		Required:        parameter.Required,
+		AllowEmptyValue: parameter.AllowEmptyValue,
 	}
 
 	if parameter.Type != "" {
@@ -185,6 +268,7 @@ func ToV3Parameter(parameter *openapi2.Parameter) (*openapi3.ParameterRef, *open
 				Enum:         parameter.Enum,
 				Min:          parameter.Minimum,
 				Max:          parameter.Maximum,
+				MultipleOf:   parameter.MultipleOf,
 				ExclusiveMin: parameter.ExclusiveMin,
 				ExclusiveMax: parameter.ExclusiveMax,
 				MinLength:    parameter.MinLength,
@@ -195,14 +279,29 @@ func ToV3Parameter(parameter *openapi2.Parameter) (*openapi3.ParameterRef, *open
 				MaxItems:     parameter.MaxItems,
 			},
 		}
-		result.Schema = ToV3SchemaRef(schema)
+		if opts.DeriveSchemaTitles {
+			schema.Value.Title = parameter.Name
+		}
+		result.Schema = toV3SchemaRef(schema, opts, nil)
+	}
+	if parameter.Type == "array" {
+		result.Style, result.Explode = toV3CollectionFormat(in, parameter.CollectionFormat)
 	}
+	toV3ParameterExamples(result)
 	return &openapi3.ParameterRef{
 		Value: result,
 	}, nil, nil
 }
 
 func ToV3Response(response *openapi2.Response) (*openapi3.ResponseRef, error) {
+	return toV3Response(response, nil, ConvertOptions{})
+}
+
+// toV3Response converts a response, creating a content entry for each produced media type.
+// When nothing is produced, the schema is assumed to describe JSON,
+// except for a "file" schema which becomes "application/octet-stream" binary content.
+// Examples are attached to the content entry of their media type, which is created if needed.
+func toV3Response(response *openapi2.Response, produces []string, opts ConvertOptions) (*openapi3.ResponseRef, error) {
 	if ref := response.Ref; len(ref) > 0 {
 		return &openapi3.ResponseRef{
 			Ref: ToV3Ref(ref),
@@ -211,8 +310,44 @@ func ToV3Response(response *openapi2.Response) (*openapi3.ResponseRef, error) {
 	result := &openapi3.Response{
 		Description: response.Description,
 	}
-	if schemaRef := response.Schema; schemaRef != nil {
-		result.WithJSONSchemaRef(ToV3SchemaRef(schemaRef))
+	var schemaRef *openapi3.SchemaRef
+	if response.Schema != nil {
+		if isFileSchema(response.Schema) {
+			// A file download has binary content
+			schemaRef = &openapi3.SchemaRef{Value: &openapi3.Schema{
+				Type:        "string",
+				Format:      "binary",
+				Description: response.Schema.Value.Description,
+			}}
+			if len(produces) == 0 {
+				produces = []string{"application/octet-stream"}
+			}
+		} else {
+			schemaRef = toV3SchemaRef(response.Schema, opts, nil)
+		}
+		if len(produces) == 0 {
+			produces = []string{"application/json"}
+		}
+		content := make(openapi3.Content, len(produces))
+		for _, mediaType := range produces {
+			content[mediaType] = &openapi3.MediaType{
+				Schema: schemaRef,
+			}
+		}
+		result.Content = content
+	}
+	for mediaType, example := range response.Examples {
+		if result.Content == nil {
+			result.Content = make(openapi3.Content, len(response.Examples))
+		}
+		content := result.Content[mediaType]
+		if content == nil {
+			content = &openapi3.MediaType{
+				Schema: schemaRef,
+			}
+			result.Content[mediaType] = content
+		}
+		content.Example = example
 	}
 	return &openapi3.ResponseRef{
 		Value: result,
@@ -220,14 +355,24 @@ func ToV3Response(response *openapi2.Response) (*openapi3.ResponseRef, error) {
 }
 
 func ToV3Schemas(defs map[string]*openapi3.SchemaRef) map[string]*openapi3.SchemaRef {
+	return toV3Schemas(defs, ConvertOptions{})
+}
+
+func toV3Schemas(defs map[string]*openapi3.SchemaRef, opts ConvertOptions) map[string]*openapi3.SchemaRef {
 	schemas := make(map[string]*openapi3.SchemaRef, len(defs))
 	for name, schema := range defs {
-		schemas[name] = ToV3SchemaRef(schema)
+		schemas[name] = toV3SchemaRef(schema, opts, nil)
 	}
 	return schemas
 }
 
 func ToV3SchemaRef(schema *openapi3.SchemaRef) *openapi3.SchemaRef {
+	return toV3SchemaRef(schema, ConvertOptions{}, nil)
+}
+
+// toV3SchemaRef converts a schema and the schemas nested in it.
+// inheritedRequired lists the properties required by the schemas it's an inline "allOf" member with.
+func toV3SchemaRef(schema *openapi3.SchemaRef, opts ConvertOptions, inheritedRequired []string) *openapi3.SchemaRef {
 	if ref := schema.Ref; len(ref) > 0 {
 		return &openapi3.SchemaRef{
 			Ref: ToV3Ref(ref),
@@ -236,23 +381,108 @@ func ToV3SchemaRef(schema *openapi3.SchemaRef) *openapi3.SchemaRef {
 	if schema.Value == nil {
 		return schema
 	}
+	// The OpenAPI 2 schema may be shared, and is left as it is
+	schema = copySchemaRef(schema)
 	if schema.Value.Items != nil {
-		schema.Value.Items = ToV3SchemaRef(schema.Value.Items)
+		schema.Value.Items = toV3SchemaRef(schema.Value.Items, opts, nil)
 	}
 	for k, v := range schema.Value.Properties {
-		schema.Value.Properties[k] = ToV3SchemaRef(v)
+		schema.Value.Properties[k] = toV3SchemaRef(v, opts, nil)
 	}
 	if schema.Value.AdditionalProperties != nil {
-		schema.Value.AdditionalProperties = ToV3SchemaRef(schema.Value.AdditionalProperties)
+		schema.Value.AdditionalProperties = toV3SchemaRef(schema.Value.AdditionalProperties, opts, nil)
+	}
+	if schema.Value.Not != nil {
+		schema.Value.Not = toV3SchemaRef(schema.Value.Not, opts, nil)
+	}
+	// The members of an allOf describe one value, so a property required by one of them is required
+	allOfRequired := append(append([]string{}, schema.Value.Required...), inheritedRequired...)
+	for _, member := range schema.Value.AllOf {
+		if member != nil && member.Ref == "" && member.Value != nil {
+			allOfRequired = append(allOfRequired, member.Value.Required...)
+		}
+	}
+	for i, v := range schema.Value.AllOf {
+		schema.Value.AllOf[i] = toV3SchemaRef(v, opts, allOfRequired)
+	}
+	for _, schemaRefs := range [][]*openapi3.SchemaRef{schema.Value.AnyOf, schema.Value.OneOf} {
+		for i, v := range schemaRefs {
+			schemaRefs[i] = toV3SchemaRef(v, opts, nil)
+		}
+	}
+	if opts.OptionalAsNullable {
+		markOptionalNullable(schema.Value, append(append([]string{}, schema.Value.Required...), inheritedRequired...))
 	}
+	if discriminator := schema.Value.Discriminator; discriminator != nil {
+		// Written in the OpenAPI 3 form, even when read in the OpenAPI 2 form
+		schema.Value.Discriminator = &openapi3.Discriminator{
+			ExtensionProps: discriminator.ExtensionProps,
+			PropertyName:   discriminator.PropertyName,
+			Mapping:        discriminator.Mapping,
+		}
+	}
+	schema.Value.Required = dedupeStrings(schema.Value.Required)
 	return schema
 }
 
+// copySchemaRef returns a copy of an inline schema, with copies of the collections
+// that converting it modifies.
+func copySchemaRef(schemaRef *openapi3.SchemaRef) *openapi3.SchemaRef {
+	schema := *schemaRef.Value
+	if schema.Properties != nil {
+		schema.Properties = make(map[string]*openapi3.SchemaRef, len(schemaRef.Value.Properties))
+		for name, property := range schemaRef.Value.Properties {
+			schema.Properties[name] = property
+		}
+	}
+	schema.Required = append([]string(nil), schema.Required...)
+	schema.AllOf = append([]*openapi3.SchemaRef(nil), schema.AllOf...)
+	schema.AnyOf = append([]*openapi3.SchemaRef(nil), schema.AnyOf...)
+	schema.OneOf = append([]*openapi3.SchemaRef(nil), schema.OneOf...)
+	return &openapi3.SchemaRef{Value: &schema}
+}
+
+// markOptionalNullable sets "nullable" on the inline properties of a converted schema
+// that aren't required, as described by ConvertOptions.OptionalAsNullable.
+func markOptionalNullable(schema *openapi3.Schema, required []string) {
+	requiredNames := make(map[string]struct{}, len(required))
+	for _, name := range required {
+		requiredNames[name] = struct{}{}
+	}
+	for name, property := range schema.Properties {
+		if _, ok := requiredNames[name]; ok || property == nil || property.Ref != "" || property.Value == nil {
+			continue
+		}
+		property.Value.Nullable = true
+	}
+}
+
+// isFileSchema reports whether a response schema is the OpenAPI 2 "file" type.
+func isFileSchema(schemaRef *openapi3.SchemaRef) bool {
+	return schemaRef.Ref == "" && schemaRef.Value != nil && schemaRef.Value.Type == "file"
+}
+
 var ref2To3 = map[string]string{
 	"#/definitions/": "#/components/schemas/",
 	"#/responses/":   "#/components/responses/",
 }
 
+// dedupeStrings removes the repeated values of a list, keeping the first occurrences in order.
+func dedupeStrings(values []string) []string {
+	if len(values) < 2 {
+		return values
+	}
+	seen := make(map[string]struct{}, len(values))
+	result := values[:0]
+	for _, value := range values {
+		if _, ok := seen[value]; !ok {
+			seen[value] = struct{}{}
+			result = append(result, value)
+		}
+	}
+	return result
+}
+
 func ToV3Ref(ref string) string {
 	for old, new := range ref2To3 {
 		if strings.HasPrefix(ref, old) {
@@ -316,10 +546,12 @@ func ToV3SecurityScheme(securityScheme *openapi2.SecurityScheme) (*openapi3.Secu
 		switch securityScheme.Flow {
 		case "implicit":
 			flows.Implicit = flow
-		case "accesscode":
+		case "accessCode", "accesscode":
 			flows.AuthorizationCode = flow
 		case "password":
 			flows.Password = flow
+		case "application":
+			flows.ClientCredentials = flow
 		default:
 			return nil, fmt.Errorf("Unsupported flow '%s'", securityScheme.Flow)
 		}
@@ -331,7 +563,11 @@ func ToV3SecurityScheme(securityScheme *openapi2.SecurityScheme) (*openapi3.Secu
 }
 
 func FromV3Swagger(swagger *openapi3.Swagger) (*openapi2.Swagger, error) {
-	resultResponses, err := FromV3Responses(swagger.Components.Responses)
+	return fromV3Swagger(swagger, nil)
+}
+
+func fromV3Swagger(swagger *openapi3.Swagger, warnings *lossyWarnings) (*openapi2.Swagger, error) {
+	resultResponses, err := fromV3Responses(swagger.Components.Responses, "Components", warnings)
 	if err != nil {
 		return nil, err
 	}
@@ -345,19 +581,26 @@ func FromV3Swagger(swagger *openapi3.Swagger) (*openapi2.Swagger, error) {
 	isHTTP := false
 	servers := swagger.Servers
 	for i, server := range servers {
+		if len(server.Variables) > 0 {
+			warnings.warn("Server '%s': variables are not supported, the URL is kept verbatim", server.URL)
+		}
 		parsedURL, err := url.Parse(server.URL)
-		if err == nil {
-			// See which schemes seem to be supported
-			if parsedURL.Scheme == "https" {
-				isHTTPS = true
-			} else if parsedURL.Scheme == "http" {
-				isHTTP = true
-			}
-			// The first server is assumed to provide the base path
-			if i == 0 {
-				result.Host = parsedURL.Host
-				result.BasePath = parsedURL.Path
-			}
+		if err != nil {
+			warnings.warn("Server '%s': invalid URL is dropped", server.URL)
+			continue
+		}
+		// See which schemes seem to be supported
+		if parsedURL.Scheme == "https" {
+			isHTTPS = true
+		} else if parsedURL.Scheme == "http" {
+			isHTTP = true
+		}
+		// The first server is assumed to provide the base path
+		if i == 0 {
+			result.Host = parsedURL.Host
+			result.BasePath = parsedURL.Path
+		} else if parsedURL.Host != result.Host || parsedURL.Path != result.BasePath {
+			warnings.warn("Server '%s': only the host and base path of the first server are kept", server.URL)
 		}
 	}
 	if isHTTPS {
@@ -374,7 +617,8 @@ func FromV3Swagger(swagger *openapi3.Swagger) (*openapi2.Swagger, error) {
 			if operation == nil {
 				continue
 			}
-			resultOperation, err := FromV3Operation(swagger, operation)
+			where := fmt.Sprintf("Operation %s %s", method, path)
+			resultOperation, err := fromV3Operation(swagger, operation, where, warnings)
 			if err != nil {
 				return nil, err
 			}
@@ -383,8 +627,13 @@ func FromV3Swagger(swagger *openapi3.Swagger) (*openapi2.Swagger, error) {
 	}
 	if m := swagger.Components.SecuritySchemes; m != nil {
 		resultSecuritySchemes := make(map[string]*openapi2.SecurityScheme)
-		for id, securityScheme := range m {
-			v, err := FromV3SecurityScheme(swagger, securityScheme)
+		ids := make([]string, 0, len(m))
+		for id := range m {
+			ids = append(ids, id)
+		}
+		sort.Strings(ids)
+		for _, id := range ids {
+			v, err := fromV3SecurityScheme(swagger, id, m[id], warnings)
 			if err != nil {
 				return nil, err
 			}
@@ -422,6 +671,14 @@ func FromV3SchemaRef(schema *openapi3.SchemaRef) *openapi3.SchemaRef {
 	if schema.Value.AdditionalProperties != nil {
 		schema.Value.AdditionalProperties = FromV3SchemaRef(schema.Value.AdditionalProperties)
 	}
+	if schema.Value.Not != nil {
+		schema.Value.Not = FromV3SchemaRef(schema.Value.Not)
+	}
+	for _, schemaRefs := range [][]*openapi3.SchemaRef{schema.Value.AllOf, schema.Value.AnyOf, schema.Value.OneOf} {
+		for i, v := range schemaRefs {
+			schemaRefs[i] = FromV3SchemaRef(v)
+		}
+	}
 	return schema
 }
 
@@ -455,6 +712,81 @@ func FromV3PathItem(swagger *openapi3.Swagger, pathItem *openapi3.PathItem) (*op
 	return result, nil
 }
 
+// requestBodyName returns the name given to a request body by the "x-codegen-request-body-name"
+// extension, or "" if it's missing or already used by a parameter of the operation.
+func requestBodyName(requestBody *openapi3.RequestBody, operation *openapi3.Operation) string {
+	value, ok := requestBody.Extensions[requestBodyNameExtension]
+	if !ok {
+		return ""
+	}
+	var name string
+	if err := decodeExtension(value, &name); err != nil {
+		return ""
+	}
+	for _, parameterRef := range operation.Parameters {
+		if parameter := parameterRef.Value; parameter != nil && parameter.Name == name {
+			return ""
+		}
+	}
+	return name
+}
+
+// Extensions carrying the examples of a non-body parameter, which OpenAPI 2 has no property for.
+const (
+	// exampleExtension is a single example value
+	exampleExtension = "x-example"
+	// examplesExtension is an object of example values by name
+	examplesExtension = "x-examples"
+)
+
+// toV3ParameterExamples moves the examples of a parameter from its extensions to its examples,
+// keeping their names, or to its example for a single "x-example".
+// "x-examples" wins when both are present, and the other extension is kept as it is.
+// An "x-examples" extension that isn't an object is ignored.
+func toV3ParameterExamples(parameter *openapi3.Parameter) {
+	if value, ok := parameter.Extensions[examplesExtension]; ok {
+		var examples map[string]interface{}
+		if decodeExtension(value, &examples) == nil && examples != nil {
+			parameter.Examples = make(map[string]*openapi3.ExampleRef, len(examples))
+			for name, example := range examples {
+				parameter.Examples[name] = &openapi3.ExampleRef{Value: openapi3.NewExample(example)}
+			}
+			delete(parameter.Extensions, examplesExtension)
+			return
+		}
+	}
+	if value, ok := parameter.Extensions[exampleExtension]; ok {
+		var example interface{}
+		if err := decodeExtension(value, &example); err != nil {
+			return
+		}
+		parameter.Example = example
+		delete(parameter.Extensions, exampleExtension)
+	}
+}
+
+// copyExtensions returns a copy of the extensions of an element, for the element it's converted to.
+func copyExtensions(extensions map[string]interface{}) map[string]interface{} {
+	if len(extensions) == 0 {
+		return nil
+	}
+	result := make(map[string]interface{}, len(extensions))
+	for k, v := range extensions {
+		result[k] = v
+	}
+	return result
+}
+
+// decodeExtension decodes the value of an extension into target.
+// Extensions hold raw JSON when decoded, and any value when set in code.
+func decodeExtension(value interface{}, target interface{}) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, target)
+}
+
 func findNameForRequestBody(operation *openapi3.Operation) string {
 nameSearch:
 	for _, name := range attemptedBodyParameterNames {
@@ -470,9 +802,20 @@ nameSearch:
 }
 
 func FromV3Operation(swagger *openapi3.Swagger, operation *openapi3.Operation) (*openapi2.Operation, error) {
+	return fromV3Operation(swagger, operation, "", nil)
+}
+
+// fromV3Operation converts an operation, reporting what it drops as the operation described by where.
+func fromV3Operation(swagger *openapi3.Swagger, operation *openapi3.Operation, where string, warnings *lossyWarnings) (*openapi2.Operation, error) {
 	if operation == nil {
 		return nil, nil
 	}
+	if len(operation.Callbacks) > 0 {
+		warnings.warn("%s: callbacks are not supported and were dropped", where)
+	}
+	if operation.Servers != nil && len(*operation.Servers) > 0 {
+		warnings.warn("%s: operation servers are not supported and were dropped", where)
+	}
 	result := &openapi2.Operation{
 		OperationID: operation.OperationID,
 		Summary:     operation.Summary,
@@ -484,6 +827,9 @@ func FromV3Operation(swagger *openapi3.Swagger, operation *openapi3.Operation) (
 		result.Security = &resultSecurity
 	}
 	for _, parameter := range operation.Parameters {
+		if parameter.Value != nil && parameter.Value.In == openapi3.ParameterInCookie {
+			warnings.warn("%s: cookie parameter '%s' is not supported", where, parameter.Value.Name)
+		}
 		r, err := FromV3Parameter(parameter)
 		if err != nil {
 			return nil, err
@@ -491,14 +837,30 @@ func FromV3Operation(swagger *openapi3.Swagger, operation *openapi3.Operation) (
 		result.Parameters = append(result.Parameters, r)
 	}
 	if v := operation.RequestBody; v != nil {
-		r, err := FromV3RequestBody(swagger, operation, v)
-		if err != nil {
-			return nil, err
+		kept := "application/json"
+		if formData, consumes := FromV3RequestBodyFormData(v); formData != nil {
+			result.Parameters = append(result.Parameters, formData...)
+			result.Consumes = []string{consumes}
+			kept = consumes
+		} else {
+			r, err := FromV3RequestBody(swagger, operation, v)
+			if err != nil {
+				return nil, err
+			}
+			result.Parameters = append(result.Parameters, r)
+		}
+		if requestBody := v.Value; v.Ref == "" && requestBody != nil {
+			if content := requestBody.Content; len(content) > 1 {
+				warnings.warn("%s: request body has %d content types, only one can be kept", where, len(content))
+			} else if _, ok := content[kept]; !ok && len(content) == 1 {
+				for mediaType := range content {
+					warnings.warn("%s: content type '%s' of the request body was dropped", where, mediaType)
+				}
+			}
 		}
-		result.Parameters = append(result.Parameters, r)
 	}
 	if responses := operation.Responses; responses != nil {
-		resultResponses, err := FromV3Responses(responses)
+		resultResponses, err := fromV3Responses(responses, where, warnings)
 		if err != nil {
 			return nil, err
 		}
@@ -507,6 +869,12 @@ func FromV3Operation(swagger *openapi3.Swagger, operation *openapi3.Operation) (
 	return result, nil
 }
 
+// requestBodyNameExtension names the body parameter of an operation for code generators.
+const requestBodyNameExtension = "x-codegen-request-body-name"
+
+// FromV3RequestBody converts a request body into a "body" parameter, copying its extensions.
+// The parameter takes the name given by the "x-codegen-request-body-name" extension
+// when no other parameter of the operation has it.
 func FromV3RequestBody(swagger *openapi3.Swagger, operation *openapi3.Operation, requestBodyRef *openapi3.RequestBodyRef) (*openapi2.Parameter, error) {
 	if ref := requestBodyRef.Ref; len(ref) > 0 {
 		return &openapi2.Parameter{
@@ -516,23 +884,27 @@ func FromV3RequestBody(swagger *openapi3.Swagger, operation *openapi3.Operation,
 	requestBody := requestBodyRef.Value
 
 	// Find parameter name that we can use for the body
-	name := findNameForRequestBody(operation)
+	name := requestBodyName(requestBody, operation)
+	if name == "" {
+		name = findNameForRequestBody(operation)
+	}
 
 	// If found an available name
 	if name == "" {
 		return nil, errors.New("Could not find a name for request body")
 	}
 	result := &openapi2.Parameter{
-		In:          "body",
-		Name:        name,
-		Description: requestBody.Description,
-		Required:    requestBody.Required,
+		ExtensionProps: openapi2.ExtensionProps{Extensions: copyExtensions(requestBody.Extensions)},
+		In:             "body",
+		Name:           name,
+		Description:    requestBody.Description,
+		Required:       requestBody.Required,
 	}
 
 	// Add JSON schema
 	mediaType := requestBody.GetMediaType("application/json")
-	if mediaType != nil {
-		result.Schema = mediaType.Schema
+	if mediaType != nil && mediaType.Schema != nil {
+		result.Schema = FromV3SchemaRef(mediaType.Schema)
 	}
 	return result, nil
 }
@@ -548,36 +920,148 @@ func FromV3Parameter(ref *openapi3.ParameterRef) (*openapi2.Parameter, error) {
 		return nil, nil
 	}
 	result := &openapi2.Parameter{
-		Description: parameter.Description,
-		In:          parameter.In,
-		Name:        parameter.Name,
-		Required:    parameter.Required,
+		ExtensionProps:  openapi2.ExtensionProps{Extensions: copyExtensions(parameter.Extensions)},
+		Description:     parameter.Description,
+		In:              parameter.In,
+		Name:            parameter.Name,
+		Required:        parameter.Required,
+		AllowEmptyValue: parameter.AllowEmptyValue,
 	}
 	if schemaRef := parameter.Schema; schemaRef != nil {
 		schemaRef = FromV3SchemaRef(schemaRef)
-		schema := schemaRef.Value
-		result.Type = schema.Type
-		result.Format = schema.Format
-		result.Enum = schema.Enum
-		result.Minimum = schema.Min
-		result.Maximum = schema.Max
-		result.ExclusiveMin = schema.ExclusiveMin
-		result.ExclusiveMax = schema.ExclusiveMax
-		result.MinLength = schema.MinLength
-		result.MaxLength = schema.MaxLength
-		result.Pattern = schema.Pattern
-		result.Default = schema.Default
-		result.Items = schema.Items
-		result.MinItems = schema.MinItems
-		result.MaxItems = schema.MaxItems
+		fromV3ParameterSchema(result, schemaRef.Value)
+	}
+	if result.Type == "array" {
+		result.CollectionFormat = fromV3CollectionFormat(parameter)
 	}
 	return result, nil
 }
 
+// toV3CollectionFormat returns the v3 style and explode of an array parameter
+// with the given v2 collectionFormat.
+// A query parameter in "csv", or without a collectionFormat since "csv" is the v2 default,
+// is "form" without explode. The defaults are returned for the other "csv" parameters,
+// and for "tsv" which has no v3 equivalent.
+func toV3CollectionFormat(in string, collectionFormat string) (string, *bool) {
+	explode := func(v bool) *bool { return &v }
+	switch collectionFormat {
+	case "ssv":
+		return openapi3.SerializationSpaceDelimited, explode(false)
+	case "pipes":
+		return openapi3.SerializationPipeDelimited, explode(false)
+	case "multi":
+		return openapi3.SerializationForm, explode(true)
+	case "", "csv":
+		if in == openapi3.ParameterInQuery {
+			return openapi3.SerializationForm, explode(false)
+		}
+	}
+	return "", nil
+}
+
+// fromV3CollectionFormat returns the v2 collectionFormat of a v3 array parameter.
+// Only an explicit style is converted, a missing style keeps the v2 default.
+func fromV3CollectionFormat(parameter *openapi3.Parameter) string {
+	switch parameter.Style {
+	case openapi3.SerializationSpaceDelimited:
+		return "ssv"
+	case openapi3.SerializationPipeDelimited:
+		return "pipes"
+	case openapi3.SerializationForm:
+		if explode := parameter.Explode; explode != nil && !*explode {
+			return "csv"
+		}
+		// "form" style explodes by default
+		return "multi"
+	}
+	return ""
+}
+
+// fromV3ParameterSchema copies the constraints of a v3 schema onto a non-body v2 parameter.
+func fromV3ParameterSchema(result *openapi2.Parameter, schema *openapi3.Schema) {
+	if schema == nil {
+		return
+	}
+	result.Type = schema.Type
+	result.Format = schema.Format
+	result.Enum = schema.Enum
+	result.Minimum = schema.Min
+	result.Maximum = schema.Max
+	result.MultipleOf = schema.MultipleOf
+	result.ExclusiveMin = schema.ExclusiveMin
+	result.ExclusiveMax = schema.ExclusiveMax
+	result.MinLength = schema.MinLength
+	result.MaxLength = schema.MaxLength
+	result.Pattern = schema.Pattern
+	result.Default = schema.Default
+	result.Items = schema.Items
+	result.MinItems = schema.MinItems
+	result.MaxItems = schema.MaxItems
+}
+
+// FromV3RequestBodyFormData converts a form request body into "formData" parameters,
+// one for each property of the form schema, and returns the form media type.
+//
+// It returns nil when the request body is a reference, has JSON content or has no form content.
+// Binary string properties become "file" parameters.
+func FromV3RequestBodyFormData(requestBodyRef *openapi3.RequestBodyRef) (openapi2.Parameters, string) {
+	requestBody := requestBodyRef.Value
+	if requestBodyRef.Ref != "" || requestBody == nil || requestBody.GetMediaType("application/json") != nil {
+		return nil, ""
+	}
+	for _, mediaType := range formMediaTypes {
+		content := requestBody.GetMediaType(mediaType)
+		if content == nil || content.Schema == nil || content.Schema.Value == nil {
+			continue
+		}
+		schema := content.Schema.Value
+		required := make(map[string]bool, len(schema.Required))
+		for _, name := range schema.Required {
+			required[name] = true
+		}
+		names := make([]string, 0, len(schema.Properties))
+		for name := range schema.Properties {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		parameters := make(openapi2.Parameters, 0, len(names))
+		for _, name := range names {
+			parameter := &openapi2.Parameter{
+				In:       "formData",
+				Name:     name,
+				Required: required[name],
+			}
+			// Non-body parameters can't hold a $ref, so referenced property schemas are inlined
+			if propertyRef := schema.Properties[name]; propertyRef != nil && propertyRef.Value != nil {
+				property := FromV3SchemaRef(&openapi3.SchemaRef{Value: propertyRef.Value}).Value
+				parameter.Description = property.Description
+				fromV3ParameterSchema(parameter, property)
+				if property.Type == "string" && property.Format == "binary" {
+					parameter.Type = "file"
+					parameter.Format = ""
+				}
+			}
+			parameters = append(parameters, parameter)
+		}
+		return parameters, mediaType
+	}
+	return nil, ""
+}
+
+var formMediaTypes = []string{
+	"application/x-www-form-urlencoded",
+	"multipart/form-data",
+}
+
 func FromV3Responses(responses map[string]*openapi3.ResponseRef) (map[string]*openapi2.Response, error) {
+	return fromV3Responses(responses, "", nil)
+}
+
+// fromV3Responses converts responses, reporting what it drops as the responses of where.
+func fromV3Responses(responses map[string]*openapi3.ResponseRef, where string, warnings *lossyWarnings) (map[string]*openapi2.Response, error) {
 	v2Responses := make(map[string]*openapi2.Response, len(responses))
 	for k, response := range responses {
-		r, err := FromV3Response(response)
+		r, err := fromV3Response(response, where, k, warnings)
 		if err != nil {
 			return nil, err
 		}
@@ -587,6 +1071,10 @@ func FromV3Responses(responses map[string]*openapi3.ResponseRef) (map[string]*op
 }
 
 func FromV3Response(ref *openapi3.ResponseRef) (*openapi2.Response, error) {
+	return fromV3Response(ref, "", "", nil)
+}
+
+func fromV3Response(ref *openapi3.ResponseRef, where string, status string, warnings *lossyWarnings) (*openapi2.Response, error) {
 	if v := ref.Ref; len(v) > 0 {
 		return &openapi2.Response{
 			Ref: FromV3Ref(v),
@@ -600,8 +1088,13 @@ func FromV3Response(ref *openapi3.ResponseRef) (*openapi2.Response, error) {
 	result := &openapi2.Response{
 		Description: response.Description,
 	}
-	if content := response.Content; content != nil {
-		if ct := content["application/json"]; ct != nil {
+	if len(response.Links) > 0 {
+		warnings.warn("%s: links of response '%s' are not supported and were dropped", where, status)
+	}
+	for mediaType, ct := range response.Content {
+		if mediaType != "application/json" {
+			warnings.warn("%s: content type '%s' of response '%s' was dropped", where, mediaType, status)
+		} else if ct != nil {
 			result.Schema = FromV3SchemaRef(ct.Schema)
 		}
 	}
@@ -609,6 +1102,11 @@ func FromV3Response(ref *openapi3.ResponseRef) (*openapi2.Response, error) {
 }
 
 func FromV3SecurityScheme(swagger *openapi3.Swagger, ref *openapi3.SecuritySchemeRef) (*openapi2.SecurityScheme, error) {
+	return fromV3SecurityScheme(swagger, "", ref, nil)
+}
+
+// fromV3SecurityScheme converts a security scheme, reporting what it drops as the scheme of the given name.
+func fromV3SecurityScheme(swagger *openapi3.Swagger, name string, ref *openapi3.SecuritySchemeRef, warnings *lossyWarnings) (*openapi2.SecurityScheme, error) {
 	securityScheme := ref.Value
 	if securityScheme == nil {
 		return nil, nil
@@ -623,6 +1121,7 @@ func FromV3SecurityScheme(swagger *openapi3.Swagger, ref *openapi3.SecuritySchem
 		case "basic":
 			result.Type = "basic"
 		default:
+			warnings.warn("Security scheme '%s': HTTP scheme '%s' is approximated by an API key in the Authorization header", name, securityScheme.Scheme)
 			result.Type = "apiKey"
 			result.In = "header"
 			result.Name = "Authorization"
@@ -635,17 +1134,32 @@ func FromV3SecurityScheme(swagger *openapi3.Swagger, ref *openapi3.SecuritySchem
 		result.Type = "oauth2"
 		flows := securityScheme.Flows
 		if flows != nil {
+			// A v2 scheme holds a single flow; when several are defined
+			// the first one in this order is kept.
+			count := 0
+			for _, flow := range []*openapi3.OAuthFlow{flows.Implicit, flows.AuthorizationCode, flows.Password, flows.ClientCredentials} {
+				if flow != nil {
+					count++
+				}
+			}
+			if count > 1 {
+				warnings.warn("Security scheme '%s': only one of its %d OAuth flows can be kept", name, count)
+			}
 			var flow *openapi3.OAuthFlow
-			// TODO: Is this the right priority? What if multiple defined?
 			if flow = flows.Implicit; flow != nil {
 				result.Flow = "implicit"
 			} else if flow = flows.AuthorizationCode; flow != nil {
-				result.Flow = "accesscode"
+				result.Flow = "accessCode"
 			} else if flow = flows.Password; flow != nil {
 				result.Flow = "password"
+			} else if flow = flows.ClientCredentials; flow != nil {
+				result.Flow = "application"
 			} else {
 				return nil, nil
 			}
+			result.AuthorizationURL = flow.AuthorizationURL
+			result.TokenURL = flow.TokenURL
+			result.Scopes = make(map[string]string, len(flow.Scopes))
 			for scope, desc := range flow.Scopes {
 				result.Scopes[scope] = desc
 			}
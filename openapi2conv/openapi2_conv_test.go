@@ -34,6 +34,61 @@ func TestConvOpenAPIV2ToV3(t *testing.T) {
 	require.JSONEq(t, exampleV3, string(data))
 }
 
+func TestConvOpenAPIV2ToV3MalformedHost(t *testing.T) {
+	swagger2 := &openapi2.Swagger{
+		Info: openapi3.Info{Title: "MyAPI", Version: "0.1"},
+		Host: "https://test.example.com",
+	}
+	_, err := openapi2conv.ToV3Swagger(swagger2)
+	require.EqualError(t, err, "Invalid host 'https://test.example.com': must be a host name or IP address with an optional port")
+}
+
+func TestConvResponseExamples(t *testing.T) {
+	var swagger2 openapi2.Swagger
+	err := json.Unmarshal([]byte(`
+{
+  "info": {"title": "MyAPI", "version": "0.1"},
+  "produces": ["application/json"],
+  "paths": {
+    "/pets": {
+      "get": {
+        "responses": {
+          "200": {
+            "description": "ok",
+            "schema": {"type": "object", "properties": {"name": {"type": "string"}}},
+            "examples": {
+              "application/json": {"name": "Rex"},
+              "application/xml": "<pet><name>Rex</name></pet>"
+            }
+          },
+          "404": {
+            "description": "not found",
+            "examples": {"text/plain": "Not found"}
+          }
+        }
+      }
+    }
+  }
+}`), &swagger2)
+	require.NoError(t, err)
+
+	swagger3, err := openapi2conv.ToV3Swagger(&swagger2)
+	require.NoError(t, err)
+	responses := swagger3.Paths["/pets"].Get.Responses
+
+	content := responses["200"].Value.Content
+	require.Len(t, content, 2)
+	require.Equal(t, map[string]interface{}{"name": "Rex"}, content["application/json"].Example)
+	require.Equal(t, "object", content["application/json"].Schema.Value.Type)
+	require.Equal(t, "<pet><name>Rex</name></pet>", content["application/xml"].Example)
+	require.Equal(t, "object", content["application/xml"].Schema.Value.Type)
+
+	content = responses["404"].Value.Content
+	require.Len(t, content, 1)
+	require.Equal(t, "Not found", content["text/plain"].Example)
+	require.Nil(t, content["text/plain"].Schema)
+}
+
 const exampleV2 = `
 {
   "info": {"title":"MyAPI","version":"0.1"},
@@ -72,7 +127,8 @@ const exampleV2 = `
         "parameters": [
           {
             "in": "query",
-            "name": "x"
+            "name": "x",
+            "allowEmptyValue": true
           },
           {
             "in": "query",
@@ -92,7 +148,8 @@ const exampleV2 = `
             "type": "array",
             "items": {
               "type": "number"
-            }
+            },
+            "collectionFormat": "csv"
           },
           {
             "in": "body",
@@ -280,7 +337,8 @@ const exampleV3 = `
         "parameters": [
           {
             "in": "query",
-            "name": "x"
+            "name": "x",
+            "allowEmptyValue": true
           },
           {
             "description": "The y parameter",
@@ -297,6 +355,8 @@ const exampleV3 = `
             "description": "Only return results that intersect the provided bounding box.",
             "in": "query",
             "name": "bbox",
+            "style": "form",
+            "explode": false,
             "schema": {
               "type": "array",
               "items": {
@@ -378,3 +438,591 @@ const exampleV3 = `
   ]
 }
 `
+
+func TestConvDeriveSchemaTitles(t *testing.T) {
+	var swagger2 openapi2.Swagger
+	err := json.Unmarshal([]byte(`
+{
+  "info": {"title": "MyAPI", "version": "0.1"},
+  "parameters": {
+    "limit": {"in": "query", "name": "limit", "type": "integer"}
+  },
+  "paths": {
+    "/pets": {
+      "post": {
+        "parameters": [
+          {"in": "query", "name": "dryRun", "type": "boolean"},
+          {"in": "body", "name": "pet", "schema": {"type": "object"}}
+        ],
+        "responses": {"201": {"description": "created"}}
+      }
+    }
+  }
+}`), &swagger2)
+	require.NoError(t, err)
+
+	swagger3, err := openapi2conv.ToV3Swagger(&swagger2)
+	require.NoError(t, err)
+	require.Empty(t, swagger3.Paths["/pets"].Post.Parameters[0].Value.Schema.Value.Title)
+	require.Empty(t, swagger3.Components.Parameters["limit"].Value.Schema.Value.Title)
+
+	swagger3, err = openapi2conv.ToV3SwaggerWithOptions(&swagger2, openapi2conv.ConvertOptions{DeriveSchemaTitles: true})
+	require.NoError(t, err)
+	operation := swagger3.Paths["/pets"].Post
+	require.Equal(t, "dryRun", operation.Parameters[0].Value.Schema.Value.Title)
+	require.Equal(t, "limit", swagger3.Components.Parameters["limit"].Value.Schema.Value.Title)
+	require.Empty(t, operation.RequestBody.Value.Content["application/json"].Schema.Value.Title)
+}
+
+func TestConvAllOfInheritance(t *testing.T) {
+	var swagger2 openapi2.Swagger
+	err := json.Unmarshal([]byte(`
+{
+  "info": {"title": "MyAPI", "version": "0.1"},
+  "paths": {},
+  "definitions": {
+    "Pet": {
+      "type": "object",
+      "discriminator": "petType",
+      "required": ["name", "petType"],
+      "properties": {
+        "name": {"type": "string"},
+        "petType": {"type": "string"}
+      }
+    },
+    "Cat": {
+      "allOf": [
+        {"$ref": "#/definitions/Pet"},
+        {
+          "type": "object",
+          "required": ["huntingSkill", "huntingSkill"],
+          "properties": {"huntingSkill": {"type": "string", "enum": ["lazy", "aggressive"]}}
+        }
+      ]
+    },
+    "Dog": {
+      "allOf": [
+        {"$ref": "#/definitions/Pet"},
+        {
+          "allOf": [{
+            "type": "object",
+            "required": ["packSize"],
+            "properties": {"packSize": {"type": "integer", "minimum": 0}}
+          }]
+        }
+      ]
+    }
+  }
+}`), &swagger2)
+	require.NoError(t, err)
+
+	// The OpenAPI 2 form of the discriminator is kept by the OpenAPI 2 document
+	data, err := json.Marshal(swagger2.Definitions["Pet"])
+	require.NoError(t, err)
+	require.Contains(t, string(data), `"discriminator":"petType"`)
+
+	swagger3, err := openapi2conv.ToV3Swagger(&swagger2)
+	require.NoError(t, err)
+	schemas := swagger3.Components.Schemas
+	pet := schemas["Pet"].Value
+	require.Equal(t, "petType", pet.Discriminator.PropertyName)
+	require.Equal(t, []string{"name", "petType"}, pet.Required)
+	cat := schemas["Cat"].Value
+	require.Equal(t, "#/components/schemas/Pet", cat.AllOf[0].Ref)
+	require.Equal(t, []string{"huntingSkill"}, cat.AllOf[1].Value.Required)
+	require.Len(t, cat.AllOf[1].Value.Properties, 1)
+	dog := schemas["Dog"].Value
+	require.Equal(t, "#/components/schemas/Pet", dog.AllOf[0].Ref)
+
+	data, err = json.Marshal(swagger3)
+	require.NoError(t, err)
+	require.Contains(t, string(data), `"discriminator":{"propertyName":"petType"}`)
+	swagger3, err = openapi3.NewSwaggerLoader().LoadSwaggerFromData(data)
+	require.NoError(t, err)
+	schemas = swagger3.Components.Schemas
+	pet = schemas["Pet"].Value
+
+	// Each subtype validates its own instances, named by the discriminator property
+	for _, tc := range []struct {
+		instance string
+		valid    bool
+	}{
+		{`{"name": "Tom", "petType": "Cat", "huntingSkill": "lazy"}`, true},
+		{`{"name": "Tom", "petType": "Cat"}`, false},
+		{`{"name": "Tom", "huntingSkill": "lazy"}`, false},
+		{`{"name": "Rex", "petType": "Dog", "packSize": 3}`, true},
+		{`{"name": "Rex", "petType": "Dog", "packSize": -1}`, false},
+	} {
+		var instance map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(tc.instance), &instance))
+		petType, _ := instance[pet.Discriminator.PropertyName].(string)
+		subtype := schemas["Cat"]
+		if petType == "Dog" {
+			subtype = schemas["Dog"]
+		}
+		err := subtype.Value.VisitJSON(instance)
+		if tc.valid {
+			require.NoError(t, err, tc.instance)
+		} else {
+			require.Error(t, err, tc.instance)
+		}
+	}
+}
+
+func TestConvParameterExtensions(t *testing.T) {
+	var swagger2 openapi2.Swagger
+	err := json.Unmarshal([]byte(`
+{
+  "info": {"title": "MyAPI", "version": "0.1"},
+  "paths": {
+    "/pets": {
+      "post": {
+        "parameters": [
+          {"in": "query", "name": "dryRun", "type": "boolean", "x-internal": true},
+          {"in": "body", "name": "pet", "x-codegen-request-body-name": "pet", "schema": {"type": "object"}}
+        ],
+        "responses": {"201": {"description": "created"}}
+      }
+    }
+  }
+}`), &swagger2)
+	require.NoError(t, err)
+
+	swagger3, err := openapi2conv.ToV3Swagger(&swagger2)
+	require.NoError(t, err)
+	operation := swagger3.Paths["/pets"].Post
+	data, err := json.Marshal(operation.RequestBody.Value)
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+  "x-codegen-request-body-name": "pet",
+  "content": {"application/json": {"schema": {"type": "object"}}}
+}`, string(data))
+	data, err = json.Marshal(operation.Parameters[0].Value)
+	require.NoError(t, err)
+	require.Contains(t, string(data), `"x-internal":true`)
+
+	// The extension names the body parameter on the way back
+	back, err := openapi2conv.FromV3Swagger(swagger3)
+	require.NoError(t, err)
+	parameters := back.Paths["/pets"].Post.Parameters
+	require.Len(t, parameters, 2)
+	require.Equal(t, "dryRun", parameters[0].Name)
+	require.Contains(t, parameters[0].Extensions, "x-internal")
+	require.Equal(t, "body", parameters[1].In)
+	require.Equal(t, "pet", parameters[1].Name)
+	require.Contains(t, parameters[1].Extensions, "x-codegen-request-body-name")
+}
+
+func TestConvFileResponse(t *testing.T) {
+	var swagger2 openapi2.Swagger
+	err := json.Unmarshal([]byte(`
+{
+  "info": {"title": "MyAPI", "version": "0.1"},
+  "paths": {
+    "/reports/{id}": {
+      "get": {
+        "parameters": [{"in": "path", "name": "id", "type": "string", "required": true}],
+        "responses": {
+          "200": {"description": "report", "schema": {"type": "file"}}
+        }
+      }
+    },
+    "/images/{id}": {
+      "get": {
+        "produces": ["image/png", "image/gif"],
+        "parameters": [{"in": "path", "name": "id", "type": "string", "required": true}],
+        "responses": {
+          "200": {"description": "image", "schema": {"type": "file", "description": "The image"}}
+        }
+      }
+    }
+  }
+}`), &swagger2)
+	require.NoError(t, err)
+
+	swagger3, err := openapi2conv.ToV3Swagger(&swagger2)
+	require.NoError(t, err)
+	data, err := json.Marshal(swagger3.Paths["/reports/{id}"].Get.Responses["200"].Value)
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+  "description": "report",
+  "content": {"application/octet-stream": {"schema": {"type": "string", "format": "binary"}}}
+}`, string(data))
+
+	content := swagger3.Paths["/images/{id}"].Get.Responses["200"].Value.Content
+	require.Len(t, content, 2)
+	for _, mediaType := range []string{"image/png", "image/gif"} {
+		schema := content[mediaType].Schema.Value
+		require.Equal(t, "string", schema.Type)
+		require.Equal(t, "binary", schema.Format)
+		require.Equal(t, "The image", schema.Description)
+	}
+}
+
+func TestConvXML(t *testing.T) {
+	var swagger2 openapi2.Swagger
+	err := json.Unmarshal([]byte(`
+{
+  "info": {"title": "MyAPI", "version": "0.1"},
+  "paths": {},
+  "definitions": {
+    "Pet": {
+      "type": "object",
+      "xml": {"name": "pet", "namespace": "https://example.com/schema", "prefix": "ex"},
+      "properties": {
+        "id": {"type": "integer", "xml": {"attribute": true}},
+        "photoUrls": {
+          "type": "array",
+          "xml": {"name": "photoUrl", "wrapped": true},
+          "items": {"type": "string", "xml": {"name": "url"}}
+        }
+      }
+    }
+  }
+}`), &swagger2)
+	require.NoError(t, err)
+
+	swagger3, err := openapi2conv.ToV3Swagger(&swagger2)
+	require.NoError(t, err)
+	data, err := json.Marshal(swagger3.Components.Schemas["Pet"])
+	require.NoError(t, err)
+	expected := `{
+  "type": "object",
+  "xml": {"name": "pet", "namespace": "https://example.com/schema", "prefix": "ex"},
+  "properties": {
+    "id": {"type": "integer", "xml": {"attribute": true}},
+    "photoUrls": {
+      "type": "array",
+      "xml": {"name": "photoUrl", "wrapped": true},
+      "items": {"type": "string", "xml": {"name": "url"}}
+    }
+  }
+}`
+	require.JSONEq(t, expected, string(data))
+
+	back, err := openapi2conv.FromV3Swagger(swagger3)
+	require.NoError(t, err)
+	data, err = json.Marshal(back.Definitions["Pet"])
+	require.NoError(t, err)
+	require.JSONEq(t, expected, string(data))
+}
+
+func TestConvSecuritySchemesOrder(t *testing.T) {
+	spec := []byte(`
+{
+  "info": {"title": "MyAPI", "version": "0.1"},
+  "paths": {},
+  "securityDefinitions": {
+    "petstore_auth": {"type": "oauth2", "flow": "implicit", "authorizationUrl": "https://example.com/oauth", "scopes": {"write:pets": "modify", "read:pets": "read"}},
+    "api_key": {"type": "apiKey", "name": "api_key", "in": "header"},
+    "basic": {"type": "basic"},
+    "zeta": {"type": "apiKey", "name": "zeta", "in": "query"}
+  }
+}`)
+	var sections []string
+	for i := 0; i < 20; i++ {
+		var swagger2 openapi2.Swagger
+		require.NoError(t, json.Unmarshal(spec, &swagger2))
+		swagger3, err := openapi2conv.ToV3Swagger(&swagger2)
+		require.NoError(t, err)
+		data, err := json.Marshal(swagger3.Components.SecuritySchemes)
+		require.NoError(t, err)
+		sections = append(sections, string(data))
+
+		back, err := openapi2conv.FromV3Swagger(swagger3)
+		require.NoError(t, err)
+		data, err = json.Marshal(back.SecurityDefinitions)
+		require.NoError(t, err)
+		sections = append(sections, string(data))
+	}
+	for i := 2; i < len(sections); i++ {
+		require.Equal(t, sections[i%2], sections[i])
+	}
+	require.Regexp(t, `^\{"api_key":.*"basic":.*"petstore_auth":.*"zeta":`, sections[0])
+
+	// The first failing scheme in name order is reported
+	var swagger2 openapi2.Swagger
+	require.NoError(t, json.Unmarshal([]byte(`
+{
+  "info": {"title": "MyAPI", "version": "0.1"},
+  "paths": {},
+  "securityDefinitions": {
+    "b": {"type": "oauth2", "flow": "second"},
+    "a": {"type": "oauth2", "flow": "first"}
+  }
+}`), &swagger2))
+	for i := 0; i < 10; i++ {
+		_, err := openapi2conv.ToV3Swagger(&swagger2)
+		require.EqualError(t, err, "Unsupported flow 'first'")
+	}
+}
+
+func TestConvOptionalAsNullable(t *testing.T) {
+	spec := []byte(`
+{
+  "info": {"title": "MyAPI", "version": "0.1"},
+  "paths": {},
+  "definitions": {
+    "Pet": {
+      "type": "object",
+      "required": ["name"],
+      "properties": {
+        "name": {"type": "string"},
+        "tag": {"type": "string"},
+        "owner": {"$ref": "#/definitions/Owner"}
+      }
+    },
+    "Owner": {
+      "allOf": [
+        {"$ref": "#/definitions/Pet"},
+        {"type": "object", "required": ["id"], "properties": {"id": {"type": "integer"}, "email": {"type": "string"}}},
+        {"type": "object", "properties": {"age": {"type": "integer"}}}
+      ],
+      "required": ["age"]
+    }
+  }
+}`)
+	var swagger2 openapi2.Swagger
+	require.NoError(t, json.Unmarshal(spec, &swagger2))
+	swagger3, err := openapi2conv.ToV3Swagger(&swagger2)
+	require.NoError(t, err)
+	require.False(t, swagger3.Components.Schemas["Pet"].Value.Properties["tag"].Value.Nullable)
+
+	swagger2 = openapi2.Swagger{}
+	require.NoError(t, json.Unmarshal(spec, &swagger2))
+	swagger3, err = openapi2conv.ToV3SwaggerWithOptions(&swagger2, openapi2conv.ConvertOptions{OptionalAsNullable: true})
+	require.NoError(t, err)
+	pet := swagger3.Components.Schemas["Pet"].Value
+	require.False(t, pet.Properties["name"].Value.Nullable)
+	require.True(t, pet.Properties["tag"].Value.Nullable)
+	require.Equal(t, "#/components/schemas/Owner", pet.Properties["owner"].Ref)
+	owner := swagger3.Components.Schemas["Owner"].Value
+	require.False(t, owner.AllOf[1].Value.Properties["id"].Value.Nullable)
+	require.True(t, owner.AllOf[1].Value.Properties["email"].Value.Nullable)
+	require.False(t, owner.AllOf[2].Value.Properties["age"].Value.Nullable)
+
+	// The OpenAPI 2 document is left alone
+	data, err := json.Marshal(&swagger2)
+	require.NoError(t, err)
+	require.NotContains(t, string(data), "nullable")
+}
+
+func TestConvTargetVersion(t *testing.T) {
+	spec := []byte(`
+{
+  "info": {"title": "MyAPI", "version": "0.1"},
+  "paths": {
+    "/items": {
+      "get": {
+        "parameters": [{"in": "query", "name": "above", "type": "number", "minimum": 0, "exclusiveMinimum": true}],
+        "responses": {"200": {"description": "items", "schema": {"$ref": "#/definitions/Item"}}}
+      }
+    }
+  },
+  "definitions": {
+    "Item": {
+      "type": "object",
+      "properties": {
+        "price": {"type": "number", "minimum": 0, "exclusiveMinimum": true, "maximum": 100, "exclusiveMaximum": false}
+      }
+    }
+  }
+}`)
+	opts := openapi2conv.ConvertOptions{OptionalAsNullable: true}
+	convert := func(targetVersion string) map[string]interface{} {
+		var swagger2 openapi2.Swagger
+		require.NoError(t, json.Unmarshal(spec, &swagger2))
+		opts.TargetVersion = targetVersion
+		data, err := openapi2conv.ToV3JSON(&swagger2, opts)
+		require.NoError(t, err)
+		var document map[string]interface{}
+		require.NoError(t, json.Unmarshal(data, &document))
+		return document
+	}
+	price := func(document map[string]interface{}) interface{} {
+		return document["components"].(map[string]interface{})["schemas"].(map[string]interface{})["Item"].(map[string]interface{})["properties"].(map[string]interface{})["price"]
+	}
+	above := func(document map[string]interface{}) interface{} {
+		return document["paths"].(map[string]interface{})["/items"].(map[string]interface{})["get"].(map[string]interface{})["parameters"].([]interface{})[0].(map[string]interface{})["schema"]
+	}
+
+	// 3.0 is the default
+	for _, targetVersion := range []string{"", "3.0"} {
+		document := convert(targetVersion)
+		require.Equal(t, "3.0.2", document["openapi"])
+		require.Equal(t, map[string]interface{}{
+			"type":             "number",
+			"nullable":         true,
+			"minimum":          float64(0),
+			"exclusiveMinimum": true,
+			"maximum":          float64(100),
+		}, price(document))
+	}
+
+	document := convert("3.1")
+	require.Equal(t, "3.1.0", document["openapi"])
+	require.Equal(t, map[string]interface{}{
+		"type":             []interface{}{"number", "null"},
+		"exclusiveMinimum": float64(0),
+		"maximum":          float64(100),
+	}, price(document))
+	require.Equal(t, map[string]interface{}{
+		"type":             "number",
+		"exclusiveMinimum": float64(0),
+	}, above(document))
+
+	var swagger2 openapi2.Swagger
+	require.NoError(t, json.Unmarshal(spec, &swagger2))
+	_, err := openapi2conv.ToV3SwaggerWithOptions(&swagger2, openapi2conv.ConvertOptions{TargetVersion: "3.1"})
+	require.EqualError(t, err, "Target version '3.1' can't be represented by openapi3.Swagger, use ToV3JSON")
+	_, err = openapi2conv.ToV3JSON(&swagger2, openapi2conv.ConvertOptions{TargetVersion: "3.2"})
+	require.EqualError(t, err, "Unsupported target version '3.2', expected '3.0' or '3.1'")
+}
+
+func TestConvParameterExamples(t *testing.T) {
+	var swagger2 openapi2.Swagger
+	err := json.Unmarshal([]byte(`
+{
+  "info": {"title": "MyAPI", "version": "0.1"},
+  "paths": {
+    "/pets": {
+      "get": {
+        "parameters": [
+          {"in": "query", "name": "limit", "type": "integer", "x-example": 10},
+          {"in": "query", "name": "tag", "type": "string", "x-example": "dog", "x-examples": {"dog": "dog", "cat": "cat"}}
+        ],
+        "responses": {"200": {"description": "pets"}}
+      }
+    }
+  }
+}`), &swagger2)
+	require.NoError(t, err)
+
+	swagger3, err := openapi2conv.ToV3Swagger(&swagger2)
+	require.NoError(t, err)
+	parameters := swagger3.Paths["/pets"].Get.Parameters
+	data, err := json.Marshal(parameters[0].Value)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"in": "query", "name": "limit", "schema": {"type": "integer"}, "example": 10}`, string(data))
+
+	// The named examples win over the single one, which is left as an extension
+	data, err = json.Marshal(parameters[1].Value)
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+  "in": "query",
+  "name": "tag",
+  "schema": {"type": "string"},
+  "x-example": "dog",
+  "examples": {
+    "dog": {"value": "dog"},
+    "cat": {"value": "cat"}
+  }
+}`, string(data))
+}
+
+func TestConvAdditionalProperties(t *testing.T) {
+	var swagger2 openapi2.Swagger
+	err := json.Unmarshal([]byte(`
+{
+  "info": {"title": "MyAPI", "version": "0.1"},
+  "paths": {},
+  "definitions": {
+    "Absent": {"type": "object"},
+    "Allowed": {"type": "object", "additionalProperties": true},
+    "Closed": {"type": "object", "additionalProperties": false},
+    "Labels": {"type": "object", "additionalProperties": {"type": "string"}},
+    "Tags": {"type": "object", "additionalProperties": {"$ref": "#/definitions/Labels"}}
+  }
+}`), &swagger2)
+	require.NoError(t, err)
+
+	swagger3, err := openapi2conv.ToV3Swagger(&swagger2)
+	require.NoError(t, err)
+	data, err := json.Marshal(swagger3.Components.Schemas)
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+  "Absent": {"type": "object"},
+  "Allowed": {"type": "object", "additionalProperties": true},
+  "Closed": {"type": "object", "additionalProperties": false},
+  "Labels": {"type": "object", "additionalProperties": {"type": "string"}},
+  "Tags": {"type": "object", "additionalProperties": {"$ref": "#/components/schemas/Labels"}}
+}`, string(data))
+
+	back, err := openapi2conv.FromV3Swagger(swagger3)
+	require.NoError(t, err)
+	data, err = json.Marshal(back.Definitions)
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+  "Absent": {"type": "object"},
+  "Allowed": {"type": "object", "additionalProperties": true},
+  "Closed": {"type": "object", "additionalProperties": false},
+  "Labels": {"type": "object", "additionalProperties": {"type": "string"}},
+  "Tags": {"type": "object", "additionalProperties": {"$ref": "#/definitions/Labels"}}
+}`, string(data))
+}
+
+func TestConvOperationServers(t *testing.T) {
+	var swagger2 openapi2.Swagger
+	err := json.Unmarshal([]byte(`
+{
+  "info": {"title": "MyAPI", "version": "0.1"},
+  "host": "api.example.com",
+  "basePath": "/v1",
+  "schemes": ["https"],
+  "paths": {
+    "/pets": {
+      "get": {"responses": {"200": {"description": "pets"}}},
+      "post": {"x-basePath": "/v2", "responses": {"201": {"description": "created"}}}
+    },
+    "/uploads": {
+      "put": {
+        "x-servers": ["https://uploads.example.com", {"url": "https://backup.example.com", "description": "Backup"}],
+        "responses": {"204": {"description": "uploaded"}}
+      }
+    }
+  }
+}`), &swagger2)
+	require.NoError(t, err)
+
+	swagger3, err := openapi2conv.ToV3Swagger(&swagger2)
+	require.NoError(t, err)
+	require.Equal(t, openapi3.Servers{{URL: "https://api.example.com/v1"}}, swagger3.Servers)
+	// Operations without an override inherit the servers of the document
+	require.Nil(t, swagger3.Paths["/pets"].Get.Servers)
+	require.Equal(t, &openapi3.Servers{{URL: "https://api.example.com/v2"}}, swagger3.Paths["/pets"].Post.Servers)
+	require.Equal(t, &openapi3.Servers{
+		{URL: "https://uploads.example.com"},
+		{URL: "https://backup.example.com", Description: "Backup"},
+	}, swagger3.Paths["/uploads"].Put.Servers)
+
+	swagger2.Paths["/pets"].Post.Extensions["x-basePath"] = json.RawMessage(`42`)
+	_, err = openapi2conv.ToV3Swagger(&swagger2)
+	require.EqualError(t, err, "Invalid 'x-basePath' extension: json: cannot unmarshal number into Go value of type string")
+}
+
+func TestConvInfoContactLicense(t *testing.T) {
+	const info = `{
+  "title": "MyAPI",
+  "version": "0.1",
+  "x-audience": "public",
+  "contact": {"name": "API team", "email": "api@example.com", "x-slack": "#api"},
+  "license": {"name": "Apache 2.0", "url": "https://www.apache.org/licenses/LICENSE-2.0.html", "x-spdx": "Apache-2.0"}
+}`
+	var swagger2 openapi2.Swagger
+	err := json.Unmarshal([]byte(`{"info": `+info+`, "paths": {}}`), &swagger2)
+	require.NoError(t, err)
+
+	data, err := json.Marshal(&swagger2)
+	require.NoError(t, err)
+	var roundTrip struct {
+		Info json.RawMessage `json:"info"`
+	}
+	require.NoError(t, json.Unmarshal(data, &roundTrip))
+	require.JSONEq(t, info, string(roundTrip.Info))
+
+	swagger3, err := openapi2conv.ToV3Swagger(&swagger2)
+	require.NoError(t, err)
+	data, err = json.Marshal(swagger3.Info)
+	require.NoError(t, err)
+	require.JSONEq(t, info, string(data))
+}
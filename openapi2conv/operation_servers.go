@@ -0,0 +1,66 @@
+package openapi2conv
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/mbilski/kin-openapi/openapi2"
+	"github.com/mbilski/kin-openapi/openapi3"
+)
+
+// Extensions overriding the servers of an operation, as used by some API gateways.
+const (
+	// serversExtension lists the servers of the operation, as OpenAPI 3 server objects
+	// such as {"url": "https://uploads.example.com/v1"}, or as URLs.
+	serversExtension = "x-servers"
+	// basePathExtension replaces the basePath of the document for the operation, such as "/v2".
+	basePathExtension = "x-basePath"
+)
+
+// toV3OperationServers returns the servers of an operation overriding those of the document,
+// given by its "x-servers" extension or else by its "x-basePath" extension, or nil.
+// The "x-basePath" servers have the schemes and host of the document, or a relative URL
+// without host.
+func toV3OperationServers(swagger *openapi2.Swagger, operation *openapi2.Operation) (*openapi3.Servers, error) {
+	if value, ok := operation.Extensions[serversExtension]; ok {
+		var items []json.RawMessage
+		if err := decodeExtension(value, &items); err != nil {
+			return nil, invalidExtension(serversExtension, err)
+		}
+		servers := make(openapi3.Servers, 0, len(items))
+		for _, item := range items {
+			server := &openapi3.Server{}
+			if err := json.Unmarshal(item, &server.URL); err != nil {
+				if err := json.Unmarshal(item, server); err != nil {
+					return nil, invalidExtension(serversExtension, err)
+				}
+			}
+			servers = append(servers, server)
+		}
+		return &servers, nil
+	}
+	if value, ok := operation.Extensions[basePathExtension]; ok {
+		var basePath string
+		if err := decodeExtension(value, &basePath); err != nil {
+			return nil, invalidExtension(basePathExtension, err)
+		}
+		override := &openapi2.Swagger{Schemes: swagger.Schemes, Host: swagger.Host, BasePath: basePath}
+		urls, err := override.ServerURLs()
+		if err != nil {
+			return nil, err
+		}
+		if len(urls) == 0 {
+			urls = []string{basePath}
+		}
+		servers := make(openapi3.Servers, 0, len(urls))
+		for _, url := range urls {
+			servers = append(servers, &openapi3.Server{URL: url})
+		}
+		return &servers, nil
+	}
+	return nil, nil
+}
+
+func invalidExtension(key string, err error) error {
+	return fmt.Errorf("Invalid '%s' extension: %v", key, err)
+}
@@ -0,0 +1,80 @@
+package openapi2conv
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/mbilski/kin-openapi/openapi2"
+)
+
+// RefError is returned by a conversion with ConvertOptions.StrictRefs
+// for a $ref that doesn't resolve within the OpenAPI 2 document.
+type RefError struct {
+	// Pointer is the JSON pointer of the element holding the $ref in the OpenAPI 2 document.
+	Pointer string
+	Ref     string
+}
+
+func (err *RefError) Error() string {
+	return fmt.Sprintf("%s: Failed to resolve ref: '%s'", err.Pointer, err.Ref)
+}
+
+// danglingRefs returns the local $refs of a document that don't resolve within it, sorted by pointer.
+func danglingRefs(swagger *openapi2.Swagger) ([]*RefError, error) {
+	var refErrs []*RefError
+	var root interface{}
+	for _, ref := range swagger.RefsFrom("") {
+		if !strings.HasPrefix(ref, "#") {
+			continue
+		}
+		if root == nil {
+			document, err := swagger.ToMap()
+			if err != nil {
+				return nil, err
+			}
+			root = document
+		}
+		if resolvesLocally(root, ref[1:]) {
+			continue
+		}
+		for _, pointer := range swagger.RefsTo(ref) {
+			refErrs = append(refErrs, &RefError{Pointer: pointer, Ref: ref})
+		}
+	}
+	sort.SliceStable(refErrs, func(i, j int) bool {
+		return refErrs[i].Pointer < refErrs[j].Pointer
+	})
+	return refErrs, nil
+}
+
+// resolvesLocally reports whether the JSON pointer designates an element of the document.
+func resolvesLocally(document interface{}, pointer string) bool {
+	if pointer == "" {
+		return true
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return false
+	}
+	node := document
+	for _, token := range strings.Split(pointer[1:], "/") {
+		token = strings.Replace(strings.Replace(token, "~1", "/", -1), "~0", "~", -1)
+		switch value := node.(type) {
+		case map[string]interface{}:
+			var ok bool
+			if node, ok = value[token]; !ok {
+				return false
+			}
+		case []interface{}:
+			i, err := strconv.Atoi(token)
+			if err != nil || i < 0 || i >= len(value) {
+				return false
+			}
+			node = value[i]
+		default:
+			return false
+		}
+	}
+	return true
+}
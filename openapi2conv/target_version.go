@@ -0,0 +1,130 @@
+package openapi2conv
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/mbilski/kin-openapi/openapi2"
+)
+
+// Values of ConvertOptions.TargetVersion.
+const (
+	TargetVersion30 = "3.0"
+	TargetVersion31 = "3.1"
+)
+
+func unsupportedTargetVersion(version string) error {
+	return fmt.Errorf("Unsupported target version '%s', expected '%s' or '%s'", version, TargetVersion30, TargetVersion31)
+}
+
+// ToV3JSON converts an OpenAPI 2 document into the JSON of an OpenAPI 3 document
+// of ConvertOptions.TargetVersion.
+//
+// With "3.1", the document converted by ToV3SwaggerWithOptions is rewritten:
+//   - "openapi" is "3.1.0".
+//   - A "nullable" schema with a type gets the "null" type, as in "type": ["string", "null"].
+//     A "nullable" schema without a type accepts null already, and "nullable" is dropped.
+//   - A boolean "exclusiveMinimum" or "exclusiveMaximum" is replaced by the bound it makes
+//     exclusive, as in "exclusiveMinimum": 0 instead of "minimum": 0.
+func ToV3JSON(swagger *openapi2.Swagger, opts ConvertOptions) ([]byte, error) {
+	version := opts.TargetVersion
+	switch version {
+	case "", TargetVersion30, TargetVersion31:
+	default:
+		return nil, unsupportedTargetVersion(version)
+	}
+	opts.TargetVersion = TargetVersion30
+	result, err := ToV3SwaggerWithOptions(swagger, opts)
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(result)
+	if err != nil || version != TargetVersion31 {
+		return data, err
+	}
+	var document map[string]interface{}
+	if err := json.Unmarshal(data, &document); err != nil {
+		return nil, err
+	}
+	document["openapi"] = "3.1.0"
+	rewriteV31Schemas(document, false)
+	return json.Marshal(document)
+}
+
+// v31SubSchemas are the keywords of a schema holding schemas, or lists or maps of schemas.
+var v31SubSchemas = map[string]bool{
+	"additionalProperties": true,
+	"allOf":                true,
+	"anyOf":                true,
+	"items":                true,
+	"not":                  true,
+	"oneOf":                true,
+	"properties":           true,
+}
+
+// rewriteV31Schemas rewrites the OpenAPI 3.0 schemas nested in a JSON value into OpenAPI 3.1.
+// isSchema tells whether the value itself is a schema.
+func rewriteV31Schemas(value interface{}, isSchema bool) {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		if isSchema {
+			rewriteV31Schema(value)
+		}
+		for key, item := range value {
+			switch {
+			case isSchema && v31SubSchemas[key]:
+				rewriteV31SubSchemas(key, item)
+			case isSchema:
+				// Keywords such as "enum" or "example" hold values
+			case key == "schema":
+				rewriteV31Schemas(item, true)
+			case key == "schemas":
+				rewriteV31SubSchemas("properties", item)
+			default:
+				rewriteV31Schemas(item, false)
+			}
+		}
+	case []interface{}:
+		for _, item := range value {
+			rewriteV31Schemas(item, isSchema)
+		}
+	}
+}
+
+// rewriteV31SubSchemas rewrites the schemas held by a schema keyword.
+func rewriteV31SubSchemas(key string, value interface{}) {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		if key != "properties" {
+			rewriteV31Schemas(value, true)
+			return
+		}
+		for _, item := range value {
+			rewriteV31Schemas(item, true)
+		}
+	case []interface{}:
+		for _, item := range value {
+			rewriteV31Schemas(item, true)
+		}
+	}
+}
+
+func rewriteV31Schema(schema map[string]interface{}) {
+	if nullable, _ := schema["nullable"].(bool); nullable {
+		if schemaType, ok := schema["type"].(string); ok {
+			schema["type"] = []interface{}{schemaType, "null"}
+		}
+	}
+	delete(schema, "nullable")
+	for exclusive, bound := range map[string]string{"exclusiveMinimum": "minimum", "exclusiveMaximum": "maximum"} {
+		flag, ok := schema[exclusive].(bool)
+		if !ok {
+			continue
+		}
+		delete(schema, exclusive)
+		if value, ok := schema[bound]; ok && flag {
+			schema[exclusive] = value
+			delete(schema, bound)
+		}
+	}
+}
@@ -0,0 +1,215 @@
+package openapi2conv
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/mbilski/kin-openapi/openapi2"
+	"github.com/mbilski/kin-openapi/openapi3"
+)
+
+// Codes of the conversion warnings returned by ToV3.
+const (
+	// WarningCollectionFormat reports a collectionFormat without an OpenAPI 3 style.
+	WarningCollectionFormat = "collection-format"
+	// WarningFileParameter reports a "file" parameter, which has no OpenAPI 3 parameter equivalent.
+	WarningFileParameter = "file-parameter"
+	// WarningBodyConsumes reports body media types that were dropped from a request body.
+	WarningBodyConsumes = "body-consumes"
+	// WarningOperationID reports an operationId renamed by ConvertOptions.DedupeOperationIDs.
+	WarningOperationID = "operation-id"
+	// WarningDanglingRef reports a local $ref that doesn't resolve within the document.
+	WarningDanglingRef = "dangling-ref"
+)
+
+// ConversionWarning describes an OpenAPI 2 construct that was approximated or dropped
+// while converting a document.
+type ConversionWarning struct {
+	// Pointer is the JSON pointer of the construct in the OpenAPI 2 document.
+	Pointer string
+	// Code identifies the kind of warning, such as WarningCollectionFormat.
+	Code    string
+	Message string
+}
+
+func (warning ConversionWarning) Error() string {
+	return fmt.Sprintf("%s: %s", warning.Pointer, warning.Message)
+}
+
+// ToV3 converts an OpenAPI 2 document into OpenAPI 3, like ToV3Swagger,
+// and reports the constructs that couldn't be converted faithfully.
+// Warnings are sorted by pointer, then code.
+func ToV3(swagger *openapi2.Swagger) (*openapi3.Swagger, []ConversionWarning, error) {
+	return ToV3WithOptions(swagger, ConvertOptions{})
+}
+
+// ToV3WithOptions is like ToV3, configured by the given options.
+func ToV3WithOptions(swagger *openapi2.Swagger, opts ConvertOptions) (*openapi3.Swagger, []ConversionWarning, error) {
+	result, err := ToV3SwaggerWithOptions(swagger, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	return result, lossyToV3(swagger, opts), nil
+}
+
+// operationIDRenaming is the new operationId of an operation repeating an earlier one.
+type operationIDRenaming struct {
+	path   string
+	method string
+	from   string
+	id     string
+}
+
+// operationIDRenamings returns the renamings applied by ConvertOptions.DedupeOperationIDs.
+// Operations are visited by path, then method; the first one keeps its operationId, and the
+// next ones get the first free suffix starting at 2.
+func operationIDRenamings(swagger *openapi2.Swagger) []operationIDRenaming {
+	paths := make([]string, 0, len(swagger.Paths))
+	for path, pathItem := range swagger.Paths {
+		if pathItem != nil {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+	type operationKey struct{ path, method string }
+	var keys []operationKey
+	used := make(map[string]struct{})
+	for _, path := range paths {
+		operations := swagger.Paths[path].Operations()
+		methods := make([]string, 0, len(operations))
+		for method := range operations {
+			methods = append(methods, method)
+		}
+		sort.Strings(methods)
+		for _, method := range methods {
+			keys = append(keys, operationKey{path, method})
+			if id := operations[method].OperationID; id != "" {
+				used[id] = struct{}{}
+			}
+		}
+	}
+	var renamings []operationIDRenaming
+	seen := make(map[string]struct{})
+	for _, key := range keys {
+		base := swagger.Paths[key.path].GetOperation(key.method).OperationID
+		if base == "" {
+			continue
+		}
+		if _, ok := seen[base]; !ok {
+			seen[base] = struct{}{}
+			continue
+		}
+		id := base
+		for i := 2; ; i++ {
+			id = base + "_" + strconv.Itoa(i)
+			if _, ok := used[id]; !ok {
+				break
+			}
+		}
+		used[id] = struct{}{}
+		renamings = append(renamings, operationIDRenaming{path: key.path, method: key.method, from: base, id: id})
+	}
+	return renamings
+}
+
+var pointerEscaper = strings.NewReplacer("~", "~0", "/", "~1")
+
+func lossyToV3(swagger *openapi2.Swagger, opts ConvertOptions) []ConversionWarning {
+	var warnings []ConversionWarning
+	warn := func(pointer string, code string, format string, args ...interface{}) {
+		warnings = append(warnings, ConversionWarning{
+			Pointer: pointer,
+			Code:    code,
+			Message: fmt.Sprintf(format, args...),
+		})
+	}
+	checkParameter := func(pointer string, parameter *openapi2.Parameter, consumes []string) {
+		if parameter == nil || parameter.Ref != "" {
+			return
+		}
+		switch parameter.In {
+		case "body":
+			var dropped []string
+			for _, mediaType := range consumes {
+				if mediaType != "application/json" {
+					dropped = append(dropped, mediaType)
+				}
+			}
+			if len(dropped) > 0 {
+				warn(pointer, WarningBodyConsumes,
+					"Body parameter '%s' is converted for 'application/json' only, dropping %s",
+					parameter.Name, strings.Join(dropped, ", "))
+			}
+			return
+		}
+		if parameter.Type == "file" {
+			warn(pointer, WarningFileParameter,
+				"File parameter '%s' has no OpenAPI 3 parameter equivalent and is kept as a '%s' parameter",
+				parameter.Name, parameter.In)
+		}
+		if parameter.Type == "array" && parameter.CollectionFormat == "tsv" {
+			warn(pointer, WarningCollectionFormat,
+				"Collection format 'tsv' of parameter '%s' has no OpenAPI 3 style, the default style is used",
+				parameter.Name)
+		}
+	}
+
+	names := make([]string, 0, len(swagger.Parameters))
+	for name := range swagger.Parameters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		checkParameter("/parameters/"+pointerEscaper.Replace(name), swagger.Parameters[name], swagger.Consumes)
+	}
+
+	paths := make([]string, 0, len(swagger.Paths))
+	for path := range swagger.Paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		pathItem := swagger.Paths[path]
+		if pathItem == nil {
+			continue
+		}
+		pathPointer := "/paths/" + pointerEscaper.Replace(path)
+		for i, parameter := range pathItem.Parameters {
+			checkParameter(pathPointer+"/parameters/"+strconv.Itoa(i), parameter, swagger.Consumes)
+		}
+		for method, operation := range pathItem.Operations() {
+			consumes := operation.Consumes
+			if len(consumes) == 0 {
+				consumes = swagger.Consumes
+			}
+			operationPointer := pathPointer + "/" + strings.ToLower(method)
+			for i, parameter := range operation.Parameters {
+				checkParameter(operationPointer+"/parameters/"+strconv.Itoa(i), parameter, consumes)
+			}
+		}
+	}
+
+	if opts.DedupeOperationIDs {
+		for _, renaming := range operationIDRenamings(swagger) {
+			operationPointer := "/paths/" + pointerEscaper.Replace(renaming.path) + "/" + strings.ToLower(renaming.method)
+			warn(operationPointer+"/operationId", WarningOperationID,
+				"Duplicate operationId '%s' is renamed to '%s'", renaming.from, renaming.id)
+		}
+	}
+
+	if refErrs, err := danglingRefs(swagger); err == nil {
+		for _, refErr := range refErrs {
+			warn(refErr.Pointer, WarningDanglingRef, "Reference '%s' doesn't resolve within the document", refErr.Ref)
+		}
+	}
+
+	sort.SliceStable(warnings, func(i, j int) bool {
+		if warnings[i].Pointer != warnings[j].Pointer {
+			return warnings[i].Pointer < warnings[j].Pointer
+		}
+		return warnings[i].Code < warnings[j].Code
+	})
+	return warnings
+}
@@ -0,0 +1,212 @@
+package openapi2conv_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/mbilski/kin-openapi/openapi2"
+	"github.com/mbilski/kin-openapi/openapi2conv"
+	"github.com/mbilski/kin-openapi/openapi3"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToV3(t *testing.T) {
+	var swagger2 openapi2.Swagger
+	err := json.Unmarshal([]byte(`
+{
+  "info": {"title": "MyAPI", "version": "0.1"},
+  "consumes": ["application/json", "application/xml"],
+  "paths": {
+    "/pets": {
+      "get": {
+        "parameters": [
+          {"in": "query", "name": "tags", "type": "array", "items": {"type": "string"}, "collectionFormat": "tsv"},
+          {"in": "query", "name": "ids", "type": "array", "items": {"type": "integer"}, "collectionFormat": "pipes"},
+          {"in": "query", "name": "names", "type": "array", "items": {"type": "string"}}
+        ],
+        "responses": {"200": {"description": "list"}}
+      },
+      "post": {
+        "parameters": [
+          {"in": "body", "name": "pet", "schema": {"type": "object"}}
+        ],
+        "responses": {"201": {"description": "created"}}
+      }
+    },
+    "/pets/{id}/photo": {
+      "put": {
+        "consumes": ["multipart/form-data"],
+        "parameters": [
+          {"in": "path", "name": "id", "type": "string", "required": true},
+          {"in": "formData", "name": "photo", "type": "file"}
+        ],
+        "responses": {"204": {"description": "uploaded"}}
+      }
+    }
+  }
+}`), &swagger2)
+	require.NoError(t, err)
+
+	swagger3, warnings, err := openapi2conv.ToV3(&swagger2)
+	require.NoError(t, err)
+	require.NotNil(t, swagger3)
+	require.Equal(t, []openapi2conv.ConversionWarning{
+		{
+			Pointer: "/paths/~1pets/get/parameters/0",
+			Code:    openapi2conv.WarningCollectionFormat,
+			Message: "Collection format 'tsv' of parameter 'tags' has no OpenAPI 3 style, the default style is used",
+		},
+		{
+			Pointer: "/paths/~1pets/post/parameters/0",
+			Code:    openapi2conv.WarningBodyConsumes,
+			Message: "Body parameter 'pet' is converted for 'application/json' only, dropping application/xml",
+		},
+		{
+			Pointer: "/paths/~1pets~1{id}~1photo/put/parameters/1",
+			Code:    openapi2conv.WarningFileParameter,
+			Message: "File parameter 'photo' has no OpenAPI 3 parameter equivalent and is kept as a 'formData' parameter",
+		},
+	}, warnings)
+
+	ids := swagger3.Paths["/pets"].Get.Parameters[1].Value
+	require.Equal(t, "pipeDelimited", ids.Style)
+	require.False(t, *ids.Explode)
+
+	// A query array is "csv" by default, which isn't the exploded "form" of OpenAPI 3
+	names := swagger3.Paths["/pets"].Get.Parameters[2].Value
+	require.Equal(t, "form", names.Style)
+	require.False(t, *names.Explode)
+}
+
+func TestToV3DedupeOperationIDs(t *testing.T) {
+	var swagger2 openapi2.Swagger
+	err := json.Unmarshal([]byte(`
+{
+  "info": {"title": "MyAPI", "version": "0.1"},
+  "paths": {
+    "/users/{id}": {
+      "get": {"operationId": "getUser", "responses": {"200": {"description": "user"}}},
+      "put": {"operationId": "getUser", "responses": {"200": {"description": "user"}}}
+    },
+    "/v2/users/{id}": {
+      "get": {"operationId": "getUser", "responses": {"200": {"description": "user"}}}
+    },
+    "/v3/users/{id}": {
+      "get": {"operationId": "getUser_2", "responses": {"200": {"description": "user"}}}
+    }
+  }
+}`), &swagger2)
+	require.NoError(t, err)
+
+	swagger3, warnings, err := openapi2conv.ToV3(&swagger2)
+	require.NoError(t, err)
+	require.Empty(t, warnings)
+	require.Equal(t, "getUser", swagger3.Paths["/users/{id}"].Put.OperationID)
+
+	swagger3, warnings, err = openapi2conv.ToV3WithOptions(&swagger2, openapi2conv.ConvertOptions{DedupeOperationIDs: true})
+	require.NoError(t, err)
+	require.Equal(t, "getUser", swagger3.Paths["/users/{id}"].Get.OperationID)
+	require.Equal(t, "getUser_3", swagger3.Paths["/users/{id}"].Put.OperationID)
+	require.Equal(t, "getUser_4", swagger3.Paths["/v2/users/{id}"].Get.OperationID)
+	require.Equal(t, "getUser_2", swagger3.Paths["/v3/users/{id}"].Get.OperationID)
+	require.Equal(t, "getUser", swagger2.Paths["/users/{id}"].Put.OperationID)
+	require.Equal(t, []openapi2conv.ConversionWarning{
+		{
+			Pointer: "/paths/~1users~1{id}/put/operationId",
+			Code:    openapi2conv.WarningOperationID,
+			Message: "Duplicate operationId 'getUser' is renamed to 'getUser_3'",
+		},
+		{
+			Pointer: "/paths/~1v2~1users~1{id}/get/operationId",
+			Code:    openapi2conv.WarningOperationID,
+			Message: "Duplicate operationId 'getUser' is renamed to 'getUser_4'",
+		},
+	}, warnings)
+}
+
+func TestToV3StrictRefs(t *testing.T) {
+	const spec = `
+{
+  "info": {"title": "MyAPI", "version": "0.1"},
+  "paths": {
+    "/pets": {
+      "get": {
+        "parameters": [{"$ref": "#/parameters/limit"}],
+        "responses": {
+          "200": {"description": "list", "schema": {"type": "array", "items": {"$ref": "#/definitions/Pet"}}}
+        }
+      }
+    }
+  },
+  "parameters": {
+    "limit": {"in": "query", "name": "limit", "type": "integer"}
+  },
+  "definitions": {
+    "Pet": {"type": "object", "properties": {"owner": {"$ref": "#/definitions/Owner"}}}
+  }
+}`
+	// Converting rewrites the schemas, so each conversion gets its own document
+	load := func() *openapi2.Swagger {
+		var swagger2 openapi2.Swagger
+		require.NoError(t, json.Unmarshal([]byte(spec), &swagger2))
+		return &swagger2
+	}
+
+	swagger3, warnings, err := openapi2conv.ToV3(load())
+	require.NoError(t, err)
+	require.NotNil(t, swagger3)
+	require.Equal(t, []openapi2conv.ConversionWarning{{
+		Pointer: "/definitions/Pet/properties/owner",
+		Code:    openapi2conv.WarningDanglingRef,
+		Message: "Reference '#/definitions/Owner' doesn't resolve within the document",
+	}}, warnings)
+
+	_, _, err = openapi2conv.ToV3WithOptions(load(), openapi2conv.ConvertOptions{StrictRefs: true})
+	require.Equal(t, &openapi2conv.RefError{
+		Pointer: "/definitions/Pet/properties/owner",
+		Ref:     "#/definitions/Owner",
+	}, err)
+	require.EqualError(t, err, "/definitions/Pet/properties/owner: Failed to resolve ref: '#/definitions/Owner'")
+
+	swagger2 := load()
+	swagger2.Definitions["Owner"] = &openapi3.SchemaRef{Value: openapi3.NewObjectSchema()}
+	swagger3, err = openapi2conv.ToV3SwaggerWithOptions(swagger2, openapi2conv.ConvertOptions{StrictRefs: true})
+	require.NoError(t, err)
+	require.NotNil(t, swagger3)
+}
+
+func TestToV3KeepsSwagger(t *testing.T) {
+	spec := []byte(`
+{
+  "swagger": "2.0",
+  "info": {"title": "MyAPI", "version": "0.1"},
+  "paths": {
+    "/pets": {
+      "post": {
+        "parameters": [
+          {"in": "body", "name": "pet", "schema": {"type": "object", "properties": {"owner": {"$ref": "#/definitions/Owner"}}}}
+        ],
+        "responses": {"200": {"description": "OK", "schema": {"type": "array", "items": {"$ref": "#/definitions/Pet"}}}}
+      }
+    }
+  },
+  "definitions": {
+    "Owner": {"type": "object", "properties": {"pets": {"type": "array", "items": {"$ref": "#/definitions/Pet"}}}},
+    "Pet": {"type": "object", "required": ["name", "name"], "allOf": [{"$ref": "#/definitions/Owner"}], "discriminator": "kind"}
+  }
+}`)
+	var swagger2 openapi2.Swagger
+	require.NoError(t, json.Unmarshal(spec, &swagger2))
+	before, err := json.Marshal(&swagger2)
+	require.NoError(t, err)
+
+	swagger3, _, err := openapi2conv.ToV3(&swagger2)
+	require.NoError(t, err)
+	require.Equal(t, "#/components/schemas/Pet", swagger3.Components.Schemas["Owner"].Value.Properties["pets"].Value.Items.Ref)
+	require.Equal(t, []string{"name"}, swagger3.Components.Schemas["Pet"].Value.Required)
+
+	after, err := json.Marshal(&swagger2)
+	require.NoError(t, err)
+	require.JSONEq(t, string(before), string(after))
+	require.Equal(t, "#/definitions/Pet", swagger2.Definitions["Owner"].Value.Properties["pets"].Value.Items.Ref)
+}
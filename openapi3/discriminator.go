@@ -1,23 +1,42 @@
 package openapi3
 
 import (
+	"bytes"
 	"context"
 
 	"github.com/mbilski/kin-openapi/jsoninfo"
 )
 
 // Discriminator is specified by OpenAPI/Swagger standard version 3.0.
+//
+// OpenAPI 2 declares a discriminator as the property name alone, such as "discriminator": "petType".
+// That form is also accepted, and written back as is unless a mapping or extensions were added.
 type Discriminator struct {
 	ExtensionProps
 	PropertyName string            `json:"propertyName" yaml:"propertyName"`
 	Mapping      map[string]string `json:"mapping,omitempty" yaml:"mapping,omitempty"`
+
+	// propertyNameOnly is set when decoded from the OpenAPI 2 form
+	propertyNameOnly bool
 }
 
 func (value *Discriminator) MarshalJSON() ([]byte, error) {
 	return jsoninfo.MarshalStrictStruct(value)
 }
 
+// EncodeWith writes the OpenAPI 2 form back as a string.
+func (value *Discriminator) EncodeWith(encoder *jsoninfo.ObjectEncoder, v interface{}) error {
+	if value.propertyNameOnly && len(value.Mapping) == 0 && len(value.Extensions) == 0 {
+		return encoder.EncodeValue(value.PropertyName)
+	}
+	return value.ExtensionProps.EncodeWith(encoder, v)
+}
+
 func (value *Discriminator) UnmarshalJSON(data []byte) error {
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '"' {
+		*value = Discriminator{propertyNameOnly: true}
+		return jsoninfo.CurrentJSONLibrary().Unmarshal(trimmed, &value.PropertyName)
+	}
 	return jsoninfo.UnmarshalStrictStruct(data, value)
 }
 
@@ -8,6 +8,11 @@ import (
 // It reads/writes all properties that begin with "x-".
 type ExtensionProps struct {
 	Extensions map[string]interface{} `json:"-" yaml:"-"`
+
+	// FieldOrder is the order of the keys in the source document.
+	// It is only recorded by jsoninfo.UnmarshalWithOptions with DecoderOptions.PreserveFieldOrder,
+	// and only honored by jsoninfo.MarshalWithOptions with EncoderOptions.PreserveFieldOrder.
+	FieldOrder []string `json:"-" yaml:"-"`
 }
 
 // Assert that the type implements the interface
@@ -15,6 +20,7 @@ var _ jsoninfo.StrictStruct = &ExtensionProps{}
 
 // EncodeWith will be invoked by package "jsoninfo"
 func (props *ExtensionProps) EncodeWith(encoder *jsoninfo.ObjectEncoder, value interface{}) error {
+	encoder.EncodeFieldOrder(props.FieldOrder)
 	for k, v := range props.Extensions {
 		if err := encoder.EncodeExtension(k, v); err != nil {
 			return err
@@ -34,5 +40,6 @@ func (props *ExtensionProps) DecodeWith(decoder *jsoninfo.ObjectDecoder, value i
 		result[k] = v
 	}
 	props.Extensions = result
+	props.FieldOrder = decoder.DecodeFieldOrder()
 	return nil
 }
@@ -19,6 +19,10 @@ func (value *CallbackRef) UnmarshalJSON(data []byte) error {
 	return jsoninfo.UnmarshalRef(data, &value.Ref, &value.Value)
 }
 
+func (value *CallbackRef) RefFields() (*string, interface{}) {
+	return &value.Ref, &value.Value
+}
+
 func (value *CallbackRef) Validate(c context.Context) error {
 	v := value.Value
 	if v == nil {
@@ -40,6 +44,10 @@ func (value *ExampleRef) UnmarshalJSON(data []byte) error {
 	return jsoninfo.UnmarshalRef(data, &value.Ref, &value.Value)
 }
 
+func (value *ExampleRef) RefFields() (*string, interface{}) {
+	return &value.Ref, &value.Value
+}
+
 func (value *ExampleRef) Validate(c context.Context) error {
 	return nil
 }
@@ -57,6 +65,10 @@ func (value *HeaderRef) UnmarshalJSON(data []byte) error {
 	return jsoninfo.UnmarshalRef(data, &value.Ref, &value.Value)
 }
 
+func (value *HeaderRef) RefFields() (*string, interface{}) {
+	return &value.Ref, &value.Value
+}
+
 func (value *HeaderRef) Validate(c context.Context) error {
 	v := value.Value
 	if v == nil {
@@ -78,6 +90,10 @@ func (value *LinkRef) UnmarshalJSON(data []byte) error {
 	return jsoninfo.UnmarshalRef(data, &value.Ref, &value.Value)
 }
 
+func (value *LinkRef) RefFields() (*string, interface{}) {
+	return &value.Ref, &value.Value
+}
+
 func (value *LinkRef) Validate(c context.Context) error {
 	v := value.Value
 	if v == nil {
@@ -99,6 +115,10 @@ func (value *ParameterRef) UnmarshalJSON(data []byte) error {
 	return jsoninfo.UnmarshalRef(data, &value.Ref, &value.Value)
 }
 
+func (value *ParameterRef) RefFields() (*string, interface{}) {
+	return &value.Ref, &value.Value
+}
+
 func (value *ParameterRef) Validate(c context.Context) error {
 	v := value.Value
 	if v == nil {
@@ -120,6 +140,10 @@ func (value *ResponseRef) UnmarshalJSON(data []byte) error {
 	return jsoninfo.UnmarshalRef(data, &value.Ref, &value.Value)
 }
 
+func (value *ResponseRef) RefFields() (*string, interface{}) {
+	return &value.Ref, &value.Value
+}
+
 func (value *ResponseRef) Validate(c context.Context) error {
 	v := value.Value
 	if v == nil {
@@ -141,6 +165,10 @@ func (value *RequestBodyRef) UnmarshalJSON(data []byte) error {
 	return jsoninfo.UnmarshalRef(data, &value.Ref, &value.Value)
 }
 
+func (value *RequestBodyRef) RefFields() (*string, interface{}) {
+	return &value.Ref, &value.Value
+}
+
 func (value *RequestBodyRef) Validate(c context.Context) error {
 	v := value.Value
 	if v == nil {
@@ -169,6 +197,10 @@ func (value *SchemaRef) UnmarshalJSON(data []byte) error {
 	return jsoninfo.UnmarshalRef(data, &value.Ref, &value.Value)
 }
 
+func (value *SchemaRef) RefFields() (*string, interface{}) {
+	return &value.Ref, &value.Value
+}
+
 func (value *SchemaRef) Validate(c context.Context) error {
 	v := value.Value
 	if v == nil {
@@ -190,6 +222,10 @@ func (value *SecuritySchemeRef) UnmarshalJSON(data []byte) error {
 	return jsoninfo.UnmarshalRef(data, &value.Ref, &value.Value)
 }
 
+func (value *SecuritySchemeRef) RefFields() (*string, interface{}) {
+	return &value.Ref, &value.Value
+}
+
 func (value *SecuritySchemeRef) Validate(c context.Context) error {
 	v := value.Value
 	if v == nil {
@@ -10,7 +10,7 @@ import (
 	"math/big"
 	"regexp"
 	"strconv"
-	"unicode/utf16"
+	"unicode/utf8"
 
 	"github.com/mbilski/kin-openapi/jsoninfo"
 )
@@ -228,6 +228,70 @@ type compiledPattern struct {
 	ErrReason string
 }
 
+// CompilePatterns compiles the regular expressions of "pattern", "format" and "patternProperties",
+// which VisitJSON otherwise compiles and stores in the schema when it first needs them.
+// Compile a schema before validating values against it concurrently.
+// The schemas nested in it aren't compiled.
+func (schema *Schema) CompilePatterns() error {
+	if _, err := schema.stringPattern(); err != nil {
+		return err
+	}
+	_, err := schema.propertiesPattern()
+	return err
+}
+
+// stringPattern returns the regular expression of "pattern", or else of "format", compiling it the first time.
+func (schema *Schema) stringPattern() (*compiledPattern, error) {
+	cp := schema.compiledPattern
+	if cp == nil {
+		pattern := schema.Pattern
+		if v := schema.Pattern; len(v) > 0 {
+			// Pattern
+			re, err := regexp.Compile(v)
+			if err != nil {
+				return nil, fmt.Errorf("Error while compiling regular expression '%s': %v", pattern, err)
+			}
+			cp = &compiledPattern{
+				Regexp:    re,
+				ErrReason: "JSON string doesn't match the regular expression '" + v + "'",
+			}
+			schema.compiledPattern = cp
+		} else if v := schema.Format; len(v) > 0 {
+			// No pattern, but does have a format
+			re := SchemaStringFormats[v]
+			if re != nil {
+				cp = &compiledPattern{
+					Regexp:    re,
+					ErrReason: "JSON string doesn't match the format '" + v + " (regular expression `" + re.String() + "`)'",
+				}
+				schema.compiledPattern = cp
+			}
+		}
+	}
+	return cp, nil
+}
+
+// propertiesPattern returns the regular expression of "patternProperties", compiling it the first time.
+func (schema *Schema) propertiesPattern() (*compiledPattern, error) {
+	var cp *compiledPattern
+	patternProperties := schema.PatternProperties
+	if len(patternProperties) > 0 {
+		cp = schema.compiledPatternProperties
+		if cp == nil {
+			re, err := regexp.Compile(patternProperties)
+			if err != nil {
+				return nil, fmt.Errorf("Error while compiling regular expression '%s': %v", patternProperties, err)
+			}
+			cp = &compiledPattern{
+				Regexp:    re,
+				ErrReason: "JSON property doesn't match the regular expression '" + patternProperties + "'",
+			}
+			schema.compiledPatternProperties = cp
+		}
+	}
+	return cp, nil
+}
+
 func (schema *Schema) WithNullable() *Schema {
 	schema.Nullable = true
 	return schema
@@ -869,15 +933,8 @@ func (schema *Schema) visitJSONString(value string, fast bool) (err error) {
 	minLength := schema.MinLength
 	maxLength := schema.MaxLength
 	if minLength != 0 || maxLength != nil {
-		// JSON schema string lengths are UTF-16, not UTF-8!
-		length := int64(0)
-		for _, r := range value {
-			if utf16.IsSurrogate(r) {
-				length += 2
-			} else {
-				length++
-			}
-		}
+		// JSON schema string lengths are counted in Unicode code points, not bytes
+		length := int64(utf8.RuneCountInString(value))
 		if minLength != 0 && length < int64(minLength) {
 			if fast {
 				return errSchema
@@ -903,31 +960,9 @@ func (schema *Schema) visitJSONString(value string, fast bool) (err error) {
 	}
 
 	// "format" and "pattern"
-	cp := schema.compiledPattern
-	if cp == nil {
-		pattern := schema.Pattern
-		if v := schema.Pattern; len(v) > 0 {
-			// Pattern
-			re, err := regexp.Compile(v)
-			if err != nil {
-				return fmt.Errorf("Error while compiling regular expression '%s': %v", pattern, err)
-			}
-			cp = &compiledPattern{
-				Regexp:    re,
-				ErrReason: "JSON string doesn't match the regular expression '" + v + "'",
-			}
-			schema.compiledPattern = cp
-		} else if v := schema.Format; len(v) > 0 {
-			// No pattern, but does have a format
-			re := SchemaStringFormats[v]
-			if re != nil {
-				cp = &compiledPattern{
-					Regexp:    re,
-					ErrReason: "JSON string doesn't match the format '" + v + " (regular expression `" + re.String() + "`)'",
-				}
-				schema.compiledPattern = cp
-			}
-		}
+	cp, err := schema.stringPattern()
+	if err != nil {
+		return err
 	}
 	if cp != nil {
 		if !cp.Regexp.MatchString(value) {
@@ -1051,21 +1086,9 @@ func (schema *Schema) visitJSONObject(value map[string]interface{}, fast bool) (
 	}
 
 	// "patternProperties"
-	var cp *compiledPattern
-	patternProperties := schema.PatternProperties
-	if len(patternProperties) > 0 {
-		cp = schema.compiledPatternProperties
-		if cp == nil {
-			re, err := regexp.Compile(patternProperties)
-			if err != nil {
-				return fmt.Errorf("Error while compiling regular expression '%s': %v", patternProperties, err)
-			}
-			cp = &compiledPattern{
-				Regexp:    re,
-				ErrReason: "JSON property doesn't match the regular expression '" + patternProperties + "'",
-			}
-			schema.compiledPatternProperties = cp
-		}
+	cp, err := schema.propertiesPattern()
+	if err != nil {
+		return err
 	}
 
 	// "additionalProperties"
@@ -263,6 +263,26 @@ var schemaExamples = []schemaExample{
 		},
 	},
 
+	{
+		Title: "STRING: length in code points",
+		Schema: openapi3.NewStringSchema().
+			WithMinLength(2).
+			WithMaxLength(3),
+		Serialization: map[string]interface{}{
+			"type":      "string",
+			"minLength": 2,
+			"maxLength": 3,
+		},
+		AllValid: []interface{}{
+			"éé",
+			"🐶🐱🐭",
+		},
+		AllInvalid: []interface{}{
+			"🐶",
+			"🐶🐱🐭🐹",
+		},
+	},
+
 	{
 		Title:  "STRING: optional format 'uuid'",
 		Schema: openapi3.NewUUIDSchema(),